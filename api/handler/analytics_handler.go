@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/repository"
+)
+
+// Longest range the analytics endpoint returns at each granularity
+var maxAnalyticsRange = map[analytics.Granularity]time.Duration{
+	analytics.Hourly: 31 * 24 * time.Hour,
+	analytics.Daily:  366 * 24 * time.Hour,
+}
+
+// AnalyticsHandler reports activity on an author's posts over time
+type AnalyticsHandler struct {
+	repo     repository.IAnalyticsRepository
+	blogRepo repository.IBlogRepository
+}
+
+func NewAnalyticsHandler(repo repository.IAnalyticsRepository, blogRepo repository.IBlogRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{repo: repo, blogRepo: blogRepo}
+}
+
+// GetAuthorAnalytics godoc
+// @Summary Get analytics for my posts
+// @Description Returns views, unique readers, new likes, published comments and shares across the logged-in author's posts as a time series, with totals, views by referring site, shares by platform and the top posts for the range. Hourly data is kept for 90 days.
+// @Tags Analytics
+// @Produce json
+// @Param id path string true "Author ID"
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive when a plain date (YYYY-MM-DD or RFC 3339), default now"
+// @Param granularity query string false "hour or day" default(day)
+// @Param top query string false "Metric to rank top posts by (views, readers, likes, comments, shares)" default(views)
+// @Param limit query int false "Number of top posts" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /authors/{id}/analytics [get]
+func (h *AnalyticsHandler) GetAuthorAnalytics(c *gin.Context) {
+	authorID, ok := selfFromParam(c)
+	if !ok {
+		return
+	}
+
+	g := analytics.Granularity(c.DefaultQuery("granularity", string(analytics.Daily)))
+	if !g.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be hour or day"})
+		return
+	}
+	from, to, err := analyticsRange(c, g, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	by := analytics.Metric(c.DefaultQuery("top", string(analytics.MetricViews)))
+	if !by.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "top must be views, readers, likes, comments or shares"})
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	ctx := context.Background()
+	posts, err := h.blogRepo.ListAllByAuthor(ctx, authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch posts"})
+		return
+	}
+	byID := make(map[primitive.ObjectID]*blog.Blog, len(posts))
+	ids := make([]primitive.ObjectID, 0, len(posts))
+	for _, b := range posts {
+		byID[b.ID] = b
+		ids = append(ids, b.ID)
+	}
+
+	points, err := h.repo.Series(ctx, ids, g, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch analytics"})
+		return
+	}
+	referrers, err := h.repo.Breakdown(ctx, ids, g, from, to, "referrers")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch analytics"})
+		return
+	}
+	platforms, err := h.repo.Breakdown(ctx, ids, g, from, to, "platforms")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch analytics"})
+		return
+	}
+	top, err := h.repo.TopPosts(ctx, ids, g, from, to, by, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch analytics"})
+		return
+	}
+
+	series, totals := fillSeries(points, g, from, to)
+	topPosts := make([]gin.H, 0, len(top))
+	for _, t := range top {
+		if b, ok := byID[t.BlogID]; ok {
+			topPosts = append(topPosts, gin.H{"blog": blogSummary(b), "counts": t.Counts})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":        from,
+		"to":          to,
+		"granularity": g,
+		"totals":      totals,
+		"series":      series,
+		"referrers":   referrers,
+		"platforms":   platforms,
+		"top_posts":   topPosts,
+	})
+}
+
+// analyticsRange reads the from and to query parameters as a half-open
+// range aligned to bucket boundaries
+func analyticsRange(c *gin.Context, g analytics.Granularity, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if v := c.Query("to"); v != "" {
+		t, dateOnly, err := parseAnalyticsTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		to = t
+		if dateOnly {
+			to = t.Add(24 * time.Hour)
+		}
+	}
+	to = g.Start(to.Add(g.Duration() - time.Nanosecond))
+
+	from := to.Add(-30 * 24 * time.Hour)
+	if g == analytics.Hourly {
+		from = to.Add(-24 * time.Hour)
+	}
+	if v := c.Query("from"); v != "" {
+		t, _, err := parseAnalyticsTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		from = t
+	}
+	from = g.Start(from)
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxAnalyticsRange[g] {
+		return time.Time{}, time.Time{}, fmt.Errorf("range is too long for %sly data", g)
+	}
+	return from, to, nil
+}
+
+// parseAnalyticsTime accepts a plain date or an RFC 3339 timestamp,
+// reporting which it was
+func parseAnalyticsTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
+
+// fillSeries lays the points out on every bucket in [from, to), with zero
+// counts where nothing happened, and adds them up
+func fillSeries(points []analytics.Point, g analytics.Granularity, from, to time.Time) ([]analytics.Point, analytics.Counts) {
+	byStart := make(map[time.Time]analytics.Counts, len(points))
+	for _, p := range points {
+		byStart[p.Start.UTC()] = p.Counts
+	}
+
+	var totals analytics.Counts
+	series := []analytics.Point{}
+	for t := from; t.Before(to); t = t.Add(g.Duration()) {
+		counts := byStart[t]
+		totals.Add(counts)
+		series = append(series, analytics.Point{Start: t, Counts: counts})
+	}
+	return series, totals
+}
+
+// fillLabelSeries is fillSeries for per-label breakdowns
+func fillLabelSeries(points []analytics.LabelPoint, g analytics.Granularity, from, to time.Time) ([]analytics.LabelPoint, map[string]int) {
+	byStart := make(map[time.Time]map[string]int, len(points))
+	for _, p := range points {
+		byStart[p.Start.UTC()] = p.Counts
+	}
+
+	totals := map[string]int{}
+	series := []analytics.LabelPoint{}
+	for t := from; t.Before(to); t = t.Add(g.Duration()) {
+		counts := byStart[t]
+		if counts == nil {
+			counts = map[string]int{}
+		}
+		for label, n := range counts {
+			totals[label] += n
+		}
+		series = append(series, analytics.LabelPoint{Start: t, Counts: counts})
+	}
+	return series, totals
+}
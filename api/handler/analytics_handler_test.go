@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/readers"
+)
+
+func TestGetAuthorAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authorID := primitive.NewObjectID()
+
+	send := func(h *AnalyticsHandler, callerID primitive.ObjectID, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", callerID.Hex()) })
+		r.GET("/authors/:id/analytics", h.GetAuthorAnalytics)
+
+		req, _ := http.NewRequest("GET", "/authors/"+authorID.Hex()+"/analytics"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Daily series covers every day in the range", func(t *testing.T) {
+		mRepo, mBlog := new(MockAnalyticsRepo), new(MockBlogRepo)
+		post := &blog.Blog{ID: primitive.NewObjectID(), Title: "Popular"}
+		ids := []primitive.ObjectID{post.ID}
+		from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+		mBlog.On("ListAllByAuthor", mock.Anything, authorID).Return([]*blog.Blog{post}, nil)
+		mRepo.On("Series", mock.Anything, ids, analytics.Daily, from, to).Return([]analytics.Point{
+			{Start: from.Add(24 * time.Hour), Counts: analytics.Counts{Views: 5, Readers: 3, Shares: 1}},
+		}, nil)
+		mRepo.On("Breakdown", mock.Anything, ids, analytics.Daily, from, to, "referrers").Return(map[string]int{"news.ycombinator.com": 5}, nil)
+		mRepo.On("Breakdown", mock.Anything, ids, analytics.Daily, from, to, "platforms").Return(map[string]int{"mastodon": 1}, nil)
+		mRepo.On("TopPosts", mock.Anything, ids, analytics.Daily, from, to, analytics.MetricReaders, int64(10)).
+			Return([]analytics.PostTotals{{BlogID: post.ID, Counts: analytics.Counts{Views: 5, Readers: 3}}}, nil)
+
+		w := send(NewAnalyticsHandler(mRepo, mBlog), authorID, "?from=2026-03-01&to=2026-03-03&top=readers")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Totals    analytics.Counts  `json:"totals"`
+			Series    []analytics.Point `json:"series"`
+			Referrers map[string]int    `json:"referrers"`
+			TopPosts  []struct {
+				Blog struct{ Title string } `json:"blog"`
+			} `json:"top_posts"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Len(t, resp.Series, 3)
+		assert.Equal(t, 0, resp.Series[0].Views)
+		assert.Equal(t, 5, resp.Series[1].Views)
+		assert.Equal(t, analytics.Counts{Views: 5, Readers: 3, Shares: 1}, resp.Totals)
+		assert.Equal(t, 5, resp.Referrers["news.ycombinator.com"])
+		if assert.Len(t, resp.TopPosts, 1) {
+			assert.Equal(t, "Popular", resp.TopPosts[0].Blog.Title)
+		}
+	})
+
+	t.Run("Invalid ranges are rejected", func(t *testing.T) {
+		h := NewAnalyticsHandler(new(MockAnalyticsRepo), new(MockBlogRepo))
+		for _, q := range []string{
+			"?from=2026-03-05&to=2026-03-01",
+			"?from=yesterday",
+			"?granularity=minute",
+			"?granularity=hour&from=2026-01-01&to=2026-03-01",
+			"?top=bounces",
+		} {
+			assert.Equal(t, http.StatusBadRequest, send(h, authorID, q).Code, q)
+		}
+	})
+
+	t.Run("Authors only see their own analytics", func(t *testing.T) {
+		h := NewAnalyticsHandler(new(MockAnalyticsRepo), new(MockBlogRepo))
+		assert.Equal(t, http.StatusForbidden, send(h, primitive.NewObjectID(), "").Code)
+	})
+}
+
+func TestGetBlog_RecordsAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	b := &blog.Blog{ID: primitive.NewObjectID(), AuthorID: primitive.NewObjectID()}
+
+	mBlog, mAuth, mRepo := new(MockBlogRepo), new(MockAuthorRepo), new(MockAnalyticsRepo)
+	mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+	mBlog.On("CountView", mock.Anything, b.ID, true).Return(nil)
+	mAuth.On("GetAuthorByID", mock.Anything).Return(&author.Author{Name: "Ada"}, nil)
+	mRepo.On("Record", mock.Anything, mock.MatchedBy(func(e analytics.Event) bool {
+		return e.BlogID == b.ID && e.Counts == analytics.Counts{Views: 1, Readers: 1} && e.Referrer == "news.ycombinator.com"
+	})).Return(nil)
+
+	counter := readers.NewCounter(readers.Settings{Salt: "test"}, visitSet{})
+	h := NewBlogHandler(mBlog, mAuth, BlogDeps{Counter: counter, Analytics: mRepo})
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.GET("/blogs/:id", h.GetBlog)
+	req, _ := http.NewRequest("GET", "/blogs/"+b.ID.Hex()+"?ref=https://news.ycombinator.com/item?id=1", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 Firefox/128.0")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mRepo.AssertExpectations(t)
+}
@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
+	"razorblog-backend/internal/models/audit"
 	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/export"
 	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -18,12 +25,16 @@ var jwtSecret = []byte("supersecretkey")
 
 // AuthorHandler holds repository reference
 type AuthorHandler struct {
-    Repo repository.IAuthorRepository // Change this to the Interface
+    Repo     repository.IAuthorRepository // Change this to the Interface
+    Deleter  service.IAuthorDeletionService
+    Audit    repository.IAuditRepository
+    Exporter service.IDataExportService
+    Mentions service.IMentionService
 }
 
 // NewAuthorHandler creates a new AuthorHandler
-func NewAuthorHandler(repo repository.IAuthorRepository) *AuthorHandler { // Change this too
-    return &AuthorHandler{Repo: repo}
+func NewAuthorHandler(repo repository.IAuthorRepository, deleter service.IAuthorDeletionService, auditRepo repository.IAuditRepository, exporter service.IDataExportService, mentions service.IMentionService) *AuthorHandler { // Change this too
+    return &AuthorHandler{Repo: repo, Deleter: deleter, Audit: auditRepo, Exporter: exporter, Mentions: mentions}
 }
 
 // RegisterAuthor godoc
@@ -95,6 +106,7 @@ func (h *AuthorHandler) LoginAuthor(c *gin.Context) {
     }
 
     if err := bcrypt.CompareHashAndPassword([]byte(authorObj.Password), []byte(req.Password)); err != nil {
+        h.recordAudit(c, authorObj.ID, audit.ActionLoginFailed)
         c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid credentials"})
         return
     }
@@ -112,6 +124,8 @@ func (h *AuthorHandler) LoginAuthor(c *gin.Context) {
         return
     }
 
+    h.recordAudit(c, authorObj.ID, audit.ActionLogin)
+
     // Return the role in the response for immediate frontend use (badges/UI)
     c.JSON(http.StatusOK, map[string]interface{}{
         "token":    tokenString,
@@ -164,6 +178,8 @@ func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
 	delete(update, "_id")
 	delete(update, "id")
 	delete(update, "email") // Usually, email updates should have a separate verified flow
+	delete(update, "follower_count")
+	delete(update, "following_count")
 	// ----------------------------------------------
 
 	if pwd, ok := update["password"].(string); ok && pwd != "" {
@@ -182,10 +198,20 @@ func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
 		return
 	}
 
+	// Mentions link by ID; refresh the name they display
+	if name, ok := update["name"].(string); ok && name != "" && h.Mentions != nil {
+		if err := h.Mentions.Rename(context.Background(), objID, name); err != nil {
+			log.Printf("⚠️ Failed to update mentions of author %s: %v", objID.Hex(), err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "author updated"})
 }
 
 // DeleteAuthor godoc
+// Deletes the logged-in author's own account. The mode query parameter
+// decides what happens to their posts: transfer (to transfer_to),
+// anonymize (default) or delete. Likes and co-author credits are removed.
 func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
 	idParam := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(idParam)
@@ -194,16 +220,49 @@ func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
 		return
 	}
 
-	if err := h.Repo.DeleteAuthor(objID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	if callerID != objID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only delete your own account"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "author deleted"})
+	opts := service.DeletionOptions{
+		Mode: service.DeletionMode(c.DefaultQuery("mode", string(service.DeletionAnonymize))),
+	}
+	if transferTo := c.Query("transfer_to"); transferTo != "" {
+		if opts.TransferTo, err = primitive.ObjectIDFromHex(transferTo); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer_to id"})
+			return
+		}
+	}
+
+	report, err := h.Deleter.DeleteAuthor(context.Background(), objID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidDeletionMode), errors.Is(err, service.ErrInvalidTransferTarget):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAuthorNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if h.Mentions != nil {
+		if err := h.Mentions.Forget(context.Background(), objID); err != nil {
+			log.Printf("⚠️ Failed to unlink mentions of author %s: %v", objID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "author deleted", "report": report})
 }
 
 // GetPublicAuthor godoc
-// Public profile: only image, name, bio
+// Public profile: only image, name, bio and follower/following counts
 func (h *AuthorHandler) GetPublicAuthor(c *gin.Context) {
 	idParam := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(idParam)
@@ -227,3 +286,139 @@ func (h *AuthorHandler) GetPublicAuthor(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"author": authorObj})
 }
 
+
+// ExportAuthorData godoc
+// Packages everything stored about the logged-in author as a ZIP download.
+// Large accounts (or ?async=true) get a background job instead: the response
+// is 202 with the job, to be polled via GetExportJob.
+func (h *AuthorHandler) ExportAuthorData(c *gin.Context) {
+	objID, ok := selfFromParam(c)
+	if !ok {
+		return
+	}
+
+	h.recordAudit(c, objID, audit.ActionDataExport)
+
+	large, err := h.Exporter.IsLarge(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if large || c.Query("async") == "true" {
+		job, err := h.Exporter.StartJob(context.Background(), objID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"job": job})
+		return
+	}
+
+	bundle, err := h.Exporter.Collect(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := service.WriteZip(&buf, bundle); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build export"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+service.ExportFilename(objID)+`"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// GetExportJob godoc
+// Returns the status of one of the logged-in author's export jobs
+func (h *AuthorHandler) GetExportJob(c *gin.Context) {
+	job, ok := h.exportJobFromParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// DownloadExportJob godoc
+// Streams the ZIP archive of a completed export job
+func (h *AuthorHandler) DownloadExportJob(c *gin.Context) {
+	job, ok := h.exportJobFromParam(c)
+	if !ok {
+		return
+	}
+
+	if job.Status != export.JobCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": service.ErrExportNotReady.Error(), "status": job.Status})
+		return
+	}
+
+	// The archive is streamed from GridFS; once the first bytes are out
+	// a failure can only cut the download short
+	c.Header("Content-Disposition", `attachment; filename="`+service.ExportFilename(job.AuthorID)+`"`)
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := h.Exporter.DownloadJob(context.Background(), job, c.Writer); err != nil {
+		log.Printf("⚠️ Data export %s download failed: %v", job.ID.Hex(), err)
+	}
+}
+
+func (h *AuthorHandler) exportJobFromParam(c *gin.Context) (*export.Job, bool) {
+	objID, ok := selfFromParam(c)
+	if !ok {
+		return nil, false
+	}
+
+	jobID, err := primitive.ObjectIDFromHex(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return nil, false
+	}
+
+	job, err := h.Exporter.GetJob(context.Background(), jobID, objID)
+	if err != nil {
+		if errors.Is(err, service.ErrExportJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return job, true
+}
+
+// selfFromParam parses the :id parameter and checks it is the logged-in author
+func selfFromParam(c *gin.Context) (primitive.ObjectID, bool) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return primitive.NilObjectID, false
+	}
+
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+	if callerID != objID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only access your own data"})
+		return primitive.NilObjectID, false
+	}
+	return objID, true
+}
+
+// recordAudit logs an account event; failures never block the request
+func (h *AuthorHandler) recordAudit(c *gin.Context, actorID primitive.ObjectID, action audit.Action) {
+	err := h.Audit.Record(context.Background(), &audit.Event{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: "author",
+		TargetID:   &actorID,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Printf("⚠️ Failed to record audit event %s: %v", action, err)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/export"
+	"razorblog-backend/internal/service"
 )
 
 func TestAuthor_Security(t *testing.T) {
@@ -20,7 +22,7 @@ func TestAuthor_Security(t *testing.T) {
 
 t.Run("FORCE GUEST: Registration ignores role in JSON", func(t *testing.T) {
     mAuth := new(MockAuthorRepo)
-    h := NewAuthorHandler(mAuth)
+    h := NewAuthorHandler(mAuth, new(MockAuthorDeleter), new(MockAuditRepo), nil, nil)
 
     var capturedAuthor *author.Author
     // Ensure we return an empty author struct on success so pointers aren't nil
@@ -60,7 +62,7 @@ t.Run("FORCE GUEST: Registration ignores role in JSON", func(t *testing.T) {
 })	
 	t.Run("PROTECT UPDATE: User cannot inject role field", func(t *testing.T) {
 		mAuth := new(MockAuthorRepo)
-		h := NewAuthorHandler(mAuth)
+		h := NewAuthorHandler(mAuth, new(MockAuthorDeleter), new(MockAuditRepo), nil, nil)
 
 		userID := primitive.NewObjectID()
 		var capturedUpdate bson.M
@@ -82,3 +84,131 @@ t.Run("FORCE GUEST: Registration ignores role in JSON", func(t *testing.T) {
 		assert.False(t, roleExists)
 	})
 }
+
+func TestDeleteAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(h *AuthorHandler, callerID primitive.ObjectID) *gin.Engine {
+		r := gin.New()
+		r.DELETE("/authors/:id", func(ctx *gin.Context) {
+			ctx.Set("author_id", callerID.Hex())
+			h.DeleteAuthor(ctx)
+		})
+		return r
+	}
+
+	t.Run("REJECT: Author cannot delete someone else", func(t *testing.T) {
+		mDel := new(MockAuthorDeleter)
+		h := NewAuthorHandler(new(MockAuthorRepo), mDel, new(MockAuditRepo), nil, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/authors/"+primitive.NewObjectID().Hex(), nil)
+		newRouter(h, primitive.NewObjectID()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mDel.AssertNotCalled(t, "DeleteAuthor", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ALLOW: Author transfers posts and deletes own account", func(t *testing.T) {
+		mDel := new(MockAuthorDeleter)
+		h := NewAuthorHandler(new(MockAuthorRepo), mDel, new(MockAuditRepo), nil, nil)
+
+		selfID := primitive.NewObjectID()
+		heirID := primitive.NewObjectID()
+		opts := service.DeletionOptions{Mode: service.DeletionTransfer, TransferTo: heirID}
+		mDel.On("DeleteAuthor", mock.Anything, selfID, opts).
+			Return(&service.DeletionReport{Mode: service.DeletionTransfer, PostsTransferred: 3}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/authors/"+selfID.Hex()+"?mode=transfer&transfer_to="+heirID.Hex(), nil)
+		newRouter(h, selfID).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"posts_transferred":3`)
+		mDel.AssertExpectations(t)
+	})
+}
+
+func TestAuthor_DataExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authorID := primitive.NewObjectID()
+	setup := func(mExp *MockDataExporter) *gin.Engine {
+		h := NewAuthorHandler(new(MockAuthorRepo), new(MockAuthorDeleter), new(MockAuditRepo), mExp, nil)
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("author_id", authorID.Hex()) })
+		r.GET("/authors/:id/export", h.ExportAuthorData)
+		r.GET("/authors/:id/export/:job_id", h.GetExportJob)
+		r.GET("/authors/:id/export/:job_id/download", h.DownloadExportJob)
+		return r
+	}
+	get := func(r *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Small accounts download the archive straight away", func(t *testing.T) {
+		mExp := new(MockDataExporter)
+		mExp.On("IsLarge", mock.Anything, authorID).Return(false, nil)
+		mExp.On("Collect", mock.Anything, authorID).Return(&service.ExportBundle{Profile: &author.Author{Name: "Ada"}}, nil)
+
+		w := get(setup(mExp), "/authors/"+authorID.Hex()+"/export")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		mExp.AssertNotCalled(t, "StartJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Async requests get the job to poll", func(t *testing.T) {
+		job := &export.Job{ID: primitive.NewObjectID(), AuthorID: authorID, Status: export.JobPending}
+		mExp := new(MockDataExporter)
+		mExp.On("IsLarge", mock.Anything, authorID).Return(false, nil)
+		mExp.On("StartJob", mock.Anything, authorID).Return(job, nil)
+
+		w := get(setup(mExp), "/authors/"+authorID.Hex()+"/export?async=true")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), job.ID.Hex())
+		mExp.AssertNotCalled(t, "Collect", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other authors' exports cannot be requested", func(t *testing.T) {
+		w := get(setup(new(MockDataExporter)), "/authors/"+primitive.NewObjectID().Hex()+"/export")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown jobs are not found", func(t *testing.T) {
+		jobID := primitive.NewObjectID()
+		mExp := new(MockDataExporter)
+		mExp.On("GetJob", mock.Anything, jobID, authorID).Return(nil, service.ErrExportJobNotFound)
+
+		w := get(setup(mExp), "/authors/"+authorID.Hex()+"/export/"+jobID.Hex())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Pending jobs cannot be downloaded yet", func(t *testing.T) {
+		job := &export.Job{ID: primitive.NewObjectID(), AuthorID: authorID, Status: export.JobPending}
+		mExp := new(MockDataExporter)
+		mExp.On("GetJob", mock.Anything, job.ID, authorID).Return(job, nil)
+
+		w := get(setup(mExp), "/authors/"+authorID.Hex()+"/export/"+job.ID.Hex()+"/download")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mExp.AssertNotCalled(t, "DownloadJob", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Completed jobs stream their archive", func(t *testing.T) {
+		job := &export.Job{ID: primitive.NewObjectID(), AuthorID: authorID, Status: export.JobCompleted}
+		mExp := new(MockDataExporter)
+		mExp.On("GetJob", mock.Anything, job.ID, authorID).Return(job, nil)
+		mExp.On("DownloadJob", mock.Anything, job, mock.Anything).Return("PK-archive", nil)
+
+		w := get(setup(mExp), "/authors/"+authorID.Hex()+"/export/"+job.ID.Hex()+"/download")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "PK-archive", w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Disposition"), service.ExportFilename(authorID))
+	})
+}
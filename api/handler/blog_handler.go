@@ -2,28 +2,70 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/author"
 	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/readers"
 	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+	"razorblog-backend/internal/trending"
 )
 
 // BlogHandler now uses interfaces instead of concrete structs
 type BlogHandler struct {
-    repo       repository.IBlogRepository   // Swapped
-    authorRepo repository.IAuthorRepository // Swapped
+	repo       repository.IBlogRepository   // Swapped
+	authorRepo repository.IAuthorRepository // Swapped
+	seriesRepo repository.ISeriesRepository
+	mentions   service.IMentionService
+	notifier   service.INotificationService
+	broker     events.Broker
+	bookmarks  repository.IBookmarkRepository
+	counter    *readers.Counter
+	analytics  repository.IAnalyticsRepository
+}
+
+// BlogDeps holds the optional collaborators of a BlogHandler. Any left
+// nil are skipped.
+type BlogDeps struct {
+	Series    repository.ISeriesRepository
+	Mentions  service.IMentionService
+	Notifier  service.INotificationService
+	Broker    events.Broker
+	Bookmarks repository.IBookmarkRepository
+	Counter   *readers.Counter
+	Analytics repository.IAnalyticsRepository
 }
 
 // NewBlogHandler now accepts interfaces
-func NewBlogHandler(repo repository.IBlogRepository, authorRepo repository.IAuthorRepository) *BlogHandler {
-    return &BlogHandler{
-        repo:       repo,
-        authorRepo: authorRepo,
-    }
+func NewBlogHandler(repo repository.IBlogRepository, authorRepo repository.IAuthorRepository, deps ...BlogDeps) *BlogHandler {
+	var d BlogDeps
+	if len(deps) > 0 {
+		d = deps[0]
+	}
+	return &BlogHandler{
+		repo:       repo,
+		authorRepo: authorRepo,
+		seriesRepo: d.Series,
+		mentions:   d.Mentions,
+		notifier:   d.Notifier,
+		broker:     d.Broker,
+		bookmarks:  d.Bookmarks,
+		counter:    d.Counter,
+		analytics:  d.Analytics,
+	}
 }
 
 // CreateBlog godoc
@@ -32,66 +74,84 @@ func NewBlogHandler(repo repository.IBlogRepository, authorRepo repository.IAuth
 // @Tags Blogs
 // @Accept json
 // @Produce json
-// @Param blog body map[string]string true "Blog info (title, content, image_url, category)"
+// @Param blog body map[string]string true "Blog info (title, content, image_url, category, type, sections)"
 // @Success 201 {object} blog.Blog
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /blogs [post]
 func (h *BlogHandler) CreateBlog(c *gin.Context) {
-    var b blog.Blog
-    if err := c.ShouldBindJSON(&b); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-
-    // 1. Get Author ID from Middleware
-    authorID, exists := c.Get("author_id")
-    if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-        return
-    }
-
-    objID, err := primitive.ObjectIDFromHex(authorID.(string))
-    if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid author ID"})
-        return
-    }
-    b.AuthorID = objID
-
-    // 2. DEFENSIVE CHECK: RBAC vs Content Type
-    // If attempting to post a TDD or Case Study, verify the role
-    if b.Type == blog.TypeTDD || b.Type == blog.TypeCaseStudy {
-        author, err := h.authorRepo.GetAuthorByID(objID)
-        if err != nil || author == nil {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
-            return
-        }
-
-        if author.Role != "founder" {
-            c.JSON(http.StatusForbidden, gin.H{
-                "error": "unauthorized: guests can only publish standard blogs",
-            })
-            return
-        }
-    }
-
-    // 3. Save to Repository
-    created, err := h.repo.Create(context.Background(), &b)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-
-    c.JSON(http.StatusCreated, created)
+	var b blog.Blog
+	if err := c.ShouldBindJSON(&b); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 1. Get Author ID from Middleware
+	authorID, exists := c.Get("author_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	objID, err := primitive.ObjectIDFromHex(authorID.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid author ID"})
+		return
+	}
+	b.AuthorID = objID
+
+	// Decision links are managed through the status endpoint only
+	b.Status = ""
+	b.Supersedes = nil
+	b.SupersededBy = nil
+	if b.Type == blog.TypeTDD {
+		b.Status = blog.StatusProposed
+	}
+
+	// 2. DEFENSIVE CHECK: RBAC vs Content Type
+	if !h.canPublishType(c, objID, b.Type) {
+		return
+	}
+
+	// 3. Structured documents must follow their section template
+	if err := blog.ValidateSections(b.Type, b.Sections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if b.Tags, err = blog.NormalizeTags(b.Tags); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 4. @mentions become profile links
+	b.Content, b.Sections, b.Mentions = resolvePostMentions(h.mentions, b.Content, b.Sections)
+
+	// 5. Save to Repository
+	created, err := h.repo.Create(context.Background(), &b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(created.Mentions) > 0 {
+		notifyMentions(h.mentions, nil, created.Mentions, service.MentionEvent{
+			ActorID:   &created.AuthorID,
+			ActorName: h.authorName(created.AuthorID, map[primitive.ObjectID]string{}),
+			BlogID:    &created.ID,
+		})
+	}
+
+	c.JSON(http.StatusCreated, created)
 }
 
 // GetBlog godoc
 // @Summary Get a blog by ID
-// @Description Retrieves a blog by its ID and increments readers count
+// @Description Retrieves a blog by its ID and counts the view. Views by the post's authors and bots are not counted, and each reader adds to the unique readers count once per counting window. Posts in a series include previous/next navigation. Logged-in readers also see whether they bookmarked the post.
 // @Tags Blogs
 // @Produce json
 // @Param id path string true "Blog ID"
+// @Param ref query string false "Referrer of the page showing the post, for analytics"
 // @Success 200 {object} blog.Blog
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
@@ -104,13 +164,13 @@ func (h *BlogHandler) GetBlog(c *gin.Context) {
 		return
 	}
 
-	_ = h.repo.IncrementReaders(context.Background(), objID)
-
 	b, err := h.repo.GetByID(context.Background(), objID)
-	if err != nil {
+	if err != nil || b.Hidden {
 		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
 		return
 	}
+	viewerID := optionalAuthorID(c)
+	h.countView(c, b, viewerID)
 
 	// Fetch author name using GetAuthorByID
 	name := ""
@@ -118,83 +178,295 @@ func (h *BlogHandler) GetBlog(c *gin.Context) {
 		name = authorData.Name
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"blog":       b,
 		"authorName": name,
-	})
+		"credits":    h.credits(b, map[primitive.ObjectID]string{}),
+	}
+	if nav := h.seriesNavigation(b.ID); nav != nil {
+		response["series"] = nav
+	}
+	if viewerID != nil && h.bookmarks != nil {
+		if bookmarked, err := h.bookmarks.IsBookmarked(context.Background(), *viewerID, b.ID); err == nil {
+			response["bookmarked"] = bookmarked
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// countView counts a fetch of the post towards its views and unique
+// readers, skipping the post's own authors. The counts on b are updated to
+// match so the response and live readers event include this view.
+func (h *BlogHandler) countView(c *gin.Context, b *blog.Blog, viewerID *primitive.ObjectID) {
+	if h.counter == nil || (viewerID != nil && b.CanEdit(*viewerID)) {
+		return
+	}
+
+	res, err := h.counter.Count(context.Background(), readers.Visit{
+		BlogID:    b.ID,
+		UserID:    viewerID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Failed to record reader visit: %v", err)
+	}
+	if !res.Counted {
+		return
+	}
+
+	if err := h.repo.CountView(context.Background(), b.ID, res.Unique); err != nil {
+		log.Printf("⚠️ Failed to count view: %v", err)
+		return
+	}
+	b.Views++
+	event := analytics.Event{BlogID: b.ID, Counts: analytics.Counts{Views: 1}, Referrer: analytics.ReferrerHost(referrer(c))}
+	if res.Unique {
+		event.Counts.Readers = 1
+		b.Readers++
+		publishEvent(h.broker, b.ID, events.TypeReaders, gin.H{"readers": b.Readers})
+	}
+	recordActivity(h.analytics, event)
+}
+
+// referrer is where the reader came from. Browser apps fetch posts from
+// their own pages, so they pass the page's document.referrer as the ref
+// query parameter; otherwise the Referer header is used.
+func referrer(c *gin.Context) string {
+	if ref := c.Query("ref"); ref != "" {
+		return ref
+	}
+	return c.Request.Referer()
+}
+
+// seriesNavigation returns the position of a post within its series along
+// with the previous and next parts, or nil for standalone posts
+func (h *BlogHandler) seriesNavigation(blogID primitive.ObjectID) gin.H {
+	if h.seriesRepo == nil {
+		return nil
+	}
+	s, err := h.seriesRepo.GetByPost(context.Background(), blogID)
+	if err != nil || s == nil {
+		return nil
+	}
+
+	// Hidden and trashed parts are skipped, so readers only step between
+	// posts they can open
+	parts, err := listedParts(context.Background(), h.repo, s.PostIDs)
+	if err != nil {
+		return nil
+	}
+	idx := -1
+	for i, p := range parts {
+		if p.ID == blogID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+
+	part := func(i int) gin.H {
+		if i < 0 || i >= len(parts) {
+			return nil
+		}
+		return gin.H{"id": parts[i].ID, "title": parts[i].Title}
+	}
+
+	return gin.H{
+		"id":       s.ID,
+		"title":    s.Title,
+		"part":     idx + 1,
+		"total":    len(parts),
+		"previous": part(idx - 1),
+		"next":     part(idx + 1),
+	}
 }
 
 // UpdateBlog godoc
 // @Summary Update a blog
-// @Description Updates blog details by ID
+// @Description Updates blog details by ID (owner and accepted co-authors only)
 // @Tags Blogs
 // @Accept json
 // @Produce json
 // @Param id path string true "Blog ID"
-// @Param blog body map[string]string true "Updated blog fields (title, content, image_url, category)"
+// @Param blog body map[string]string true "Updated blog fields (title, content, image_url, category, type, sections)"
 // @Success 200 {object} blog.Blog
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /blogs/{id} [put]
 func (h *BlogHandler) UpdateBlog(c *gin.Context) {
-    id := c.Param("id")
-    objID, err := primitive.ObjectIDFromHex(id)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
-        return
-    }
-
-    var b blog.Blog
-    if err := c.ShouldBindJSON(&b); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-
-    // Surgical Update: Include the new 'type' field
-    update := map[string]interface{}{
-        "title":     b.Title,
-        "content":   b.Content,
-        "image_url": b.ImageURL,
-        "category":  b.Category,
-        "type":      b.Type, // Added this
-    }
-
-    // Optional: You can add the same RBAC check here if you want to prevent
-    // guests from "upgrading" an existing blog to a TDD.
-    
-    updated, err := h.repo.Update(context.Background(), objID, update)
-    if err != nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
-        return
-    }
-
-    c.JSON(http.StatusOK, updated)
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	var b blog.Blog
+	if err := c.ShouldBindJSON(&b); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only the owner and accepted co-authors may edit
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	existing, err := h.repo.GetByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+	if !existing.CanEdit(callerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the author and accepted co-authors can edit this post"})
+		return
+	}
+
+	// Changing the type is the owner's call, and structured documents stay
+	// founder-only just as when creating a post
+	if b.Type == "" {
+		b.Type = existing.Type
+	}
+	if b.Type != existing.Type {
+		if callerID != existing.AuthorID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the author can change the type of this post"})
+			return
+		}
+		if !h.canPublishType(c, callerID, b.Type) {
+			return
+		}
+	}
+
+	content, sections, mentioned := resolvePostMentions(h.mentions, b.Content, b.Sections)
+
+	// Surgical Update: Include the new 'type' field
+	update := map[string]interface{}{
+		"title":     b.Title,
+		"content":   content,
+		"image_url": b.ImageURL,
+		"category":  b.Category,
+		"type":      b.Type, // Added this
+		"sections":  sections,
+		"mentions":  mentioned,
+	}
+
+	if err := blog.ValidateSections(b.Type, b.Sections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := blog.NormalizeTags(b.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	update["tags"] = tags
+	if b.Type == blog.TypeTDD && existing.Type != blog.TypeTDD {
+		update["status"] = blog.StatusProposed
+	}
+
+	updated, err := h.repo.Update(context.Background(), objID, update)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	if len(mentioned) > 0 {
+		notifyMentions(h.mentions, existing.Mentions, mentioned, service.MentionEvent{
+			ActorID:   &callerID,
+			ActorName: h.authorName(callerID, map[primitive.ObjectID]string{}),
+			BlogID:    &updated.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, updated)
 }
 
 // DeleteBlog godoc
 // @Summary Delete a blog
-// @Description Deletes a blog by its ID
+// @Description Moves a blog owned by the logged-in author to the trash. It can be restored until the retention period ends.
 // @Tags Blogs
 // @Produce json
 // @Param id path string true "Blog ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /blogs/{id} [delete]
 func (h *BlogHandler) DeleteBlog(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
+	b, ok := h.ownedBlog(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(context.Background(), b.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]string{"message": "blog moved to trash"})
+}
+
+// ListTrash godoc
+// @Summary List trashed blogs
+// @Description Returns the logged-in author's deleted blogs that can still be restored
+// @Tags Blogs
+// @Produce json
+// @Success 200 {array} blog.Blog
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/trash [get]
+func (h *BlogHandler) ListTrash(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	blogs, err := h.repo.ListTrash(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if blogs == nil {
+		blogs = []*blog.Blog{}
+	}
+
+	c.JSON(http.StatusOK, blogs)
+}
+
+// RestoreBlog godoc
+// @Summary Restore a trashed blog
+// @Description Takes one of the logged-in author's blogs out of the trash
+// @Tags Blogs
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/restore [patch]
+func (h *BlogHandler) RestoreBlog(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
 		return
 	}
 
-	if err := h.repo.Delete(context.Background(), objID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+	if err := h.repo.Restore(context.Background(), blogID, authorID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found in trash"})
 		return
 	}
 
-	c.JSON(http.StatusOK, map[string]string{"message": "blog deleted"})
+	c.JSON(http.StatusOK, gin.H{"message": "blog restored"})
 }
 
 // ListBlogs godoc
@@ -217,7 +489,14 @@ func (h *BlogHandler) ListBlogs(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, h.listing(blogs))
+}
+
+// listing pairs each post with its author's name and role and its credits,
+// the way post lists are returned
+func (h *BlogHandler) listing(blogs []*blog.Blog) []gin.H {
 	// Include author names
+	names := map[primitive.ObjectID]string{}
 	result := make([]gin.H, 0, len(blogs))
 	for _, b := range blogs {
 		name := ""
@@ -230,12 +509,140 @@ func (h *BlogHandler) ListBlogs(c *gin.Context) {
 			"blog":       b,
 			"authorName": name,
 			"authorRole": role,
+			"credits":    h.credits(b, names),
 		})
 	}
+	return result
+}
+
+// ListTrending godoc
+// @Summary List trending posts
+// @Description Returns the posts with the most recent engagement. Unique readers, likes, comments and shares from the last week count towards a post's trending score, with older engagement counting for less. Scores are recomputed in the background every few minutes.
+// @Tags Blogs
+// @Produce json
+// @Param type query string false "Only posts of this type (blog, tdd, case_study)"
+// @Param limit query int false "Limit number of blogs" default(10)
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blogs/trending [get]
+func (h *BlogHandler) ListTrending(c *gin.Context) {
+	docType, limit, ok := rankingParams(c)
+	if !ok {
+		return
+	}
+
+	blogs, err := h.repo.ListTrending(context.Background(), docType, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trending posts"})
+		return
+	}
 
+	c.JSON(http.StatusOK, h.listing(blogs))
+}
+
+// ListPopular godoc
+// @Summary List popular posts
+// @Description Returns the posts with the most engagement within a window ending now, such as 24h or 7d. Unique readers, likes, comments and shares all count, each listed post includes its counts for the window.
+// @Tags Blogs
+// @Produce json
+// @Param window query string false "Window length: 1h to 48h, or 1d to 365d" default(7d)
+// @Param type query string false "Only posts of this type (blog, tdd, case_study)"
+// @Param limit query int false "Limit number of blogs" default(10)
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blogs/popular [get]
+func (h *BlogHandler) ListPopular(c *gin.Context) {
+	docType, limit, ok := rankingParams(c)
+	if !ok {
+		return
+	}
+	g, from, to, err := popularWindow(c.DefaultQuery("window", "7d"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.analytics == nil {
+		c.JSON(http.StatusOK, []gin.H{})
+		return
+	}
+
+	ctx := context.Background()
+	top, err := h.analytics.Popular(ctx, g, from, to, trending.DefaultWeights, docType, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch popular posts"})
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(top))
+	for _, t := range top {
+		ids = append(ids, t.BlogID)
+	}
+	blogs, err := h.repo.GetListedByIDs(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch popular posts"})
+		return
+	}
+
+	byID := make(map[primitive.ObjectID]*blog.Blog, len(blogs))
+	for _, b := range blogs {
+		byID[b.ID] = b
+	}
+	ranked := make([]*blog.Blog, 0, len(top))
+	counts := make([]analytics.Counts, 0, len(top))
+	for _, t := range top {
+		if b, ok := byID[t.BlogID]; ok {
+			ranked = append(ranked, b)
+			counts = append(counts, t.Counts)
+		}
+	}
+
+	result := h.listing(ranked)
+	for i := range result {
+		result[i]["counts"] = counts[i]
+	}
 	c.JSON(http.StatusOK, result)
 }
 
+// rankingParams reads the type and limit query parameters of the ranking
+// endpoints
+func rankingParams(c *gin.Context) (blog.DocumentType, int64, bool) {
+	docType := blog.DocumentType(c.Query("type"))
+	if docType != "" && !docType.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be blog, tdd or case_study"})
+		return "", 0, false
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	return docType, limit, true
+}
+
+var windowPattern = regexp.MustCompile(`^(\d+)([hd])$`)
+
+// popularWindow turns a window such as "24h" or "7d" into the buckets it
+// covers. Hour windows use hourly buckets and end with the current hour;
+// day windows use daily buckets and end with today.
+func popularWindow(window string, now time.Time) (analytics.Granularity, time.Time, time.Time, error) {
+	m := windowPattern.FindStringSubmatch(window)
+	if m == nil {
+		return "", time.Time{}, time.Time{}, errors.New("window must look like 24h or 7d")
+	}
+	n, _ := strconv.Atoi(m[1])
+
+	g, longest := analytics.Daily, 365
+	if m[2] == "h" {
+		g, longest = analytics.Hourly, 48
+	}
+	if n < 1 || n > longest {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("window must be between 1%s and %d%s", m[2], longest, m[2])
+	}
+
+	to := g.Start(now).Add(g.Duration())
+	return g, to.Add(-time.Duration(n) * g.Duration()), to, nil
+}
+
 // LikeBlog godoc
 // @Summary Like a blog
 // @Description Adds the logged-in user's like to a blog
@@ -267,10 +674,28 @@ func (h *BlogHandler) LikeBlog(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.LikeBlog(c, blogID, userID); err != nil {
+	added, err := h.repo.LikeBlog(c, blogID, userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if added {
+		recordActivity(h.analytics, analytics.Event{BlogID: blogID, Counts: analytics.Counts{Likes: 1}})
+	}
+
+	// The key keeps liking, unliking and liking again from notifying twice
+	if b, err := h.repo.GetByID(context.Background(), blogID); err == nil {
+		publishEvent(h.broker, blogID, events.TypeLikes, gin.H{"likes": len(b.Likes)})
+		if h.notifier != nil {
+			notifyPostAuthors(h.notifier, b, nil, notification.Notification{
+				Type:      notification.TypeLike,
+				ActorID:   &userID,
+				ActorName: h.authorName(userID, map[primitive.ObjectID]string{}),
+				BlogID:    &blogID,
+				Key:       "like:" + blogID.Hex() + ":" + userID.Hex(),
+			})
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "blog liked"})
 }
@@ -311,12 +736,16 @@ func (h *BlogHandler) UnlikeBlog(c *gin.Context) {
 		return
 	}
 
+	if b, err := h.repo.GetByID(context.Background(), blogID); err == nil {
+		publishEvent(h.broker, blogID, events.TypeLikes, gin.H{"likes": len(b.Likes)})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "blog unliked"})
 }
 
 // GetBlogsByAuthor godoc
 // @Summary List blogs for a specific author
-// @Description Retrieves all blogs created by the given author ID
+// @Description Retrieves all blogs created or co-authored by the given author ID
 // @Tags Blogs
 // @Produce json
 // @Param author_id path string true "Author ID"
@@ -338,14 +767,188 @@ func (h *BlogHandler) GetBlogsByAuthor(c *gin.Context) {
 		return
 	}
 
-	// Attach author name
-	name := ""
-	if authorData, err := h.authorRepo.GetAuthorByID(authorID); err == nil && authorData != nil {
-		name = authorData.Name
+	// Attach the owner's name and every credited author; co-authored
+	// posts may be owned by someone else
+	names := map[primitive.ObjectID]string{}
+	result := make([]gin.H, 0, len(blogs))
+	for _, b := range blogs {
+		result = append(result, gin.H{
+			"blog":       b,
+			"authorName": h.authorName(b.AuthorID, names),
+			"credits":    h.credits(b, names),
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListTemplates godoc
+// @Summary List document templates
+// @Description Returns the section schema for every structured document type
+// @Tags Blogs
+// @Produce json
+// @Success 200 {array} blog.Template
+// @Router /blogs/templates [get]
+func (h *BlogHandler) ListTemplates(c *gin.Context) {
+	templates := make([]blog.Template, 0, len(blog.Templates))
+	for _, t := range []blog.DocumentType{blog.TypeTDD, blog.TypeCaseStudy} {
+		templates = append(templates, blog.Templates[t])
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get a document template skeleton
+// @Description Returns the section schema and an empty skeleton for a document type
+// @Tags Blogs
+// @Produce json
+// @Param type path string true "Document type (tdd, case_study)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /blogs/templates/{type} [get]
+func (h *BlogHandler) GetTemplate(c *gin.Context) {
+	tpl, ok := blog.TemplateFor(blog.DocumentType(c.Param("type")))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no template for this document type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"type":     tpl.Type,
+		"sections": tpl.Sections,
+		"skeleton": tpl.Skeleton(),
+	})
+}
+
+// UpdateDecisionStatus godoc
+// @Summary Change the decision status of a TDD
+// @Description Moves a TDD through proposed, accepted, implemented, rejected or superseded (founders only)
+// @Tags Blogs
+// @Accept json
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param body body object{status=string,superseded_by=string} true "New status, and the superseding TDD when status is superseded"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/status [patch]
+func (h *BlogHandler) UpdateDecisionStatus(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	var body struct {
+		Status       blog.DecisionStatus `json:"status"`
+		SupersededBy string              `json:"superseded_by"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || !body.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a valid status is required"})
+		return
+	}
+
+	if _, ok := h.requireFounder(c); !ok {
+		return
+	}
+
+	b, err := h.repo.GetByID(context.Background(), blogID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+	if b.Type != blog.TypeTDD {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only TDD documents have a decision status"})
+		return
+	}
+
+	current := blog.DecisionStatusOf(b)
+	if !current.CanTransitionTo(body.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "cannot move a " + string(current) + " TDD to " + string(body.Status),
+		})
+		return
+	}
+
+	update := map[string]interface{}{"status": body.Status}
+
+	var successorID primitive.ObjectID
+	if body.Status == blog.StatusSuperseded {
+		successorID, err = primitive.ObjectIDFromHex(body.SupersededBy)
+		if err != nil || successorID == blogID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "superseded_by must reference another TDD"})
+			return
+		}
+
+		successor, err := h.repo.GetByID(context.Background(), successorID)
+		if err != nil || successor.Type != blog.TypeTDD {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "superseded_by must reference another TDD"})
+			return
+		}
+		if successor.Supersedes != nil && *successor.Supersedes != blogID {
+			c.JSON(http.StatusConflict, gin.H{"error": "superseded_by already supersedes another TDD"})
+			return
+		}
+		update["superseded_by"] = successorID
+	}
+
+	// The original is updated first so a failure part way leaves no
+	// successor pointing back at a TDD that was never superseded
+	updated, err := h.repo.Update(context.Background(), blogID, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if body.Status == blog.StatusSuperseded {
+		if _, err := h.repo.Update(context.Background(), successorID, map[string]interface{}{"supersedes": blogID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// ListTDDs godoc
+// @Summary List TDDs by decision status
+// @Description Returns technical design documents, optionally filtered by status
+// @Tags Blogs
+// @Produce json
+// @Param status query string false "Decision status (proposed, accepted, implemented, rejected, superseded)"
+// @Param limit query int false "Limit number of TDDs" default(10)
+// @Param skip query int false "Number of TDDs to skip" default(0)
+// @Success 200 {array} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blogs/tdds [get]
+func (h *BlogHandler) ListTDDs(c *gin.Context) {
+	status := blog.DecisionStatus(c.Query("status"))
+	if status != "" && !status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	blogs, err := h.repo.ListTDDs(context.Background(), status, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	result := make([]gin.H, 0, len(blogs))
 	for _, b := range blogs {
+		name := ""
+		if authorData, err := h.authorRepo.GetAuthorByID(b.AuthorID); err == nil && authorData != nil {
+			name = authorData.Name
+		}
+		b.Status = blog.DecisionStatusOf(b)
 		result = append(result, gin.H{
 			"blog":       b,
 			"authorName": name,
@@ -355,4 +958,87 @@ func (h *BlogHandler) GetBlogsByAuthor(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// canPublishType rejects post types the author's role may not publish.
+// TDDs and case studies are founder-only; anyone may publish standard blogs.
+func (h *BlogHandler) canPublishType(c *gin.Context, authorID primitive.ObjectID, t blog.DocumentType) bool {
+	if t != "" && !t.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be blog, tdd or case_study"})
+		return false
+	}
+	if t != blog.TypeTDD && t != blog.TypeCaseStudy {
+		return true
+	}
+
+	a, err := h.authorRepo.GetAuthorByID(authorID)
+	if err != nil || a == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
+		return false
+	}
+	if a.Role != author.RoleFounder {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "unauthorized: guests can only publish standard blogs",
+		})
+		return false
+	}
+	return true
+}
+
+// requireFounder resolves the logged-in author and rejects anyone who is not a founder
+func (h *BlogHandler) requireFounder(c *gin.Context) (primitive.ObjectID, bool) {
+	objID, ok := currentAuthorID(c)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	a, err := h.authorRepo.GetAuthorByID(objID)
+	if err != nil || a == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
+		return primitive.NilObjectID, false
+	}
 
+	if a.Role != author.RoleFounder {
+		c.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: only founders can do this"})
+		return primitive.NilObjectID, false
+	}
+
+	return objID, true
+}
+
+// UpdateCommentModeration godoc
+// @Summary Configure comment pre-moderation for a blog
+// @Description Turns pre-moderation of new comments on or off for a blog owned by the logged-in author
+// @Tags Blogs
+// @Accept json
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param body body object{premoderate_comments=bool} true "Whether new comments wait for approval"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/moderation [patch]
+func (h *BlogHandler) UpdateCommentModeration(c *gin.Context) {
+	b, ok := h.ownedBlog(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		PremoderateComments *bool `json:"premoderate_comments"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.PremoderateComments == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "premoderate_comments is required"})
+		return
+	}
+
+	updated, err := h.repo.Update(context.Background(), b.ID, map[string]interface{}{
+		"premoderate_comments": *body.PremoderateComments,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
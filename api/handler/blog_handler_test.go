@@ -2,17 +2,22 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/analytics"
 	"razorblog-backend/internal/models/author"
 	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/readers"
 )
 
 func TestCreateBlog_Security(t *testing.T) {
@@ -60,11 +65,438 @@ func TestCreateBlog_Security(t *testing.T) {
 			h.CreateBlog(ctx)
 		})
 
-		input := blog.Blog{Title: "Founder Architecture", Type: blog.TypeTDD}
+		input := blog.Blog{Title: "Founder Architecture", Type: blog.TypeTDD, Sections: validTDDSections()}
 		body, _ := json.Marshal(input)
 		req, _ := http.NewRequest("POST", "/blogs", bytes.NewBuffer(body))
 		r.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusCreated, w.Code)
 	})
+
+	t.Run("REJECT: TDD missing required sections", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+
+		founderID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", founderID).Return(&author.Author{Role: "founder"}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+
+		r.POST("/blogs", func(ctx *gin.Context) {
+			ctx.Set("author_id", founderID.Hex())
+			h.CreateBlog(ctx)
+		})
+
+		sections := validTDDSections()
+		delete(sections, "decision")
+		input := blog.Blog{Title: "Half-written TDD", Type: blog.TypeTDD, Sections: sections}
+		body, _ := json.Marshal(input)
+		req, _ := http.NewRequest("POST", "/blogs", bytes.NewBuffer(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "decision")
+		mBlog.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func validTDDSections() map[string]string {
+	return map[string]string{
+		"context":      "Why we need this",
+		"goals":        "What we want",
+		"non_goals":    "What we skip",
+		"design":       "How it works",
+		"alternatives": "What else we considered",
+		"decision":     "What we chose",
+	}
+}
+
+func TestUpdateDecisionStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(h *BlogHandler, callerID primitive.ObjectID) *gin.Engine {
+		r := gin.New()
+		r.PATCH("/blogs/:id/status", func(ctx *gin.Context) {
+			ctx.Set("author_id", callerID.Hex())
+			h.UpdateDecisionStatus(ctx)
+		})
+		return r
+	}
+
+	t.Run("REJECT: Guest attempts to accept a TDD", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+
+		guestID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", guestID).Return(&author.Author{Role: author.RoleGuest}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/blogs/"+primitive.NewObjectID().Hex()+"/status", bytes.NewBufferString(`{"status":"accepted"}`))
+		newRouter(h, guestID).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Rejected TDD cannot be implemented", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+
+		founderID := primitive.NewObjectID()
+		blogID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", founderID).Return(&author.Author{Role: author.RoleFounder}, nil)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, Type: blog.TypeTDD, Status: blog.StatusRejected}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/blogs/"+blogID.Hex()+"/status", bytes.NewBufferString(`{"status":"implemented"}`))
+		newRouter(h, founderID).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Successor already supersedes another TDD", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+
+		founderID := primitive.NewObjectID()
+		blogID, successorID, otherID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", founderID).Return(&author.Author{Role: author.RoleFounder}, nil)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, Type: blog.TypeTDD, Status: blog.StatusAccepted}, nil)
+		mBlog.On("GetByID", mock.Anything, successorID).Return(&blog.Blog{ID: successorID, Type: blog.TypeTDD, Supersedes: &otherID}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/blogs/"+blogID.Hex()+"/status",
+			bytes.NewBufferString(`{"status":"superseded","superseded_by":"`+successorID.Hex()+`"}`))
+		newRouter(h, founderID).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ALLOW: Original is superseded before the successor is linked", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+
+		founderID := primitive.NewObjectID()
+		blogID, successorID := primitive.NewObjectID(), primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", founderID).Return(&author.Author{Role: author.RoleFounder}, nil)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, Type: blog.TypeTDD, Status: blog.StatusAccepted}, nil)
+		mBlog.On("GetByID", mock.Anything, successorID).Return(&blog.Blog{ID: successorID, Type: blog.TypeTDD}, nil)
+		mBlog.On("Update", mock.Anything, blogID, mock.Anything).Return(&blog.Blog{ID: blogID}, nil)
+		mBlog.On("Update", mock.Anything, successorID, mock.Anything).Return(&blog.Blog{ID: successorID}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/blogs/"+blogID.Hex()+"/status",
+			bytes.NewBufferString(`{"status":"superseded","superseded_by":"`+successorID.Hex()+`"}`))
+		newRouter(h, founderID).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var updated []primitive.ObjectID
+		for _, call := range mBlog.Calls {
+			if call.Method == "Update" {
+				updated = append(updated, call.Arguments.Get(1).(primitive.ObjectID))
+			}
+		}
+		assert.Equal(t, []primitive.ObjectID{blogID, successorID}, updated)
+	})
+}
+
+func TestUpdateBlog_EditRights(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	pendingID := primitive.NewObjectID()
+	blogID := primitive.NewObjectID()
+	existing := &blog.Blog{
+		ID:       blogID,
+		AuthorID: ownerID,
+		Type:     blog.TypeBlog,
+		CoAuthors: []blog.CoAuthor{
+			{AuthorID: editorID, Role: blog.RoleEditor, Status: blog.InviteAccepted},
+			{AuthorID: pendingID, Role: blog.RoleCoAuthor, Status: blog.InvitePending},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		callerID primitive.ObjectID
+		want     int
+	}{
+		{"ALLOW: Accepted co-author edits", editorID, http.StatusOK},
+		{"REJECT: Pending co-author edits", pendingID, http.StatusForbidden},
+		{"REJECT: Stranger edits", primitive.NewObjectID(), http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mBlog := new(MockBlogRepo)
+			h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+
+			mBlog.On("GetByID", mock.Anything, blogID).Return(existing, nil)
+			mBlog.On("Update", mock.Anything, blogID, mock.Anything).Return(existing, nil)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.PUT("/blogs/:id", func(ctx *gin.Context) {
+				ctx.Set("author_id", tc.callerID.Hex())
+				h.UpdateBlog(ctx)
+			})
+
+			body, _ := json.Marshal(blog.Blog{Title: "Edited", Type: blog.TypeBlog})
+			req, _ := http.NewRequest("PUT", "/blogs/"+blogID.Hex(), bytes.NewBuffer(body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.want, w.Code)
+			if tc.want != http.StatusOK {
+				mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateBlog_TypeChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	blogID := primitive.NewObjectID()
+	existing := &blog.Blog{
+		ID:        blogID,
+		AuthorID:  ownerID,
+		Type:      blog.TypeBlog,
+		CoAuthors: []blog.CoAuthor{{AuthorID: editorID, Role: blog.RoleEditor, Status: blog.InviteAccepted}},
+	}
+
+	send := func(callerID primitive.ObjectID, role author.UserRole) (*MockBlogRepo, *httptest.ResponseRecorder) {
+		mBlog, mAuth := new(MockBlogRepo), new(MockAuthorRepo)
+		h := NewBlogHandler(mBlog, mAuth)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(existing, nil)
+		mBlog.On("Update", mock.Anything, blogID, mock.Anything).Return(existing, nil)
+		mAuth.On("GetAuthorByID", callerID).Return(&author.Author{Role: role}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.PUT("/blogs/:id", func(ctx *gin.Context) {
+			ctx.Set("author_id", callerID.Hex())
+			h.UpdateBlog(ctx)
+		})
+
+		body, _ := json.Marshal(blog.Blog{Title: "Now a TDD", Type: blog.TypeTDD, Sections: validTDDSections()})
+		req, _ := http.NewRequest("PUT", "/blogs/"+blogID.Hex(), bytes.NewBuffer(body))
+		r.ServeHTTP(w, req)
+		return mBlog, w
+	}
+
+	t.Run("REJECT: Co-author turns a post into a TDD", func(t *testing.T) {
+		mBlog, w := send(editorID, author.RoleFounder)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Guest owner turns a post into a TDD", func(t *testing.T) {
+		mBlog, w := send(ownerID, author.RoleGuest)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ALLOW: Founder owner turns a post into a proposed TDD", func(t *testing.T) {
+		mBlog, w := send(ownerID, author.RoleFounder)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mBlog.AssertCalled(t, "Update", mock.Anything, blogID, mock.MatchedBy(func(u bson.M) bool {
+			return u["type"] == blog.TypeTDD && u["status"] == blog.StatusProposed
+		}))
+	})
+}
+
+func TestBlogTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	blogID := primitive.NewObjectID()
+	existing := &blog.Blog{
+		ID:        blogID,
+		AuthorID:  ownerID,
+		CoAuthors: []blog.CoAuthor{{AuthorID: editorID, Role: blog.RoleEditor, Status: blog.InviteAccepted}},
+	}
+
+	send := func(h *BlogHandler, callerID primitive.ObjectID, method, path string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("author_id", callerID.Hex()) })
+		r.DELETE("/blogs/:id", h.DeleteBlog)
+		r.GET("/blogs/trash", h.ListTrash)
+		r.PATCH("/blogs/:id/restore", h.RestoreBlog)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ALLOW: Owner moves a post to the trash", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(existing, nil)
+		mBlog.On("Delete", mock.Anything, blogID).Return(nil)
+		h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+
+		w := send(h, ownerID, "DELETE", "/blogs/"+blogID.Hex())
+		assert.Equal(t, http.StatusOK, w.Code)
+		mBlog.AssertCalled(t, "Delete", mock.Anything, blogID)
+	})
+
+	t.Run("REJECT: Co-author moves a post to the trash", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(existing, nil)
+		h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+
+		w := send(h, editorID, "DELETE", "/blogs/"+blogID.Hex())
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mBlog.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Empty trash lists as an empty array", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("ListTrash", mock.Anything, ownerID).Return(nil, nil)
+		h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+
+		w := send(h, ownerID, "GET", "/blogs/trash")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+
+	t.Run("Restore is scoped to the caller's own trash", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("Restore", mock.Anything, blogID, ownerID).Return(nil)
+		mBlog.On("Restore", mock.Anything, blogID, editorID).Return(assert.AnError)
+		h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+
+		assert.Equal(t, http.StatusOK, send(h, ownerID, "PATCH", "/blogs/"+blogID.Hex()+"/restore").Code)
+		assert.Equal(t, http.StatusNotFound, send(h, editorID, "PATCH", "/blogs/"+blogID.Hex()+"/restore").Code)
+	})
+}
+
+// visitSet is an in-memory reader visit store
+type visitSet map[string]bool
+
+func (s visitSet) RecordVisit(ctx context.Context, blogID primitive.ObjectID, visitor string, window, expires time.Time) (bool, error) {
+	key := blogID.Hex() + visitor + window.String()
+	first := !s[key]
+	s[key] = true
+	return first, nil
+}
+
+func TestGetBlog_ReaderCounting(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ownerID := primitive.NewObjectID()
+	b := &blog.Blog{ID: primitive.NewObjectID(), AuthorID: ownerID}
+	const browser = "Mozilla/5.0 (Macintosh) Safari/605.1.15"
+
+	setup := func() (*MockBlogRepo, func(viewerID *primitive.ObjectID, userAgent string) int) {
+		mBlog, mAuth := new(MockBlogRepo), new(MockAuthorRepo)
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mBlog.On("CountView", mock.Anything, b.ID, mock.Anything).Return(nil)
+		mAuth.On("GetAuthorByID", mock.Anything).Return(&author.Author{Name: "Ada"}, nil)
+		counter := readers.NewCounter(readers.Settings{Salt: "test"}, visitSet{})
+		h := NewBlogHandler(mBlog, mAuth, BlogDeps{Counter: counter})
+
+		return mBlog, func(viewerID *primitive.ObjectID, userAgent string) int {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			if viewerID != nil {
+				r.Use(func(c *gin.Context) { c.Set("author_id", viewerID.Hex()) })
+			}
+			r.GET("/blogs/:id", h.GetBlog)
+			req, _ := http.NewRequest("GET", "/blogs/"+b.ID.Hex(), nil)
+			req.Header.Set("User-Agent", userAgent)
+			r.ServeHTTP(w, req)
+			return w.Code
+		}
+	}
+
+	t.Run("Refreshes add views but one reader", func(t *testing.T) {
+		mBlog, get := setup()
+		assert.Equal(t, http.StatusOK, get(nil, browser))
+		assert.Equal(t, http.StatusOK, get(nil, browser))
+
+		mBlog.AssertNumberOfCalls(t, "CountView", 2)
+		mBlog.AssertCalled(t, "CountView", mock.Anything, b.ID, true)
+		mBlog.AssertCalled(t, "CountView", mock.Anything, b.ID, false)
+	})
+
+	t.Run("Author previews and bots are not counted", func(t *testing.T) {
+		mBlog, get := setup()
+		assert.Equal(t, http.StatusOK, get(&ownerID, browser))
+		assert.Equal(t, http.StatusOK, get(nil, "Googlebot/2.1 (+http://www.google.com/bot.html)"))
+
+		mBlog.AssertNotCalled(t, "CountView", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPopularWindow(t *testing.T) {
+	now := time.Date(2026, 6, 10, 15, 30, 0, 0, time.UTC)
+
+	g, from, to, err := popularWindow("7d", now)
+	assert.NoError(t, err)
+	assert.Equal(t, analytics.Daily, g)
+	assert.Equal(t, time.Date(2026, 6, 4, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 6, 11, 0, 0, 0, 0, time.UTC), to)
+
+	g, from, to, err = popularWindow("24h", now)
+	assert.NoError(t, err)
+	assert.Equal(t, analytics.Hourly, g)
+	assert.Equal(t, time.Date(2026, 6, 9, 16, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 6, 10, 16, 0, 0, 0, time.UTC), to)
+
+	for _, w := range []string{"", "7", "0d", "72h", "2w", "400d"} {
+		_, _, _, err := popularWindow(w, now)
+		assert.Error(t, err, w)
+	}
+}
+
+func TestListPopular(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	first, second := &blog.Blog{ID: primitive.NewObjectID(), Type: blog.TypeTDD}, &blog.Blog{ID: primitive.NewObjectID(), Type: blog.TypeTDD}
+
+	mBlog, mAuth, mRepo := new(MockBlogRepo), new(MockAuthorRepo), new(MockAnalyticsRepo)
+	mRepo.On("Popular", mock.Anything, analytics.Daily, mock.Anything, mock.Anything, mock.Anything, blog.TypeTDD, int64(10)).Return([]analytics.PostTotals{
+		{BlogID: first.ID, Counts: analytics.Counts{Readers: 40}},
+		{BlogID: second.ID, Counts: analytics.Counts{Readers: 12}},
+	}, nil)
+	// Listed posts come back in storage order, not ranking order
+	mBlog.On("GetListedByIDs", mock.Anything, []primitive.ObjectID{first.ID, second.ID}).Return([]*blog.Blog{second, first}, nil)
+	mAuth.On("GetAuthorByID", mock.Anything).Return(&author.Author{Name: "Ada"}, nil)
+	h := NewBlogHandler(mBlog, mAuth, BlogDeps{Analytics: mRepo})
+
+	send := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/blogs/popular", h.ListPopular)
+		req, _ := http.NewRequest("GET", "/blogs/popular"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("?window=30d&type=tdd")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []struct {
+		Blog   blog.Blog        `json:"blog"`
+		Counts analytics.Counts `json:"counts"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp, 2) {
+		assert.Equal(t, first.ID, resp[0].Blog.ID)
+		assert.Equal(t, 40, resp[0].Counts.Readers)
+		assert.Equal(t, second.ID, resp[1].Blog.ID)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, send("?type=essay").Code)
+	assert.Equal(t, http.StatusBadRequest, send("?window=forever").Code)
 }
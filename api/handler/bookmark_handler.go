@@ -0,0 +1,633 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/bookmark"
+	"razorblog-backend/internal/repository"
+)
+
+// BookmarkHandler handles bookmarks and reading lists
+type BookmarkHandler struct {
+	repo     repository.IBookmarkRepository
+	blogRepo repository.IBlogRepository
+}
+
+func NewBookmarkHandler(repo repository.IBookmarkRepository, blogRepo repository.IBlogRepository) *BookmarkHandler {
+	return &BookmarkHandler{repo: repo, blogRepo: blogRepo}
+}
+
+// BookmarkBlog godoc
+// @Summary Bookmark a blog
+// @Description Saves a post for the logged-in author to read later. Bookmarking twice has no further effect.
+// @Tags Bookmarks
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 201 {object} map[string]bool
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/bookmark [put]
+func (h *BookmarkHandler) BookmarkBlog(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	b, ok := h.listedBlog(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	created, err := h.repo.Add(context.Background(), authorID, b.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to bookmark blog"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"bookmarked": true})
+}
+
+// UnbookmarkBlog godoc
+// @Summary Remove a bookmark
+// @Description Removes a post from the logged-in author's bookmarks
+// @Tags Bookmarks
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/bookmark [delete]
+func (h *BookmarkHandler) UnbookmarkBlog(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	if err := h.repo.Remove(context.Background(), authorID, blogID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove bookmark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bookmarked": false})
+}
+
+// ListBookmarks godoc
+// @Summary List my bookmarks
+// @Description Returns the logged-in author's bookmarked posts, most recently bookmarked first. Posts that were deleted or hidden since are left out.
+// @Tags Bookmarks
+// @Produce json
+// @Param limit query int false "Limit number of bookmarks" default(20)
+// @Param skip query int false "Number of bookmarks to skip" default(0)
+// @Success 200 {array} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /bookmarks [get]
+func (h *BookmarkHandler) ListBookmarks(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	bookmarks, err := h.repo.List(context.Background(), authorID, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bookmarks"})
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		ids = append(ids, bm.BlogID)
+	}
+	blogs, err := h.blogsByID(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bookmarks"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		if b, ok := blogs[bm.BlogID]; ok {
+			result = append(result, gin.H{"blog": blogSummary(b), "bookmarked_at": bm.CreatedAt})
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateReadingList godoc
+// @Summary Create a reading list
+// @Description Creates an empty, named reading list owned by the logged-in author. Lists are private unless public is true.
+// @Tags Reading Lists
+// @Accept json
+// @Produce json
+// @Param body body object{name=string,description=string,public=bool} true "List details"
+// @Success 201 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists [post]
+func (h *BookmarkHandler) CreateReadingList(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	created, err := h.repo.CreateList(context.Background(), &bookmark.ReadingList{
+		OwnerID:     authorID,
+		Name:        body.Name,
+		Description: body.Description,
+		Public:      body.Public,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create reading list"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListMyReadingLists godoc
+// @Summary List my reading lists
+// @Description Returns the logged-in author's reading lists, public and private, newest first
+// @Tags Reading Lists
+// @Produce json
+// @Success 200 {array} bookmark.ReadingList
+// @Failure 401 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists [get]
+func (h *BookmarkHandler) ListMyReadingLists(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	h.respondWithLists(c, authorID, false)
+}
+
+// ListAuthorReadingLists godoc
+// @Summary List an author's reading lists
+// @Description Returns an author's public reading lists; the author themselves also sees their private ones
+// @Tags Reading Lists
+// @Produce json
+// @Param author_id path string true "Author ID"
+// @Success 200 {array} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Router /reading-lists/author/{author_id} [get]
+func (h *BookmarkHandler) ListAuthorReadingLists(c *gin.Context) {
+	ownerID, err := primitive.ObjectIDFromHex(c.Param("author_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+	viewerID := optionalAuthorID(c)
+	h.respondWithLists(c, ownerID, viewerID == nil || *viewerID != ownerID)
+}
+
+func (h *BookmarkHandler) respondWithLists(c *gin.Context, ownerID primitive.ObjectID, publicOnly bool) {
+	lists, err := h.repo.ListLists(context.Background(), ownerID, publicOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reading lists"})
+		return
+	}
+	if lists == nil {
+		lists = []*bookmark.ReadingList{}
+	}
+	c.JSON(http.StatusOK, lists)
+}
+
+// GetReadingList godoc
+// @Summary Get a reading list
+// @Description Returns a reading list with its posts in order. Private lists are only visible to their owner. Posts that were deleted or hidden since are left out.
+// @Tags Reading Lists
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reading-lists/{id} [get]
+func (h *BookmarkHandler) GetReadingList(c *gin.Context) {
+	l, ok := h.visibleList(c)
+	if !ok {
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(l.Entries))
+	for _, e := range l.Entries {
+		ids = append(ids, e.BlogID)
+	}
+	blogs, err := h.blogsByID(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reading list"})
+		return
+	}
+
+	entries := make([]gin.H, 0, len(l.Entries))
+	for _, e := range l.Entries {
+		if b, ok := blogs[e.BlogID]; ok {
+			entries = append(entries, gin.H{"blog": blogSummary(b), "note": e.Note, "added_at": e.AddedAt})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          l.ID,
+		"owner_id":    l.OwnerID,
+		"name":        l.Name,
+		"description": l.Description,
+		"public":      l.Public,
+		"entries":     entries,
+		"created_at":  l.CreatedAt,
+		"updated_at":  l.UpdatedAt,
+	})
+}
+
+// UpdateReadingList godoc
+// @Summary Update a reading list
+// @Description Renames a reading list, changes its description or makes it public or private (owner only)
+// @Tags Reading Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param body body object{name=string,description=string,public=bool} true "Fields to change"
+// @Success 200 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id} [put]
+func (h *BookmarkHandler) UpdateReadingList(c *gin.Context) {
+	l, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Public      *bool   `json:"public"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{}
+	if body.Name != nil {
+		name := strings.TrimSpace(*body.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+			return
+		}
+		update["name"] = name
+	}
+	if body.Description != nil {
+		update["description"] = *body.Description
+	}
+	if body.Public != nil {
+		update["public"] = *body.Public
+	}
+
+	h.saveList(c, l.ID, update)
+}
+
+// DeleteReadingList godoc
+// @Summary Delete a reading list
+// @Description Deletes a reading list (owner only). The posts themselves are not affected.
+// @Tags Reading Lists
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id} [delete]
+func (h *BookmarkHandler) DeleteReadingList(c *gin.Context) {
+	l, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteList(context.Background(), l.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reading list deleted"})
+}
+
+// AddReadingListEntry godoc
+// @Summary Add a post to a reading list
+// @Description Appends a post, with an optional note, to the end of a reading list (owner only)
+// @Tags Reading Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param body body object{blog_id=string,note=string} true "Post and note"
+// @Success 200 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id}/entries [post]
+func (h *BookmarkHandler) AddReadingListEntry(c *gin.Context) {
+	l, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		BlogID string `json:"blog_id"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	b, ok := h.listedBlog(c, body.BlogID)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.AddEntry(context.Background(), l.ID, bookmark.Entry{BlogID: b.ID, Note: body.Note, AddedAt: time.Now()})
+	switch {
+	case errors.Is(err, bookmark.ErrAlreadyListed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, bookmark.ErrListFull):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "reading list not found"})
+	default:
+		h.respondWithList(c, updated, err)
+	}
+}
+
+// UpdateReadingListEntry godoc
+// @Summary Change the note on a reading list entry
+// @Description Replaces the owner's note on a post in a reading list; an empty note removes it
+// @Tags Reading Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param blog_id path string true "Blog ID"
+// @Param body body object{note=string} true "New note"
+// @Success 200 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id}/entries/{blog_id} [patch]
+func (h *BookmarkHandler) UpdateReadingListEntry(c *gin.Context) {
+	l, blogID, ok := h.ownedEntry(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.repo.SetEntryNote(context.Background(), l.ID, blogID, body.Note)
+	h.respondWithList(c, updated, err)
+}
+
+// RemoveReadingListEntry godoc
+// @Summary Remove a post from a reading list
+// @Description Takes a post out of a reading list (owner only)
+// @Tags Reading Lists
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param blog_id path string true "Blog ID"
+// @Success 200 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id}/entries/{blog_id} [delete]
+func (h *BookmarkHandler) RemoveReadingListEntry(c *gin.Context) {
+	l, blogID, ok := h.ownedEntry(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.RemoveEntry(context.Background(), l.ID, blogID)
+	h.respondWithList(c, updated, err)
+}
+
+// ReorderReadingList godoc
+// @Summary Reorder a reading list
+// @Description Sets the order of a reading list's posts. blog_ids must list every post in the list exactly once.
+// @Tags Reading Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param body body object{blog_ids=[]string} true "Blog IDs in the new order"
+// @Success 200 {object} bookmark.ReadingList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reading-lists/{id}/order [put]
+func (h *BookmarkHandler) ReorderReadingList(c *gin.Context) {
+	l, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		BlogIDs []string `json:"blog_ids"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(body.BlogIDs) != len(l.Entries) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "blog_ids must list every post in the reading list exactly once"})
+		return
+	}
+
+	entries := make([]bookmark.Entry, 0, len(l.Entries))
+	seen := make(map[primitive.ObjectID]bool, len(body.BlogIDs))
+	for _, hex := range body.BlogIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		idx := l.EntryIndex(id)
+		if err != nil || idx < 0 || seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "blog_ids must list every post in the reading list exactly once"})
+			return
+		}
+		seen[id] = true
+		entries = append(entries, l.Entries[idx])
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusOK, l)
+		return
+	}
+
+	// The order only applies to the entries it was chosen for; if the list
+	// changed meanwhile, the caller has to look again
+	updated, err := h.repo.ReorderEntries(context.Background(), l.ID, l.Entries, entries)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": "reading list changed; reload it and try again"})
+		return
+	}
+	h.respondWithList(c, updated, err)
+}
+
+// visibleList loads the reading list in the path if the caller may view it.
+// Lists the caller may not view are reported as not found.
+func (h *BookmarkHandler) visibleList(c *gin.Context) (*bookmark.ReadingList, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reading list ID"})
+		return nil, false
+	}
+
+	l, err := h.repo.GetList(context.Background(), id)
+	if err != nil || !l.VisibleTo(optionalAuthorID(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reading list not found"})
+		return nil, false
+	}
+	return l, true
+}
+
+// ownedList loads the reading list in the path if the caller owns it
+func (h *BookmarkHandler) ownedList(c *gin.Context) (*bookmark.ReadingList, bool) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return nil, false
+	}
+
+	l, ok := h.visibleList(c)
+	if !ok {
+		return nil, false
+	}
+	if l.OwnerID != authorID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can change this reading list"})
+		return nil, false
+	}
+	return l, true
+}
+
+// ownedEntry loads the caller's reading list and checks the post in the
+// path is in it
+func (h *BookmarkHandler) ownedEntry(c *gin.Context) (*bookmark.ReadingList, primitive.ObjectID, bool) {
+	l, ok := h.ownedList(c)
+	if !ok {
+		return nil, primitive.NilObjectID, false
+	}
+
+	blogID, err := primitive.ObjectIDFromHex(c.Param("blog_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return nil, primitive.NilObjectID, false
+	}
+	if l.EntryIndex(blogID) < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post is not in this reading list"})
+		return nil, primitive.NilObjectID, false
+	}
+	return l, blogID, true
+}
+
+func (h *BookmarkHandler) saveList(c *gin.Context, id primitive.ObjectID, update bson.M) {
+	updated, err := h.repo.UpdateList(context.Background(), id, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update reading list"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// respondWithList writes a reading list after a change. A change that
+// matched nothing means the post left the list meanwhile.
+func (h *BookmarkHandler) respondWithList(c *gin.Context, updated *bookmark.ReadingList, err error) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post is not in this reading list"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update reading list"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// listedBlog loads a post readers can see
+func (h *BookmarkHandler) listedBlog(c *gin.Context, hex string) (*blog.Blog, bool) {
+	blogID, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return nil, false
+	}
+
+	b, err := h.blogRepo.GetByID(context.Background(), blogID)
+	if err != nil || b.Hidden {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// blogsByID loads the listed posts among ids
+func (h *BookmarkHandler) blogsByID(ids []primitive.ObjectID) (map[primitive.ObjectID]*blog.Blog, error) {
+	blogs, err := h.blogRepo.GetListedByIDs(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*blog.Blog, len(blogs))
+	for _, b := range blogs {
+		byID[b.ID] = b
+	}
+	return byID, nil
+}
+
+// blogSummary is the part of a post shown in bookmark and reading lists
+func blogSummary(b *blog.Blog) gin.H {
+	return gin.H{
+		"id":         b.ID,
+		"title":      b.Title,
+		"type":       b.Type,
+		"category":   b.Category,
+		"image_url":  b.ImageURL,
+		"author_id":  b.AuthorID,
+		"created_at": b.CreatedAt,
+	}
+}
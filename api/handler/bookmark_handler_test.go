@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/bookmark"
+)
+
+func TestBookmarkBlog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	readerID := primitive.NewObjectID()
+
+	send := func(h *BookmarkHandler, blogID primitive.ObjectID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", readerID.Hex()) })
+		r.PUT("/blogs/:id/bookmark", h.BookmarkBlog)
+
+		req, _ := http.NewRequest("PUT", "/blogs/"+blogID.Hex()+"/bookmark", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("New bookmark is created", func(t *testing.T) {
+		mRepo, mBlog := new(MockBookmarkRepo), new(MockBlogRepo)
+		b := &blog.Blog{ID: primitive.NewObjectID()}
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mRepo.On("Add", mock.Anything, readerID, b.ID).Return(true, nil)
+
+		w := send(NewBookmarkHandler(mRepo, mBlog), b.ID)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Bookmarking again is a no-op", func(t *testing.T) {
+		mRepo, mBlog := new(MockBookmarkRepo), new(MockBlogRepo)
+		b := &blog.Blog{ID: primitive.NewObjectID()}
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mRepo.On("Add", mock.Anything, readerID, b.ID).Return(false, nil)
+
+		w := send(NewBookmarkHandler(mRepo, mBlog), b.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Hidden posts cannot be bookmarked", func(t *testing.T) {
+		mRepo, mBlog := new(MockBookmarkRepo), new(MockBlogRepo)
+		b := &blog.Blog{ID: primitive.NewObjectID(), Hidden: true}
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+
+		w := send(NewBookmarkHandler(mRepo, mBlog), b.ID)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetReadingList_Visibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ownerID := primitive.NewObjectID()
+
+	send := func(h *BookmarkHandler, listID primitive.ObjectID, viewerID *primitive.ObjectID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		if viewerID != nil {
+			r.Use(func(c *gin.Context) { c.Set("author_id", viewerID.Hex()) })
+		}
+		r.GET("/reading-lists/:id", h.GetReadingList)
+
+		req, _ := http.NewRequest("GET", "/reading-lists/"+listID.Hex(), nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	shown, gone := primitive.NewObjectID(), primitive.NewObjectID()
+	private := &bookmark.ReadingList{
+		ID:      primitive.NewObjectID(),
+		OwnerID: ownerID,
+		Name:    "Later",
+		Entries: []bookmark.Entry{{BlogID: gone}, {BlogID: shown, Note: "read twice"}},
+	}
+
+	t.Run("Private list is hidden from others", func(t *testing.T) {
+		mRepo := new(MockBookmarkRepo)
+		mRepo.On("GetList", mock.Anything, private.ID).Return(private, nil)
+		other := primitive.NewObjectID()
+
+		h := NewBookmarkHandler(mRepo, new(MockBlogRepo))
+		assert.Equal(t, http.StatusNotFound, send(h, private.ID, nil).Code)
+		assert.Equal(t, http.StatusNotFound, send(h, private.ID, &other).Code)
+	})
+
+	t.Run("Owner sees entries with notes, minus removed posts", func(t *testing.T) {
+		mRepo, mBlog := new(MockBookmarkRepo), new(MockBlogRepo)
+		mRepo.On("GetList", mock.Anything, private.ID).Return(private, nil)
+		mBlog.On("GetListedByIDs", mock.Anything, []primitive.ObjectID{gone, shown}).
+			Return([]*blog.Blog{{ID: shown, Title: "Kept"}}, nil)
+
+		w := send(NewBookmarkHandler(mRepo, mBlog), private.ID, &ownerID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Entries []struct {
+				Blog struct{ Title string } `json:"blog"`
+				Note string                 `json:"note"`
+			} `json:"entries"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if assert.Len(t, resp.Entries, 1) {
+			assert.Equal(t, "Kept", resp.Entries[0].Blog.Title)
+			assert.Equal(t, "read twice", resp.Entries[0].Note)
+		}
+	})
+}
+
+func TestReorderReadingList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ownerID := primitive.NewObjectID()
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+
+	send := func(h *BookmarkHandler, listID primitive.ObjectID, ids ...primitive.ObjectID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", ownerID.Hex()) })
+		r.PUT("/reading-lists/:id/order", h.ReorderReadingList)
+
+		hexes := []string{}
+		for _, id := range ids {
+			hexes = append(hexes, id.Hex())
+		}
+		body, _ := json.Marshal(gin.H{"blog_ids": hexes})
+		req, _ := http.NewRequest("PUT", "/reading-lists/"+listID.Hex()+"/order", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	newList := func() *bookmark.ReadingList {
+		return &bookmark.ReadingList{
+			ID:      primitive.NewObjectID(),
+			OwnerID: ownerID,
+			Entries: []bookmark.Entry{{BlogID: a, Note: "first"}, {BlogID: b}},
+		}
+	}
+
+	t.Run("Entries keep their notes in the new order", func(t *testing.T) {
+		l := newList()
+		mRepo := new(MockBookmarkRepo)
+		mRepo.On("GetList", mock.Anything, l.ID).Return(l, nil)
+		mRepo.On("ReorderEntries", mock.Anything, l.ID, l.Entries, []bookmark.Entry{{BlogID: b}, {BlogID: a, Note: "first"}}).Return(l, nil)
+
+		w := send(NewBookmarkHandler(mRepo, new(MockBlogRepo)), l.ID, b, a)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mRepo.AssertExpectations(t)
+	})
+
+	t.Run("Order must list every entry exactly once", func(t *testing.T) {
+		l := newList()
+		mRepo := new(MockBookmarkRepo)
+		mRepo.On("GetList", mock.Anything, l.ID).Return(l, nil)
+		h := NewBookmarkHandler(mRepo, new(MockBlogRepo))
+
+		assert.Equal(t, http.StatusBadRequest, send(h, l.ID, a).Code)
+		assert.Equal(t, http.StatusBadRequest, send(h, l.ID, a, a).Code)
+		assert.Equal(t, http.StatusBadRequest, send(h, l.ID, a, primitive.NewObjectID()).Code)
+		mRepo.AssertNotCalled(t, "ReorderEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("A list changed meanwhile is not overwritten", func(t *testing.T) {
+		l := newList()
+		mRepo := new(MockBookmarkRepo)
+		mRepo.On("GetList", mock.Anything, l.ID).Return(l, nil)
+		mRepo.On("ReorderEntries", mock.Anything, l.ID, l.Entries, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+
+		w := send(NewBookmarkHandler(mRepo, new(MockBlogRepo)), l.ID, b, a)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Only the owner can reorder", func(t *testing.T) {
+		l := newList()
+		l.OwnerID = primitive.NewObjectID()
+		l.Public = true
+		mRepo := new(MockBookmarkRepo)
+		mRepo.On("GetList", mock.Anything, l.ID).Return(l, nil)
+
+		w := send(NewBookmarkHandler(mRepo, new(MockBlogRepo)), l.ID, b, a)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestGetBlog_Bookmarked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	readerID := primitive.NewObjectID()
+	b := &blog.Blog{ID: primitive.NewObjectID(), AuthorID: primitive.NewObjectID()}
+
+	send := func(viewerID *primitive.ObjectID) (map[string]interface{}, *MockBookmarkRepo) {
+		mBlog, mAuth, mBookmarks := new(MockBlogRepo), new(MockAuthorRepo), new(MockBookmarkRepo)
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mAuth.On("GetAuthorByID", b.AuthorID).Return(&author.Author{Name: "Ada"}, nil)
+		mBookmarks.On("IsBookmarked", mock.Anything, readerID, b.ID).Return(true, nil)
+		h := NewBlogHandler(mBlog, mAuth, BlogDeps{Bookmarks: mBookmarks})
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		if viewerID != nil {
+			r.Use(func(c *gin.Context) { c.Set("author_id", viewerID.Hex()) })
+		}
+		r.GET("/blogs/:id", h.GetBlog)
+		req, _ := http.NewRequest("GET", "/blogs/"+b.ID.Hex(), nil)
+		r.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp, mBookmarks
+	}
+
+	t.Run("Logged-in reader sees bookmark status", func(t *testing.T) {
+		resp, _ := send(&readerID)
+		assert.Equal(t, true, resp["bookmarked"])
+	})
+
+	t.Run("Anonymous reader does not", func(t *testing.T) {
+		resp, mBookmarks := send(nil)
+		assert.NotContains(t, resp, "bookmarked")
+		mBookmarks.AssertNotCalled(t, "IsBookmarked", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
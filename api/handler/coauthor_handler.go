@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"razorblog-backend/internal/models/blog"
+)
+
+// InviteCoAuthor godoc
+// @Summary Invite a co-author
+// @Description Invites another author to be credited on a post. Inviting someone already listed updates their role.
+// @Tags Co-authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param body body object{author_id=string,role=string} true "Invitee and contribution role (author, reviewer, editor)"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/coauthors [post]
+func (h *BlogHandler) InviteCoAuthor(c *gin.Context) {
+	b, ok := h.ownedBlog(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		AuthorID string                `json:"author_id" binding:"required"`
+		Role     blog.ContributionRole `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if body.Role == "" {
+		body.Role = blog.RoleCoAuthor
+	}
+	if !body.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be author, reviewer or editor"})
+		return
+	}
+
+	inviteeID, err := primitive.ObjectIDFromHex(body.AuthorID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+	if inviteeID == b.AuthorID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the post's author is already credited"})
+		return
+	}
+	if invitee, err := h.authorRepo.GetAuthorByID(inviteeID); err != nil || invitee == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "author not found"})
+		return
+	}
+
+	updated, err := h.repo.InviteCoAuthor(context.Background(), b.ID, inviteeID, body.Role)
+	h.respondCoAuthors(c, updated, err, "blog not found")
+}
+
+// AcceptCoAuthorInvite godoc
+// @Summary Accept a co-author invitation
+// @Description Accepts a pending invitation, granting edit rights on the post
+// @Tags Co-authors
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/coauthors/accept [patch]
+func (h *BlogHandler) AcceptCoAuthorInvite(c *gin.Context) {
+	h.respondToInvite(c, blog.InviteAccepted)
+}
+
+// DeclineCoAuthorInvite godoc
+// @Summary Decline a co-author invitation
+// @Description Declines a pending invitation
+// @Tags Co-authors
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/coauthors/decline [patch]
+func (h *BlogHandler) DeclineCoAuthorInvite(c *gin.Context) {
+	h.respondToInvite(c, blog.InviteDeclined)
+}
+
+// RemoveCoAuthor godoc
+// @Summary Remove a co-author
+// @Description Removes a co-author credit. The owner can remove anyone; co-authors can remove themselves.
+// @Tags Co-authors
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param author_id path string true "Co-author ID"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/coauthors/{author_id} [delete]
+func (h *BlogHandler) RemoveCoAuthor(c *gin.Context) {
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	b, ok := h.blogFromParam(c)
+	if !ok {
+		return
+	}
+
+	coAuthorID, err := primitive.ObjectIDFromHex(c.Param("author_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+	if callerID != b.AuthorID && callerID != coAuthorID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the post's author can remove other co-authors"})
+		return
+	}
+
+	updated, err := h.repo.RemoveCoAuthor(context.Background(), b.ID, coAuthorID)
+	h.respondCoAuthors(c, updated, err, "co-author not found")
+}
+
+// ReorderCoAuthors godoc
+// @Summary Reorder co-author credits
+// @Description Sets the display order of co-authors. The list must contain every current co-author exactly once.
+// @Tags Co-authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param body body object{author_ids=[]string} true "Co-author IDs in display order"
+// @Success 200 {object} blog.Blog
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /blogs/{id}/coauthors/order [put]
+func (h *BlogHandler) ReorderCoAuthors(c *gin.Context) {
+	b, ok := h.ownedBlog(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		AuthorIDs []string `json:"author_ids"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || len(body.AuthorIDs) != len(b.CoAuthors) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "author_ids must list every co-author exactly once"})
+		return
+	}
+
+	coAuthors := make([]blog.CoAuthor, 0, len(b.CoAuthors))
+	seen := make(map[primitive.ObjectID]bool, len(body.AuthorIDs))
+	for _, idStr := range body.AuthorIDs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		idx := b.CoAuthorIndex(id)
+		if err != nil || idx < 0 || seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "author_ids must list every co-author exactly once"})
+			return
+		}
+		seen[id] = true
+		coAuthors = append(coAuthors, b.CoAuthors[idx])
+	}
+
+	if len(coAuthors) == 0 {
+		c.JSON(http.StatusOK, b)
+		return
+	}
+
+	// The order only applies to the list it was chosen for; if co-authors
+	// changed meanwhile, the caller has to look again
+	updated, err := h.repo.ReorderCoAuthors(context.Background(), b.ID, b.CoAuthors, coAuthors)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": "co-authors changed; reload them and try again"})
+		return
+	}
+	h.respondCoAuthors(c, updated, err, "blog not found")
+}
+
+// respondToInvite records the logged-in author's answer to a pending invitation
+func (h *BlogHandler) respondToInvite(c *gin.Context, status blog.InviteStatus) {
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	b, ok := h.blogFromParam(c)
+	if !ok {
+		return
+	}
+
+	idx := b.CoAuthorIndex(callerID)
+	if idx < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no invitation for this post"})
+		return
+	}
+	if b.CoAuthors[idx].Status != blog.InvitePending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invitation already " + string(b.CoAuthors[idx].Status)})
+		return
+	}
+
+	updated, err := h.repo.RespondToInvite(context.Background(), b.ID, callerID, status)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": "invitation is no longer pending"})
+		return
+	}
+	h.respondCoAuthors(c, updated, err, "blog not found")
+}
+
+// respondCoAuthors writes the blog after a co-author change. A change that
+// matched nothing is reported as notFound.
+func (h *BlogHandler) respondCoAuthors(c *gin.Context, updated *blog.Blog, err error, notFound string) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// blogFromParam loads the blog referenced by the :id URL parameter
+func (h *BlogHandler) blogFromParam(c *gin.Context) (*blog.Blog, bool) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return nil, false
+	}
+
+	b, err := h.repo.GetByID(context.Background(), blogID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// ownedBlog loads the blog in the URL and checks the caller is its owner
+func (h *BlogHandler) ownedBlog(c *gin.Context) (*blog.Blog, bool) {
+	callerID, ok := currentAuthorID(c)
+	if !ok {
+		return nil, false
+	}
+
+	b, ok := h.blogFromParam(c)
+	if !ok {
+		return nil, false
+	}
+
+	if b.AuthorID != callerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the post's author can do this"})
+		return nil, false
+	}
+	return b, true
+}
+
+// credits lists everyone credited on a post: the owner first, then accepted
+// co-authors in their chosen order. Names are cached across calls.
+func (h *BlogHandler) credits(b *blog.Blog, names map[primitive.ObjectID]string) []gin.H {
+	credits := []gin.H{{
+		"author_id": b.AuthorID,
+		"name":      h.authorName(b.AuthorID, names),
+		"role":      blog.RoleCoAuthor,
+	}}
+
+	for _, ca := range b.CoAuthors {
+		if ca.Status != blog.InviteAccepted {
+			continue
+		}
+		credits = append(credits, gin.H{
+			"author_id": ca.AuthorID,
+			"name":      h.authorName(ca.AuthorID, names),
+			"role":      ca.Role,
+		})
+	}
+	return credits
+}
+
+// authorName looks up an author's display name, memoising results in names
+func (h *BlogHandler) authorName(id primitive.ObjectID, names map[primitive.ObjectID]string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+
+	name := ""
+	if authorData, err := h.authorRepo.GetAuthorByID(id); err == nil && authorData != nil {
+		name = authorData.Name
+	}
+	names[id] = name
+	return name
+}
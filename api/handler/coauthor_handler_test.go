@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"razorblog-backend/internal/models/blog"
+)
+
+func TestAcceptCoAuthorInvite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ownerID, inviteeID := primitive.NewObjectID(), primitive.NewObjectID()
+	b := &blog.Blog{ID: primitive.NewObjectID(), AuthorID: ownerID, CoAuthors: []blog.CoAuthor{
+		{AuthorID: inviteeID, Role: blog.RoleCoAuthor, Status: blog.InvitePending},
+	}}
+	accept := func(mBlog *MockBlogRepo) *httptest.ResponseRecorder {
+		h := NewBlogHandler(mBlog, new(MockAuthorRepo))
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", inviteeID.Hex()) })
+		r.PATCH("/blogs/:id/coauthors/accept", h.AcceptCoAuthorInvite)
+		req, _ := http.NewRequest("PATCH", "/blogs/"+b.ID.Hex()+"/coauthors/accept", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("SUCCESS: Accepts only the caller's own entry", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mBlog.On("RespondToInvite", mock.Anything, b.ID, inviteeID, blog.InviteAccepted).Return(b, nil)
+
+		assert.Equal(t, http.StatusOK, accept(mBlog).Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Invitation answered or withdrawn meanwhile", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, b.ID).Return(b, nil)
+		mBlog.On("RespondToInvite", mock.Anything, b.ID, inviteeID, blog.InviteAccepted).Return(nil, mongo.ErrNoDocuments)
+
+		assert.Equal(t, http.StatusConflict, accept(mBlog).Code)
+	})
+}
@@ -2,34 +2,129 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/blog"
 	"razorblog-backend/internal/repository"
 	models "razorblog-backend/internal/models/comment"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/service"
+	"razorblog-backend/internal/spam"
 )
 
 // CommentHandler handles HTTP requests for comments
 type CommentHandler struct {
-	repo *repository.CommentRepository
+	repo       *repository.CommentRepository
+	blogRepo   repository.IBlogRepository
+	authorRepo repository.IAuthorRepository
+	scorer     *spam.Scorer
+	mentions   service.IMentionService
+	notifier   service.INotificationService
+	broker     events.Broker
+	analytics  repository.IAnalyticsRepository
+	settings   CommentSettings
 }
 
-func NewCommentHandler(repo *repository.CommentRepository) *CommentHandler {
-	return &CommentHandler{repo: repo}
+// CommentSettings holds the site-wide comment policy
+type CommentSettings struct {
+	Premoderation bool          // Hold every new comment for moderation
+	EditWindow    time.Duration // How long after posting a comment its writer may edit it; 0 disables editing
+}
+
+// editTokenHeader carries the edit token of an anonymous comment
+const editTokenHeader = "X-Edit-Token"
+
+// CommentDeps holds the collaborators of a CommentHandler besides its
+// repository. Optional ones left nil are skipped.
+type CommentDeps struct {
+	BlogRepo   repository.IBlogRepository
+	AuthorRepo repository.IAuthorRepository
+	Scorer     *spam.Scorer
+	Mentions   service.IMentionService
+	Notifier   service.INotificationService
+	Broker     events.Broker
+	Analytics  repository.IAnalyticsRepository
+	Settings   CommentSettings
+}
+
+func NewCommentHandler(repo *repository.CommentRepository, deps ...CommentDeps) *CommentHandler {
+	var d CommentDeps
+	if len(deps) > 0 {
+		d = deps[0]
+	}
+	return &CommentHandler{repo: repo, blogRepo: d.BlogRepo, authorRepo: d.AuthorRepo, scorer: d.Scorer, mentions: d.Mentions, notifier: d.Notifier, broker: d.Broker, analytics: d.Analytics, settings: d.Settings}
+}
+
+// commenter is the identity a comment or like is recorded under
+type commenter struct {
+	Name      string
+	AuthorID  *primitive.ObjectID
+	AvatarURL string
+}
+
+// identify resolves who is commenting. Logged-in authors always comment
+// under their account name; anonymous readers supply a username, which may
+// not be the name of a registered author.
+func (h *CommentHandler) identify(c *gin.Context, username string) (*commenter, bool) {
+	if _, loggedIn := c.Get("author_id"); loggedIn {
+		authorID, ok := currentAuthorID(c)
+		if !ok {
+			return nil, false
+		}
+		a, err := h.authorRepo.GetAuthorByID(authorID)
+		if err != nil || a == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
+			return nil, false
+		}
+		return &commenter{Name: a.Name, AuthorID: &authorID, AvatarURL: a.AvatarURL}, true
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return nil, false
+	}
+
+	registered, err := h.authorRepo.GetAuthorByName(username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if registered != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "this name belongs to a registered author; log in to use it"})
+		return nil, false
+	}
+
+	return &commenter{Name: username}, true
 }
 
 // CreateComment godoc
 // @Summary Create a new comment
-// @Description Adds a comment to a blog post
+// @Description Adds a comment to a blog post. With a JWT the comment is linked to the author's account; anonymous usernames cannot match a registered author. Anonymous comments are returned once with an edit_token that proves ownership later.
 // @Tags Comments
 // @Accept json
 // @Produce json
-// @Param comment body map[string]string true "Comment info (blog_id, username, content)"
+// @Param comment body map[string]string true "Comment info (blog_id, username, content, optional parent_id to reply)"
 // @Success 201 {object} models.Comment
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /comments [post]
 func (h *CommentHandler) CreateComment(c *gin.Context) {
@@ -39,20 +134,228 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
-	if cmt.BlogID.IsZero() || cmt.Username == "" || cmt.Content == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "blog_id, username, and content are required"})
+	if cmt.BlogID.IsZero() || cmt.Content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "blog_id and content are required"})
+		return
+	}
+
+	b, err := h.blogRepo.GetByID(context.Background(), cmt.BlogID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
 		return
 	}
 
+	who, ok := h.identify(c, cmt.Username)
+	if !ok {
+		return
+	}
+	cmt.Username = who.Name
+	cmt.AuthorID = who.AuthorID
+	cmt.AvatarURL = who.AvatarURL
+	cmt.Verified = who.AuthorID != nil
+	cmt.IsPostAuthor = who.AuthorID != nil && b.IsCredited(*who.AuthorID)
+	cmt.EditedAt = nil
+	cmt.History = nil
+	cmt.Withdrawn = false
+	cmt.Content, cmt.Mentions = resolveMentions(h.mentions, cmt.Content)
+
+	// Anonymous writers get a token to edit or delete their comment later
+	editToken := ""
+	cmt.EditTokenHash = ""
+	if who.AuthorID == nil {
+		var err error
+		if editToken, cmt.EditTokenHash, err = newEditToken(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create comment"})
+			return
+		}
+	}
+
+	// Pre-moderated and likely spam comments wait in the moderation queue;
+	// the post's own authors are trusted
+	likelySpam := h.scoreSpam(c, &cmt)
+	cmt.Status = models.StatusApproved
+	if !cmt.IsPostAuthor && (h.settings.Premoderation || b.PremoderateComments || likelySpam) {
+		cmt.Status = models.StatusPending
+	}
+
+	// Replies inherit their thread position from the parent
+	cmt.Ancestors = nil
+	cmt.Depth = 0
+	var parent *models.Comment
+	if cmt.ParentID != nil {
+		p, err := h.repo.GetByID(context.Background(), *cmt.ParentID)
+		if err != nil || p.BlogID != cmt.BlogID || !p.IsVisible() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent comment not found on this blog"})
+			return
+		}
+		if p.Depth >= models.MaxDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "replies cannot be nested any deeper"})
+			return
+		}
+		parent = p
+		cmt.Ancestors = append(append([]primitive.ObjectID{}, p.Ancestors...), p.ID)
+		cmt.Depth = p.Depth + 1
+	}
+
 	created, err := h.repo.Create(context.Background(), &cmt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create comment"})
 		return
 	}
 
+	if parent != nil && created.Status == models.StatusApproved {
+		_ = h.repo.IncrementReplies(context.Background(), parent.ID, 1)
+	}
+
+	// Held comments notify nobody, so spam cannot fan out through mentions.
+	// They are announced once a moderator approves them.
+	if created.IsVisible() {
+		announceComment(h.mentions, h.notifier, h.broker, h.analytics, b, created)
+	}
+
+	created.EditToken = editToken
 	c.JSON(http.StatusCreated, created)
 }
 
+// announceComment tells the mentioned authors, the post's authors and its
+// live readers about a comment readers can now see, and counts it in the
+// post's analytics. Post authors who were mentioned only hear about it once.
+func announceComment(mentions service.IMentionService, notifier service.INotificationService, broker events.Broker, analyticsRepo repository.IAnalyticsRepository, b *blog.Blog, cmt *models.Comment) {
+	notifyMentions(mentions, nil, cmt.Mentions, service.MentionEvent{
+		ActorID:   cmt.AuthorID,
+		ActorName: cmt.Username,
+		BlogID:    &cmt.BlogID,
+		CommentID: &cmt.ID,
+	})
+	if b != nil {
+		notifyPostAuthors(notifier, b, cmt.Mentions, notification.Notification{
+			Type:      notification.TypeComment,
+			ActorID:   cmt.AuthorID,
+			ActorName: cmt.Username,
+			BlogID:    &cmt.BlogID,
+			CommentID: &cmt.ID,
+		})
+	}
+	publishEvent(broker, cmt.BlogID, events.TypeComment, cmt)
+	recordActivity(analyticsRepo, analytics.Event{BlogID: cmt.BlogID, Counts: analytics.Counts{Comments: 1}})
+}
+
+// EditComment godoc
+// @Summary Edit a comment
+// @Description Changes the text of a comment within the edit window. The writer proves ownership with their JWT or, for anonymous comments, the edit token in the X-Edit-Token header. Earlier versions are kept in the comment's history.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param X-Edit-Token header string false "Edit token of an anonymous comment"
+// @Param body body object{content=string} true "New comment text"
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /comments/{id} [put]
+func (h *CommentHandler) EditComment(c *gin.Context) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	var body struct {
+		Content string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content is required"})
+		return
+	}
+
+	cmt, err := h.repo.GetByID(context.Background(), commentID)
+	if err != nil || cmt.Withdrawn {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return
+	}
+	if !h.writtenByCaller(c, cmt) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only edit your own comments"})
+		return
+	}
+	if h.settings.EditWindow <= 0 || time.Since(cmt.CreatedAt) > h.settings.EditWindow {
+		c.JSON(http.StatusForbidden, gin.H{"error": "the edit window for this comment has closed"})
+		return
+	}
+	content, mentioned := resolveMentions(h.mentions, body.Content)
+	if content == cmt.Content {
+		c.JSON(http.StatusOK, cmt)
+		return
+	}
+
+	// Edits are scored again so an approved comment cannot be turned into spam
+	next := *cmt
+	next.Content = content
+	likelySpam := h.scoreSpam(c, &next)
+	fields := bson.M{
+		"mentions":     mentioned,
+		"fingerprint":  next.Fingerprint,
+		"spam_score":   next.SpamScore,
+		"spam_reasons": next.SpamReasons,
+	}
+	heldBack := likelySpam && !cmt.IsPostAuthor && cmt.IsVisible()
+	if heldBack {
+		fields["status"] = models.StatusPending
+	}
+
+	updated, err := h.repo.Edit(context.Background(), cmt, content, fields)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusConflict, gin.H{"error": "the comment changed while you were editing it; please try again"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to edit comment"})
+		return
+	}
+
+	if heldBack && cmt.ParentID != nil {
+		_ = h.repo.IncrementReplies(context.Background(), *cmt.ParentID, -1)
+	}
+	if updated.IsVisible() {
+		notifyMentions(h.mentions, cmt.Mentions, updated.Mentions, service.MentionEvent{
+			ActorID:   updated.AuthorID,
+			ActorName: updated.Username,
+			BlogID:    &updated.BlogID,
+			CommentID: &updated.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// scoreSpam records the spam verdict on a new comment and reports whether
+// it should be held for moderation. Scoring failures let the comment through.
+func (h *CommentHandler) scoreSpam(c *gin.Context, cmt *models.Comment) bool {
+	cmt.IP = c.ClientIP()
+	cmt.Fingerprint = spam.Fingerprint(cmt.Content)
+	cmt.SpamScore = 0
+	cmt.SpamReasons = nil
+	cmt.TrainedAs = ""
+	if h.scorer == nil {
+		return false
+	}
+
+	res, err := h.scorer.Score(context.Background(), spam.Input{
+		Content:  cmt.Content,
+		Username: cmt.Username,
+		IP:       cmt.IP,
+	})
+	if err != nil {
+		log.Printf("⚠️ Spam scoring failed: %v", err)
+		return false
+	}
+
+	cmt.SpamScore = res.Score
+	cmt.SpamReasons = res.Reasons
+	return h.scorer.Flagged(res)
+}
+
 // ListComments godoc
 // @Summary List comments for a blog
 // @Description Returns a paginated list of comments for a specific blog
@@ -87,7 +390,7 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 
 // LikeComment godoc
 // @Summary Like a comment
-// @Description Adds a like from a user to a specific comment
+// @Description Adds a like to a specific comment, from the logged-in author or an anonymous username. Liking twice has no further effect.
 // @Tags Comments
 // @Accept json
 // @Produce json
@@ -95,29 +398,312 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 // @Param body body map[string]string true "Username liking the comment"
 // @Success 200 {object} models.Comment
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /comments/{id}/like [post]
 func (h *CommentHandler) LikeComment(c *gin.Context) {
-	commentIDStr := c.Param("id")
-	commentID, err := primitive.ObjectIDFromHex(commentIDStr)
+	h.setLike(c, h.repo.Like)
+}
+
+// UnlikeComment godoc
+// @Summary Unlike a comment
+// @Description Removes a like from a specific comment, from the logged-in author or an anonymous username. Unliking a comment that was not liked has no effect.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param body body map[string]string true "Username removing the like"
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id}/unlike [post]
+func (h *CommentHandler) UnlikeComment(c *gin.Context) {
+	h.setLike(c, h.repo.Unlike)
+}
+
+// setLike resolves the reader and applies a like or unlike
+func (h *CommentHandler) setLike(c *gin.Context, apply func(context.Context, primitive.ObjectID, string) (*models.Comment, error)) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
 		return
 	}
 
+	// The body is optional for logged-in authors
 	var body struct {
 		Username string `json:"username"`
 	}
-	if err := c.ShouldBindJSON(&body); err != nil || body.Username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+	_ = c.ShouldBindJSON(&body)
+
+	who, ok := h.identify(c, body.Username)
+	if !ok {
 		return
 	}
 
-	updated, err := h.repo.Like(context.Background(), commentID, body.Username)
+	updated, err := apply(context.Background(), commentID, who.Name)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if updated.IsVisible() {
+		publishEvent(h.broker, updated.BlogID, events.TypeCommentLikes, gin.H{"comment_id": updated.ID, "likes": updated.Likes})
+	}
+
 	c.JSON(http.StatusOK, updated)
 }
 
+// ListCommentTree godoc
+// @Summary List threaded comments for a blog
+// @Description Returns a page of top-level comments with their nested replies
+// @Tags Comments
+// @Produce json
+// @Param blog_id path string true "Blog ID"
+// @Param limit query int false "Number of threads" default(10)
+// @Param skip query int false "Number of threads to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /comments/{blog_id}/tree [get]
+func (h *CommentHandler) ListCommentTree(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("blog_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	roots, replies, err := h.repo.ListThreads(context.Background(), blogID, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+
+	total, err := h.repo.CountThreads(context.Background(), blogID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": models.BuildTree(roots, replies),
+		"total":    total,
+		"limit":    limit,
+		"skip":     skip,
+	})
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Deletes a comment. Its writer (by JWT or X-Edit-Token) can withdraw it, leaving a "[deleted]" tombstone if it has replies. The author of the blog post can move any comment to the trash.
+// @Tags Comments
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param X-Edit-Token header string false "Edit token of an anonymous comment"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	cmt, err := h.repo.GetByID(context.Background(), commentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return
+	}
+
+	if h.writtenByCaller(c, cmt) {
+		h.withdraw(c, cmt)
+		return
+	}
+
+	if _, loggedIn := c.Get("author_id"); !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "log in or provide the comment's edit token"})
+		return
+	}
+	if !h.ownsBlog(c, cmt.BlogID) {
+		return
+	}
+
+	if err := h.repo.Delete(context.Background(), commentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return
+	}
+	if cmt.ParentID != nil && cmt.IsVisible() {
+		_ = h.repo.IncrementReplies(context.Background(), *cmt.ParentID, -1)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment moved to trash"})
+}
+
+// ListTrashedComments godoc
+// @Summary List trashed comments for a blog
+// @Description Returns the deleted comments of a blog post owned by the logged-in author
+// @Tags Comments
+// @Produce json
+// @Param blog_id path string true "Blog ID"
+// @Success 200 {array} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /comments/{blog_id}/trash [get]
+func (h *CommentHandler) ListTrashedComments(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("blog_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	if !h.ownsBlog(c, blogID) {
+		return
+	}
+
+	comments, err := h.repo.ListTrash(context.Background(), blogID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+	if comments == nil {
+		comments = []*models.Comment{}
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// RestoreComment godoc
+// @Summary Restore a trashed comment
+// @Description Takes a comment out of the trash. Only the author of the blog post can do this.
+// @Tags Comments
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /comments/{id}/restore [patch]
+func (h *CommentHandler) RestoreComment(c *gin.Context) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	cmt, err := h.repo.GetTrashedByID(context.Background(), commentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found in trash"})
+		return
+	}
+
+	if !h.ownsBlog(c, cmt.BlogID) {
+		return
+	}
+
+	if err := h.repo.Restore(context.Background(), commentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found in trash"})
+		return
+	}
+	cmt.DeletedAt = nil
+	if cmt.ParentID != nil && cmt.IsVisible() {
+		_ = h.repo.IncrementReplies(context.Background(), *cmt.ParentID, 1)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment restored"})
+}
+
+// withdraw deletes a comment on behalf of its writer. Comments with
+// replies become tombstones so the thread stays readable; others go to the
+// trash.
+func (h *CommentHandler) withdraw(c *gin.Context, cmt *models.Comment) {
+	if cmt.ReplyCount > 0 {
+		if err := h.repo.Withdraw(context.Background(), cmt.ID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "comment deleted"})
+		return
+	}
+
+	if err := h.repo.Delete(context.Background(), cmt.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return
+	}
+	if cmt.ParentID != nil && cmt.IsVisible() {
+		_ = h.repo.IncrementReplies(context.Background(), *cmt.ParentID, -1)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted"})
+}
+
+// writtenByCaller reports whether the caller wrote the comment: a logged-in
+// author matching the comment's account, or anyone holding the edit token
+// of an anonymous comment
+func (h *CommentHandler) writtenByCaller(c *gin.Context, cmt *models.Comment) bool {
+	if cmt.Withdrawn {
+		return false
+	}
+
+	if cmt.AuthorID != nil {
+		idStr, ok := c.Get("author_id")
+		if !ok {
+			return false
+		}
+		s, _ := idStr.(string)
+		authorID, err := primitive.ObjectIDFromHex(s)
+		return err == nil && authorID == *cmt.AuthorID
+	}
+
+	token := c.GetHeader(editTokenHeader)
+	if token == "" || cmt.EditTokenHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashEditToken(token)), []byte(cmt.EditTokenHash)) == 1
+}
+
+// newEditToken returns a random edit token and the hash stored in its place
+func newEditToken() (string, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashEditToken(token), nil
+}
+
+func hashEditToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ownsBlog checks that the logged-in author owns the given blog post
+func (h *CommentHandler) ownsBlog(c *gin.Context, blogID primitive.ObjectID) bool {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return false
+	}
+
+	b, err := h.blogRepo.GetByID(context.Background(), blogID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return false
+	}
+
+	if b.AuthorID != authorID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the post's author can manage its comments"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	models "razorblog-backend/internal/models/comment"
+	"razorblog-backend/internal/repository"
+)
+
+func TestCreateComment_Identity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("REJECT: Anonymous commenter impersonates a registered author", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewCommentHandler(nil, CommentDeps{BlogRepo: mBlog, AuthorRepo: mAuth})
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+		mAuth.On("GetAuthorByName", "victor").Return(&author.Author{Name: "Victor"}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.POST("/comments", h.CreateComment)
+
+		body := `{"blog_id":"` + blogID.Hex() + `","username":"victor","content":"Great post"}`
+		req, _ := http.NewRequest("POST", "/comments", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("REJECT: Anonymous commenter without a username", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewCommentHandler(nil, CommentDeps{BlogRepo: mBlog, AuthorRepo: mAuth})
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.POST("/comments", h.CreateComment)
+
+		body := `{"blog_id":"` + blogID.Hex() + `","content":"Who am I?"}`
+		req, _ := http.NewRequest("POST", "/comments", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mAuth.AssertNotCalled(t, "GetAuthorByName", mock.Anything)
+	})
+}
+
+func TestWrittenByCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCommentHandler(nil)
+
+	token, hash, err := newEditToken()
+	assert.NoError(t, err)
+	anonymous := &models.Comment{Username: "ana", EditTokenHash: hash}
+
+	writerID := primitive.NewObjectID()
+	registered := &models.Comment{Username: "Victor", AuthorID: &writerID}
+
+	request := func(authorID, editToken string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("PUT", "/comments/x", nil)
+		if authorID != "" {
+			c.Set("author_id", authorID)
+		}
+		if editToken != "" {
+			c.Request.Header.Set(editTokenHeader, editToken)
+		}
+		return c
+	}
+
+	assert.True(t, h.writtenByCaller(request("", token), anonymous), "anonymous writer with their token")
+	assert.False(t, h.writtenByCaller(request("", "wrong"), anonymous), "wrong token")
+	assert.False(t, h.writtenByCaller(request("", ""), anonymous), "no token")
+	assert.True(t, h.writtenByCaller(request(writerID.Hex(), ""), registered), "logged-in writer")
+	assert.False(t, h.writtenByCaller(request(primitive.NewObjectID().Hex(), ""), registered), "another author")
+	assert.False(t, h.writtenByCaller(request("", token), registered), "edit tokens do not unlock account comments")
+
+	anonymous.Withdrawn = true
+	assert.False(t, h.writtenByCaller(request("", token), anonymous), "withdrawn comments cannot be touched")
+}
+
+// commentDoc turns a comment into a document the mock deployment can return
+func commentDoc(cmt *models.Comment) bson.D {
+	raw, _ := bson.Marshal(cmt)
+	var d bson.D
+	_ = bson.Unmarshal(raw, &d)
+	return d
+}
+
+func TestCommentTrash_ReplyCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	ownerID := primitive.NewObjectID()
+	blogID := primitive.NewObjectID()
+	parentID := primitive.NewObjectID()
+
+	// replyCountChange is the reply_count delta sent to the parent, or 0
+	replyCountChange := func(mt *mtest.T) int32 {
+		var delta int32
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			if e.CommandName != "update" {
+				continue
+			}
+			u := e.Command.Lookup("updates").Array().Index(0).Value().Document()
+			if u.Lookup("q", "_id").ObjectID() != parentID {
+				continue
+			}
+			delta += u.Lookup("u", "$inc", "reply_count").Int32()
+		}
+		return delta
+	}
+	send := func(mt *mtest.T, method, path string) int {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, AuthorID: ownerID}, nil)
+		h := NewCommentHandler(repository.NewCommentRepository(mt.DB), CommentDeps{BlogRepo: mBlog})
+
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("author_id", ownerID.Hex()) })
+		r.DELETE("/comments/:id", h.DeleteComment)
+		r.PATCH("/comments/:id/restore", h.RestoreComment)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	ok := func() bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+	}
+
+	mt.Run("Trashing a reply takes it off the parent's count", func(mt *mtest.T) {
+		reply := &models.Comment{ID: primitive.NewObjectID(), BlogID: blogID, ParentID: &parentID, Status: models.StatusApproved}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(reply)), ok(), ok())
+
+		assert.Equal(mt, http.StatusOK, send(mt, "DELETE", "/comments/"+reply.ID.Hex()))
+		assert.Equal(mt, int32(-1), replyCountChange(mt))
+	})
+
+	mt.Run("Restoring a reply puts it back on the parent's count", func(mt *mtest.T) {
+		deletedAt := time.Now()
+		reply := &models.Comment{ID: primitive.NewObjectID(), BlogID: blogID, ParentID: &parentID, Status: models.StatusApproved, DeletedAt: &deletedAt}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(reply)), ok(), ok())
+
+		assert.Equal(mt, http.StatusOK, send(mt, "PATCH", "/comments/"+reply.ID.Hex()+"/restore"))
+		assert.Equal(mt, int32(1), replyCountChange(mt))
+	})
+
+	mt.Run("Replies held for moderation were never counted", func(mt *mtest.T) {
+		reply := &models.Comment{ID: primitive.NewObjectID(), BlogID: blogID, ParentID: &parentID, Status: models.StatusPending}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(reply)), ok())
+
+		assert.Equal(mt, http.StatusOK, send(mt, "DELETE", "/comments/"+reply.ID.Hex()))
+		assert.Equal(mt, int32(0), replyCountChange(mt))
+	})
+}
+
+func TestCreateComment_DepthCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	blogID := primitive.NewObjectID()
+	// parentAt is a visible comment on the blog nested depth levels deep
+	parentAt := func(depth int) *models.Comment {
+		p := &models.Comment{ID: primitive.NewObjectID(), BlogID: blogID, Depth: depth, Status: models.StatusApproved}
+		for i := 0; i < depth; i++ {
+			p.Ancestors = append(p.Ancestors, primitive.NewObjectID())
+		}
+		return p
+	}
+	reply := func(mt *mtest.T, parentID primitive.ObjectID) *httptest.ResponseRecorder {
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+		mAuth := new(MockAuthorRepo)
+		mAuth.On("GetAuthorByName", "guest").Return((*author.Author)(nil), nil)
+		h := NewCommentHandler(repository.NewCommentRepository(mt.DB), CommentDeps{BlogRepo: mBlog, AuthorRepo: mAuth})
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.POST("/comments", h.CreateComment)
+
+		body := `{"blog_id":"` + blogID.Hex() + `","parent_id":"` + parentID.Hex() + `","username":"guest","content":"Agreed"}`
+		req, _ := http.NewRequest("POST", "/comments", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	mt.Run("Replies may reach the maximum depth", func(mt *mtest.T) {
+		parent := parentAt(models.MaxDepth - 1)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(parent)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		w := reply(mt, parent.ID)
+
+		assert.Equal(mt, http.StatusCreated, w.Code, w.Body.String())
+		mt.GetStartedEvent() // find parent
+		inserted := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(mt, int32(models.MaxDepth), inserted.Lookup("depth").Int32())
+		ancestors, _ := inserted.Lookup("ancestors").Array().Values()
+		assert.Len(mt, ancestors, models.MaxDepth)
+		assert.Equal(mt, parent.ID, ancestors[len(ancestors)-1].ObjectID())
+	})
+
+	mt.Run("REJECT: Replies nested below the maximum depth", func(mt *mtest.T) {
+		parent := parentAt(models.MaxDepth)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(parent)))
+
+		w := reply(mt, parent.ID)
+
+		assert.Equal(mt, http.StatusBadRequest, w.Code)
+		assert.Contains(mt, w.Body.String(), "nested any deeper")
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			assert.NotEqual(mt, "insert", e.CommandName)
+		}
+	})
+}
+
+func TestListCommentTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Nests replies under their threads", func(mt *mtest.T) {
+		blogID := primitive.NewObjectID()
+		root := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "blog_id", Value: blogID}, {Key: "content", Value: "root"}}
+		rootID := root[0].Value.(primitive.ObjectID)
+		child := bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()}, {Key: "blog_id", Value: blogID}, {Key: "content", Value: "child"},
+			{Key: "parent_id", Value: rootID}, {Key: "ancestors", Value: bson.A{rootID}}, {Key: "depth", Value: 1},
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, root),
+			mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, child),
+			mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, bson.D{{Key: "n", Value: 7}}),
+		)
+
+		h := NewCommentHandler(repository.NewCommentRepository(mt.DB))
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/comments/:blog_id/tree", h.ListCommentTree)
+
+		req, _ := http.NewRequest("GET", "/comments/"+blogID.Hex()+"/tree?limit=1", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(mt, http.StatusOK, w.Code)
+		var resp struct {
+			Comments []struct {
+				Content string `json:"content"`
+				Replies []struct {
+					Content string        `json:"content"`
+					Replies []interface{} `json:"replies"`
+				} `json:"replies"`
+			} `json:"comments"`
+			Total int64 `json:"total"`
+			Limit int64 `json:"limit"`
+		}
+		assert.NoError(mt, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(mt, int64(7), resp.Total)
+		assert.Equal(mt, int64(1), resp.Limit)
+		if assert.Len(mt, resp.Comments, 1) && assert.Len(mt, resp.Comments[0].Replies, 1) {
+			assert.Equal(mt, "root", resp.Comments[0].Content)
+			assert.Equal(mt, "child", resp.Comments[0].Replies[0].Content)
+			assert.NotNil(mt, resp.Comments[0].Replies[0].Replies)
+		}
+	})
+
+	mt.Run("REJECT: Invalid blog ID", func(mt *mtest.T) {
+		h := NewCommentHandler(repository.NewCommentRepository(mt.DB))
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/comments/:blog_id/tree", h.ListCommentTree)
+
+		req, _ := http.NewRequest("GET", "/comments/not-an-id/tree", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(mt, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// currentAuthorID reads the logged-in author set by the auth middleware.
+// It writes a 401 response and returns false when there is none.
+func currentAuthorID(c *gin.Context) (primitive.ObjectID, bool) {
+	authorID, exists := c.Get("author_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return primitive.NilObjectID, false
+	}
+
+	idStr, _ := authorID.(string)
+	objID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid author ID"})
+		return primitive.NilObjectID, false
+	}
+
+	return objID, true
+}
+
+// optionalAuthorID reads the logged-in author when the optional auth
+// middleware found one, or returns nil for anonymous readers
+func optionalAuthorID(c *gin.Context) *primitive.ObjectID {
+	authorID, exists := c.Get("author_id")
+	if !exists {
+		return nil
+	}
+	idStr, _ := authorID.(string)
+	objID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return nil
+	}
+	return &objID
+}
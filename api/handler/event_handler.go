@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/repository"
+)
+
+// eventHeartbeat keeps idle streams from being closed by proxies
+const eventHeartbeat = 25 * time.Second
+
+// EventHandler streams live updates about a post to its readers
+type EventHandler struct {
+	broker   events.Broker
+	blogRepo repository.IBlogRepository
+}
+
+func NewEventHandler(broker events.Broker, blogRepo repository.IBlogRepository) *EventHandler {
+	return &EventHandler{broker: broker, blogRepo: blogRepo}
+}
+
+// StreamBlogEvents godoc
+// @Summary Stream live updates for a blog
+// @Description Server-Sent Events stream for readers on a post page. It starts with a "readers" event holding the current reader and like counts, then sends "comment" (a new comment), "likes" (the post's like count), "comment_likes" (a comment's like count) and "readers" events as they happen, with a "ping" every 25 seconds.
+// @Tags Blogs
+// @Produce text/event-stream
+// @Param id path string true "Blog ID"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /blogs/{id}/events [get]
+func (h *EventHandler) StreamBlogEvents(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+
+	b, err := h.blogRepo.GetByID(context.Background(), blogID)
+	if err != nil || b.Hidden {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	stream, cancel := h.broker.Subscribe(blogID)
+	defer cancel()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+
+	c.SSEvent(events.TypeReaders, gin.H{"readers": b.Readers, "likes": len(b.Likes)})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e, ok := <-stream:
+			if !ok {
+				return false
+			}
+			c.SSEvent(e.Type, e.Data)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().Unix()})
+			return true
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/blog"
+)
+
+func TestStreamBlogEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("STREAM: Initial counts then published events", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		broker := events.NewMemoryBroker()
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, Readers: 7}, nil)
+
+		r := gin.New()
+		r.GET("/blogs/:id/events", NewEventHandler(broker, mBlog).StreamBlogEvents)
+		srv := httptest.NewServer(r)
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/blogs/"+blogID.Hex()+"/events", nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+		lines := bufio.NewScanner(resp.Body)
+		next := func() string {
+			// Each event is an event line, a data line and a blank line
+			var ev []string
+			for lines.Scan() && lines.Text() != "" {
+				ev = append(ev, lines.Text())
+			}
+			return strings.Join(ev, "\n")
+		}
+
+		assert.Equal(t, "event:readers\ndata:{\"likes\":0,\"readers\":7}", next())
+
+		_ = broker.Publish(context.Background(), events.Event{BlogID: blogID, Type: events.TypeLikes, Data: gin.H{"likes": 3}})
+		assert.Equal(t, "event:likes\ndata:{\"likes\":3}", next())
+	})
+
+	t.Run("DENY: Hidden blog", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, Hidden: true}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/blogs/:id/events", NewEventHandler(events.NewMemoryBroker(), mBlog).StreamBlogEvents)
+		req, _ := http.NewRequest("GET", "/blogs/"+blogID.Hex()+"/events", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
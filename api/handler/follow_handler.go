@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/repository"
+)
+
+// maxFeedLimit caps how many posts one feed page returns
+const maxFeedLimit = 50
+
+// FollowHandler handles following authors and the feed of their posts
+type FollowHandler struct {
+	repo       repository.IFollowRepository
+	authorRepo repository.IAuthorRepository
+	blogRepo   repository.IBlogRepository
+}
+
+func NewFollowHandler(repo repository.IFollowRepository, authorRepo repository.IAuthorRepository, blogRepo repository.IBlogRepository) *FollowHandler {
+	return &FollowHandler{repo: repo, authorRepo: authorRepo, blogRepo: blogRepo}
+}
+
+// FollowAuthor godoc
+// @Summary Follow an author
+// @Description The logged-in author follows another author; their posts then appear in the follower's feed. Following twice has no further effect.
+// @Tags Authors
+// @Produce json
+// @Param id path string true "Author ID to follow"
+// @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /authors/{id}/follow [post]
+func (h *FollowHandler) FollowAuthor(c *gin.Context) {
+	followerID, followeeID, ok := h.followParams(c)
+	if !ok {
+		return
+	}
+	if followerID == followeeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "you cannot follow yourself"})
+		return
+	}
+
+	followee, err := h.authorRepo.GetAuthorByID(followeeID)
+	if err != nil || followee == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "author not found"})
+		return
+	}
+
+	created, err := h.repo.Follow(context.Background(), followerID, followeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to follow author"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"following": true})
+}
+
+// UnfollowAuthor godoc
+// @Summary Unfollow an author
+// @Description The logged-in author stops following another author. Unfollowing someone not followed has no effect.
+// @Tags Authors
+// @Produce json
+// @Param id path string true "Author ID to unfollow"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /authors/{id}/follow [delete]
+func (h *FollowHandler) UnfollowAuthor(c *gin.Context) {
+	followerID, followeeID, ok := h.followParams(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.Unfollow(context.Background(), followerID, followeeID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unfollow author"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"following": false})
+}
+
+// followParams reads the logged-in author and the author in the path
+func (h *FollowHandler) followParams(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	followerID, ok := currentAuthorID(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	followeeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return followerID, followeeID, true
+}
+
+// GetFeed godoc
+// @Summary Get my feed
+// @Description Returns recent posts by the authors the logged-in author follows, newest first. Pass next_cursor from one page as cursor to get the next; it is empty on the last page.
+// @Tags Blogs
+// @Produce json
+// @Param limit query int false "Posts per page (max 50)" default(20)
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	readerID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	var after *blog.Cursor
+	if token := c.Query("cursor"); token != "" {
+		var err error
+		if after, err = blog.ParseCursor(token); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	following, err := h.repo.Following(context.Background(), readerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feed"})
+		return
+	}
+
+	// Ask for one extra post to learn whether there is another page
+	posts, err := h.blogRepo.ListFeed(context.Background(), following, after, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feed"})
+		return
+	}
+
+	next := ""
+	if int64(len(posts)) > limit {
+		posts = posts[:limit]
+		next = blog.CursorAfter(posts[len(posts)-1]).String()
+	}
+	if posts == nil {
+		posts = []*blog.Blog{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blogs": posts, "next_cursor": next})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+)
+
+func TestFollowAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(h *FollowHandler, followerID, followeeID primitive.ObjectID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", followerID.Hex()) })
+		r.POST("/authors/:id/follow", h.FollowAuthor)
+
+		req, _ := http.NewRequest("POST", "/authors/"+followeeID.Hex()+"/follow", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ALLOW: First follow", func(t *testing.T) {
+		mFollow, mAuth := new(MockFollowRepo), new(MockAuthorRepo)
+		followerID, followeeID := primitive.NewObjectID(), primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", followeeID).Return(&author.Author{ID: followeeID}, nil)
+		mFollow.On("Follow", mock.Anything, followerID, followeeID).Return(true, nil)
+
+		w := send(NewFollowHandler(mFollow, mAuth, nil), followerID, followeeID)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("DENY: Following yourself", func(t *testing.T) {
+		mFollow := new(MockFollowRepo)
+		id := primitive.NewObjectID()
+
+		w := send(NewFollowHandler(mFollow, new(MockAuthorRepo), nil), id, id)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mFollow.AssertNotCalled(t, "Follow", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	readerID, followeeID := primitive.NewObjectID(), primitive.NewObjectID()
+	now := time.Now().Truncate(time.Millisecond)
+	posts := []*blog.Blog{
+		{ID: primitive.NewObjectID(), AuthorID: followeeID, CreatedAt: now},
+		{ID: primitive.NewObjectID(), AuthorID: followeeID, CreatedAt: now.Add(-time.Minute)},
+		{ID: primitive.NewObjectID(), AuthorID: followeeID, CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	get := func(h *FollowHandler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", readerID.Hex()) })
+		r.GET("/feed", h.GetFeed)
+
+		req, _ := http.NewRequest("GET", "/feed"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("PAGE: Cursor continues after the last post", func(t *testing.T) {
+		mFollow, mBlog := new(MockFollowRepo), new(MockBlogRepo)
+		mFollow.On("Following", mock.Anything, readerID).Return([]primitive.ObjectID{followeeID}, nil)
+		mBlog.On("ListFeed", mock.Anything, []primitive.ObjectID{followeeID}, (*blog.Cursor)(nil), int64(3)).Return(posts, nil)
+
+		w := get(NewFollowHandler(mFollow, nil, mBlog), "?limit=2")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Blogs      []*blog.Blog `json:"blogs"`
+			NextCursor string       `json:"next_cursor"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		assert.Len(t, body.Blogs, 2)
+
+		next, err := blog.ParseCursor(body.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, posts[1].ID, next.ID)
+		assert.True(t, posts[1].CreatedAt.Equal(next.CreatedAt))
+	})
+
+	t.Run("REJECT: Tampered cursor", func(t *testing.T) {
+		w := get(NewFollowHandler(new(MockFollowRepo), nil, new(MockBlogRepo)), "?cursor=not-a-cursor")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
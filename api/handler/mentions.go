@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/service"
+)
+
+// resolveMentions links the @mentions in a text. Without a mention service
+// the text is left as it is.
+func resolveMentions(mentions service.IMentionService, text string) (string, []primitive.ObjectID) {
+	if mentions == nil {
+		return text, nil
+	}
+	return mentions.Resolve(text)
+}
+
+// resolvePostMentions links the @mentions in a post's content and sections
+func resolvePostMentions(mentions service.IMentionService, content string, sections map[string]string) (string, map[string]string, []primitive.ObjectID) {
+	content, ids := resolveMentions(mentions, content)
+	if len(sections) == 0 {
+		return content, sections, ids
+	}
+
+	resolved := make(map[string]string, len(sections))
+	for key, text := range sections {
+		var more []primitive.ObjectID
+		resolved[key], more = resolveMentions(mentions, text)
+		ids = appendNew(ids, more)
+	}
+	return content, resolved, ids
+}
+
+// appendNew appends the ids not already in list
+func appendNew(list, ids []primitive.ObjectID) []primitive.ObjectID {
+	for _, id := range ids {
+		if !containsID(list, id) {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+func containsID(list []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/audit"
+	"razorblog-backend/internal/models/blog"
+	models "razorblog-backend/internal/models/comment"
+	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+	"razorblog-backend/internal/spam"
+)
+
+// ModerationHandler handles moderator tools for community content
+type ModerationHandler struct {
+	commentRepo *repository.CommentRepository
+	authorRepo  repository.IAuthorRepository
+	auditRepo   repository.IAuditRepository
+	scorer      *spam.Scorer
+	analytics   repository.IAnalyticsRepository
+	blogRepo    repository.IBlogRepository
+	mentions    service.IMentionService
+	notifier    service.INotificationService
+	broker      events.Broker
+}
+
+// ModerationDeps holds the optional collaborators of a ModerationHandler.
+// Any left nil are skipped.
+type ModerationDeps struct {
+	Scorer    *spam.Scorer
+	Analytics repository.IAnalyticsRepository
+	BlogRepo  repository.IBlogRepository
+	Mentions  service.IMentionService
+	Notifier  service.INotificationService
+	Broker    events.Broker
+}
+
+func NewModerationHandler(commentRepo *repository.CommentRepository, authorRepo repository.IAuthorRepository, auditRepo repository.IAuditRepository, deps ...ModerationDeps) *ModerationHandler {
+	var d ModerationDeps
+	if len(deps) > 0 {
+		d = deps[0]
+	}
+	return &ModerationHandler{
+		commentRepo: commentRepo,
+		authorRepo:  authorRepo,
+		auditRepo:   auditRepo,
+		scorer:      d.Scorer,
+		analytics:   d.Analytics,
+		blogRepo:    d.BlogRepo,
+		mentions:    d.Mentions,
+		notifier:    d.Notifier,
+		broker:      d.Broker,
+	}
+}
+
+// moderationRequest is the body of the bulk moderation endpoints
+type moderationRequest struct {
+	IDs    []string `json:"ids" binding:"required"`
+	Reason string   `json:"reason"`
+}
+
+// ListModerationQueue godoc
+// @Summary List the comment moderation queue
+// @Description Returns comments in a moderation state, oldest first (moderators only)
+// @Tags Moderation
+// @Produce json
+// @Param status query string false "Moderation status (pending, approved, rejected, spam)" default(pending)
+// @Param blog_id query string false "Only comments on this blog"
+// @Param limit query int false "Limit number of comments" default(20)
+// @Param skip query int false "Number of comments to skip" default(0)
+// @Success 200 {array} models.ModerationView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments [get]
+func (h *ModerationHandler) ListModerationQueue(c *gin.Context) {
+	if _, ok := requireModerator(c, h.authorRepo); !ok {
+		return
+	}
+
+	status := models.Status(c.DefaultQuery("status", string(models.StatusPending)))
+	if !status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	var blogID *primitive.ObjectID
+	if idStr := c.Query("blog_id"); idStr != "" {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+			return
+		}
+		blogID = &id
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	comments, err := h.commentRepo.ListByStatus(context.Background(), status, blogID, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+	views := make([]models.ModerationView, 0, len(comments))
+	for _, cmt := range comments {
+		views = append(views, cmt.ForModeration())
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+// ApproveComments godoc
+// @Summary Approve comments
+// @Description Publishes the given comments (moderators only). The reason is recorded in the audit log.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body object{ids=[]string,reason=string} true "Comment IDs and an optional reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments/approve [post]
+func (h *ModerationHandler) ApproveComments(c *gin.Context) {
+	h.moderate(c, models.StatusApproved, audit.ActionCommentApproved)
+}
+
+// RejectComments godoc
+// @Summary Reject comments
+// @Description Hides the given comments from readers (moderators only). The reason is recorded in the audit log.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body object{ids=[]string,reason=string} true "Comment IDs and an optional reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments/reject [post]
+func (h *ModerationHandler) RejectComments(c *gin.Context) {
+	h.moderate(c, models.StatusRejected, audit.ActionCommentRejected)
+}
+
+// DeleteComments godoc
+// @Summary Delete comments
+// @Description Moves the given comments to the trash (moderators only). The reason is recorded in the audit log.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body object{ids=[]string,reason=string} true "Comment IDs and an optional reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments/delete [post]
+func (h *ModerationHandler) DeleteComments(c *gin.Context) {
+	h.moderate(c, "", audit.ActionCommentDeleted)
+}
+
+// MarkSpam godoc
+// @Summary Mark comments as spam
+// @Description Hides the given comments and trains the spam classifier on them (moderators only)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body object{ids=[]string,reason=string} true "Comment IDs and an optional reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments/spam [post]
+func (h *ModerationHandler) MarkSpam(c *gin.Context) {
+	h.moderate(c, models.StatusSpam, audit.ActionCommentSpam)
+}
+
+// MarkHam godoc
+// @Summary Mark comments as not spam
+// @Description Publishes the given comments and trains the spam classifier on them as legitimate (moderators only)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body object{ids=[]string,reason=string} true "Comment IDs and an optional reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/comments/ham [post]
+func (h *ModerationHandler) MarkHam(c *gin.Context) {
+	h.moderate(c, models.StatusApproved, audit.ActionCommentHam)
+}
+
+// moderate applies a bulk moderation action. An empty status means delete.
+func (h *ModerationHandler) moderate(c *gin.Context, status models.Status, action audit.Action) {
+	moderatorID, ok := requireModerator(c, h.authorRepo)
+	if !ok {
+		return
+	}
+
+	var req moderationRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.IDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids are required"})
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(req.IDs))
+	for _, idStr := range req.IDs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID: " + idStr})
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	comments, err := h.commentRepo.GetByIDs(context.Background(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var changed int64
+	if status == "" {
+		changed, err = h.commentRepo.DeleteMany(context.Background(), ids)
+	} else {
+		changed, err = h.commentRepo.SetStatus(context.Background(), ids, status)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	blogs := map[primitive.ObjectID]*blog.Blog{}
+	for _, cmt := range comments {
+		h.syncReplyCount(cmt, status)
+		// A held comment is announced when it is published
+		if !cmt.IsVisible() && visibleAs(cmt, status) {
+			published := *cmt
+			published.Status = status
+			announceComment(h.mentions, h.notifier, h.broker, h.analytics, h.blogOf(blogs, cmt.BlogID), &published)
+		}
+		h.learn(cmt, action)
+		h.record(c, moderatorID, action, cmt.ID, req.Reason)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matched": len(comments), "modified": changed})
+}
+
+// blogOf returns the post a comment is on, fetching each post once. It is
+// nil when the post cannot be loaded.
+func (h *ModerationHandler) blogOf(cache map[primitive.ObjectID]*blog.Blog, blogID primitive.ObjectID) *blog.Blog {
+	if b, ok := cache[blogID]; ok {
+		return b
+	}
+	var b *blog.Blog
+	if h.blogRepo != nil {
+		if found, err := h.blogRepo.GetByID(context.Background(), blogID); err == nil {
+			b = found
+		}
+	}
+	cache[blogID] = b
+	return b
+}
+
+// syncReplyCount keeps the parent's reply count in line with what readers
+// can see when a reply is published or taken down
+func (h *ModerationHandler) syncReplyCount(cmt *models.Comment, next models.Status) {
+	if cmt.ParentID == nil {
+		return
+	}
+
+	wasVisible := cmt.IsVisible()
+	isVisible := visibleAs(cmt, next)
+	switch {
+	case !wasVisible && isVisible:
+		_ = h.commentRepo.IncrementReplies(context.Background(), *cmt.ParentID, 1)
+	case wasVisible && !isVisible:
+		_ = h.commentRepo.IncrementReplies(context.Background(), *cmt.ParentID, -1)
+	}
+}
+
+// visibleAs reports whether readers would see a comment once it is in the
+// next status. Comments that reports hid stay out of sight whatever their
+// status, and an empty status means the comment is being deleted.
+func visibleAs(cmt *models.Comment, next models.Status) bool {
+	if next == "" {
+		return false
+	}
+	after := *cmt
+	after.Status = next
+	return after.IsVisible()
+}
+
+// learn trains the spam classifier on comments a moderator labelled as
+// spam or ham. A comment relabelled the other way is unlearned first.
+func (h *ModerationHandler) learn(cmt *models.Comment, action audit.Action) {
+	var label models.Status
+	switch action {
+	case audit.ActionCommentSpam:
+		label = models.StatusSpam
+	case audit.ActionCommentHam:
+		label = models.StatusApproved
+	default:
+		return
+	}
+	if h.scorer == nil || cmt.TrainedAs == label {
+		return
+	}
+
+	ctx := context.Background()
+	if cmt.TrainedAs != "" {
+		if err := h.scorer.Train(ctx, cmt.Content, cmt.Username, cmt.TrainedAs == models.StatusSpam, true); err != nil {
+			log.Printf("⚠️ Failed to unlearn comment %s: %v", cmt.ID.Hex(), err)
+			return
+		}
+	}
+	if err := h.scorer.Train(ctx, cmt.Content, cmt.Username, label == models.StatusSpam, false); err != nil {
+		log.Printf("⚠️ Failed to train spam classifier on comment %s: %v", cmt.ID.Hex(), err)
+		return
+	}
+	if err := h.commentRepo.SetTrainedAs(ctx, cmt.ID, label); err != nil {
+		log.Printf("⚠️ Failed to record training label of comment %s: %v", cmt.ID.Hex(), err)
+	}
+}
+
+// record writes a moderation decision to the audit log
+func (h *ModerationHandler) record(c *gin.Context, moderatorID primitive.ObjectID, action audit.Action, commentID primitive.ObjectID, reason string) {
+	err := h.auditRepo.Record(context.Background(), &audit.Event{
+		ActorID:    moderatorID,
+		Action:     action,
+		TargetType: "comment",
+		TargetID:   &commentID,
+		Reason:     reason,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Printf("⚠️ Failed to record audit event %s: %v", action, err)
+	}
+}
+
+// requireModerator resolves the logged-in author and rejects anyone who
+// cannot moderate
+func requireModerator(c *gin.Context, authorRepo repository.IAuthorRepository) (primitive.ObjectID, bool) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	a, err := authorRepo.GetAuthorByID(authorID)
+	if err != nil || a == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
+		return primitive.NilObjectID, false
+	}
+
+	if !a.IsModerator() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: moderators only"})
+		return primitive.NilObjectID, false
+	}
+
+	return authorID, true
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	models "razorblog-backend/internal/models/comment"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+)
+
+func TestModeration_RBAC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("REJECT: Guest cannot approve comments", func(t *testing.T) {
+		mAuth := new(MockAuthorRepo)
+		h := NewModerationHandler(nil, mAuth, new(MockAuditRepo))
+
+		guestID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", guestID).Return(&author.Author{ID: guestID, Role: author.RoleGuest}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", guestID.Hex()) })
+		r.POST("/moderation/comments/approve", h.ApproveComments)
+
+		body := `{"ids":["` + primitive.NewObjectID().Hex() + `"]}`
+		req, _ := http.NewRequest("POST", "/moderation/comments/approve", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("REJECT: Moderator sends malformed IDs", func(t *testing.T) {
+		mAuth := new(MockAuthorRepo)
+		h := NewModerationHandler(nil, mAuth, new(MockAuditRepo))
+
+		modID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", modID).Return(&author.Author{ID: modID, Role: author.RoleModerator}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", modID.Hex()) })
+		r.POST("/moderation/comments/reject", h.RejectComments)
+
+		req, _ := http.NewRequest("POST", "/moderation/comments/reject", bytes.NewBufferString(`{"ids":["nope"]}`))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestListModerationQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Moderators see the spam signals", func(mt *mtest.T) {
+		mAuth := new(MockAuthorRepo)
+		modID := primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", modID).Return(&author.Author{ID: modID, Role: author.RoleModerator}, nil)
+		h := NewModerationHandler(repository.NewCommentRepository(mt.DB), mAuth, new(MockAuditRepo))
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(&models.Comment{
+			ID:          primitive.NewObjectID(),
+			Content:     "Buy now",
+			Status:      models.StatusPending,
+			SpamScore:   0.9,
+			SpamReasons: []string{"blocklist"},
+		})))
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", modID.Hex()) })
+		r.GET("/moderation/comments", h.ListModerationQueue)
+
+		req, _ := http.NewRequest("GET", "/moderation/comments", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(mt, http.StatusOK, w.Code)
+		assert.Contains(mt, w.Body.String(), `"spam_score":0.9`)
+		assert.Contains(mt, w.Body.String(), `"spam_reasons":["blocklist"]`)
+	})
+}
+
+func TestModeration_ReplyCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	modID := primitive.NewObjectID()
+	parentID := primitive.NewObjectID()
+
+	// approve approves a reply and returns the reply_count delta sent to its parent
+	approve := func(mt *mtest.T, reply *models.Comment) int32 {
+		mAuth := new(MockAuthorRepo)
+		mAuth.On("GetAuthorByID", modID).Return(&author.Author{ID: modID, Role: author.RoleModerator}, nil)
+		h := NewModerationHandler(repository.NewCommentRepository(mt.DB), mAuth, new(MockAuditRepo))
+
+		ok := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(reply)), ok, ok)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", modID.Hex()) })
+		r.POST("/moderation/comments/approve", h.ApproveComments)
+		req, _ := http.NewRequest("POST", "/moderation/comments/approve", bytes.NewBufferString(`{"ids":["`+reply.ID.Hex()+`"]}`))
+		r.ServeHTTP(w, req)
+		assert.Equal(mt, http.StatusOK, w.Code)
+
+		var delta int32
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			if e.CommandName != "update" {
+				continue
+			}
+			u := e.Command.Lookup("updates").Array().Index(0).Value().Document()
+			if id, ok := u.Lookup("q", "_id").ObjectIDOK(); ok && id == parentID {
+				delta += u.Lookup("u", "$inc", "reply_count").Int32()
+			}
+		}
+		return delta
+	}
+
+	mt.Run("Approving a held reply counts it", func(mt *mtest.T) {
+		reply := &models.Comment{ID: primitive.NewObjectID(), ParentID: &parentID, Status: models.StatusPending}
+		assert.Equal(mt, int32(1), approve(mt, reply))
+	})
+
+	mt.Run("Approving a reply that reports hid does not count it", func(mt *mtest.T) {
+		reply := &models.Comment{ID: primitive.NewObjectID(), ParentID: &parentID, Status: models.StatusPending, Hidden: true}
+		assert.Equal(mt, int32(0), approve(mt, reply))
+	})
+}
+
+func TestModeration_AnnouncesPublishedComments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Approving a held comment notifies the post's author and live readers", func(mt *mtest.T) {
+		modID := primitive.NewObjectID()
+		ownerID := primitive.NewObjectID()
+		blogID := primitive.NewObjectID()
+		held := &models.Comment{ID: primitive.NewObjectID(), BlogID: blogID, Username: "guest", Status: models.StatusPending}
+
+		mAuth := new(MockAuthorRepo)
+		mAuth.On("GetAuthorByID", modID).Return(&author.Author{ID: modID, Role: author.RoleModerator}, nil)
+		mBlog := new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID, AuthorID: ownerID}, nil)
+		mNotes := new(MockNotificationRepo)
+		mNotes.On("MutedBy", mock.Anything, notification.TypeComment, []primitive.ObjectID{ownerID}).Return([]primitive.ObjectID{}, nil)
+		mNotes.On("CreateMany", mock.Anything, mock.MatchedBy(func(notes []*notification.Notification) bool {
+			return len(notes) == 1 && notes[0].RecipientID == ownerID && *notes[0].CommentID == held.ID
+		})).Return(nil)
+		broker := events.NewMemoryBroker()
+		live, cancel := broker.Subscribe(blogID)
+		defer cancel()
+
+		h := NewModerationHandler(repository.NewCommentRepository(mt.DB), mAuth, new(MockAuditRepo), ModerationDeps{
+			BlogRepo: mBlog,
+			Notifier: service.NewNotificationService(mNotes),
+			Broker:   broker,
+		})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, commentDoc(held)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", modID.Hex()) })
+		r.POST("/moderation/comments/approve", h.ApproveComments)
+		req, _ := http.NewRequest("POST", "/moderation/comments/approve", bytes.NewBufferString(`{"ids":["`+held.ID.Hex()+`"]}`))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(mt, http.StatusOK, w.Code)
+		mNotes.AssertExpectations(mt)
+		select {
+		case e := <-live:
+			assert.Equal(mt, events.TypeComment, e.Type)
+		default:
+			mt.Error("no live comment event was published")
+		}
+	})
+}
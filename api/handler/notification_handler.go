@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"razorblog-backend/internal/models/digest"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/repository"
+)
+
+// NotificationHandler serves an author's notification center and email
+// digest settings
+type NotificationHandler struct {
+	repo       repository.INotificationRepository
+	digestRepo repository.IDigestRepository
+}
+
+func NewNotificationHandler(repo repository.INotificationRepository, digestRepo repository.IDigestRepository) *NotificationHandler {
+	return &NotificationHandler{repo: repo, digestRepo: digestRepo}
+}
+
+// ListNotifications godoc
+// @Summary List my notifications
+// @Description Returns the logged-in author's notifications, unread first and newest first within each group, with the number still unread
+// @Tags Notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Limit number of notifications" default(20)
+// @Param skip query int false "Number of notifications to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+	unreadOnly := c.Query("unread") == "true"
+
+	notes, err := h.repo.List(context.Background(), authorID, unreadOnly, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch notifications"})
+		return
+	}
+	if notes == nil {
+		notes = []*notification.Notification{}
+	}
+
+	unread, err := h.repo.CountUnread(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": unread, "notifications": notes})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification read
+// @Description Marks one of the logged-in author's notifications as read
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	if err := h.repo.MarkRead(context.Background(), id, authorID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked read"})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications read
+// @Description Marks every unread notification of the logged-in author as read
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/read [patch]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.repo.MarkAllRead(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notifications marked read", "updated": n})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get my notification preferences
+// @Description Returns whether each notification type (comment, like, share, mention) is turned on for the logged-in author
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.repo.GetPreferences(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs.Settings())
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update my notification preferences
+// @Description Turns notification types on or off. Types left out of the body keep their current setting.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body map[string]bool true "Type (comment, like, share, mention) to whether it is on"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	var body map[notification.Type]bool
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for t := range body {
+		if !t.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown notification type: " + string(t)})
+			return
+		}
+	}
+
+	prefs, err := h.repo.GetPreferences(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch preferences"})
+		return
+	}
+
+	settings := prefs.Settings()
+	for t, on := range body {
+		settings[t] = on
+	}
+	prefs.Muted = []notification.Type{}
+	for _, t := range notification.Types {
+		if !settings[t] {
+			prefs.Muted = append(prefs.Muted, t)
+		}
+	}
+
+	if err := h.repo.SetPreferences(context.Background(), prefs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs.Settings())
+}
+
+// GetDigestSettings godoc
+// @Summary Get my email digest settings
+// @Description Returns how often the logged-in author receives an activity digest by email (daily, weekly or off) and when the last one was sent
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} digest.Subscription
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/digest [get]
+func (h *NotificationHandler) GetDigestSettings(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.digestRepo.Get(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch digest settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// UpdateDigestSettings godoc
+// @Summary Update my email digest settings
+// @Description Sets how often the logged-in author receives an activity digest by email; "off" opts out
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body object{frequency=string} true "daily, weekly or off"
+// @Success 200 {object} digest.Subscription
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /notifications/digest [put]
+func (h *NotificationHandler) UpdateDigestSettings(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Frequency digest.Frequency `json:"frequency"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !body.Frequency.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "frequency must be daily, weekly or off"})
+		return
+	}
+
+	if err := h.digestRepo.SetFrequency(context.Background(), authorID, body.Frequency); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update digest settings"})
+		return
+	}
+
+	sub, err := h.digestRepo.Get(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch digest settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
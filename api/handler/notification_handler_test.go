@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/service"
+)
+
+func TestNotificationCenter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(h *NotificationHandler, authorID primitive.ObjectID, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", authorID.Hex()) })
+		r.GET("/notifications", h.ListNotifications)
+		r.PATCH("/notifications/:id/read", h.MarkNotificationRead)
+		r.PUT("/notifications/preferences", h.UpdateNotificationPreferences)
+
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("LIST: Includes the unread count", func(t *testing.T) {
+		mRepo := new(MockNotificationRepo)
+		authorID := primitive.NewObjectID()
+		mRepo.On("List", mock.Anything, authorID, true, int64(20), int64(0)).Return([]*notification.Notification{}, nil)
+		mRepo.On("CountUnread", mock.Anything, authorID).Return(int64(4), nil)
+
+		w := send(NewNotificationHandler(mRepo, nil), authorID, "GET", "/notifications?unread=true", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"unread":4,"notifications":[]}`, w.Body.String())
+	})
+
+	t.Run("DENY: Marking someone else's notification", func(t *testing.T) {
+		mRepo := new(MockNotificationRepo)
+		authorID, noteID := primitive.NewObjectID(), primitive.NewObjectID()
+		mRepo.On("MarkRead", mock.Anything, noteID, authorID).Return(mongo.ErrNoDocuments)
+
+		w := send(NewNotificationHandler(mRepo, nil), authorID, "PATCH", "/notifications/"+noteID.Hex()+"/read", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PREFERENCES: Unchanged types keep their setting", func(t *testing.T) {
+		mRepo := new(MockNotificationRepo)
+		authorID := primitive.NewObjectID()
+		mRepo.On("GetPreferences", mock.Anything, authorID).Return(&notification.Preferences{
+			AuthorID: authorID,
+			Muted:    []notification.Type{notification.TypeShare},
+		}, nil)
+		mRepo.On("SetPreferences", mock.Anything, mock.Anything).Return(nil)
+
+		w := send(NewNotificationHandler(mRepo, nil), authorID, "PUT", "/notifications/preferences", `{"like":false}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"comment":true,"like":false,"share":false,"mention":true}`, w.Body.String())
+		mRepo.AssertCalled(t, "SetPreferences", mock.Anything, &notification.Preferences{
+			AuthorID: authorID,
+			Muted:    []notification.Type{notification.TypeLike, notification.TypeShare},
+		})
+	})
+
+	t.Run("REJECT: Unknown notification type", func(t *testing.T) {
+		w := send(NewNotificationHandler(new(MockNotificationRepo), nil), primitive.NewObjectID(), "PUT", "/notifications/preferences", `{"follow":false}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotifyPostAuthors(t *testing.T) {
+	mRepo := new(MockNotificationRepo)
+	notifier := service.NewNotificationService(mRepo)
+
+	owner, coAuthor, invited, liker := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	b := &blog.Blog{ID: primitive.NewObjectID(), AuthorID: owner, CoAuthors: []blog.CoAuthor{
+		{AuthorID: coAuthor, Status: blog.InviteAccepted},
+		{AuthorID: invited, Status: blog.InvitePending},
+	}}
+
+	// The co-author muted likes; pending invitees are not credited yet
+	mRepo.On("MutedBy", mock.Anything, notification.TypeLike, []primitive.ObjectID{owner, coAuthor}).Return([]primitive.ObjectID{coAuthor}, nil)
+	mRepo.On("CreateMany", mock.Anything, mock.Anything).Return(nil)
+
+	notifyPostAuthors(notifier, b, nil, notification.Notification{Type: notification.TypeLike, ActorID: &liker, BlogID: &b.ID})
+
+	mRepo.AssertCalled(t, "CreateMany", mock.Anything, mock.MatchedBy(func(notes []*notification.Notification) bool {
+		return len(notes) == 1 && notes[0].RecipientID == owner
+	}))
+}
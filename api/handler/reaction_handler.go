@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/reaction"
+	"razorblog-backend/internal/repository"
+)
+
+// ReactionHandler handles emoji reactions on blogs and comments
+type ReactionHandler struct {
+	repo        repository.IReactionRepository
+	blogRepo    repository.IBlogRepository
+	commentRepo *repository.CommentRepository
+	authorRepo  repository.IAuthorRepository
+}
+
+func NewReactionHandler(repo repository.IReactionRepository, blogRepo repository.IBlogRepository, commentRepo *repository.CommentRepository, authorRepo repository.IAuthorRepository) *ReactionHandler {
+	return &ReactionHandler{
+		repo:        repo,
+		blogRepo:    blogRepo,
+		commentRepo: commentRepo,
+		authorRepo:  authorRepo,
+	}
+}
+
+// reactionTargets maps the :target URL segment to the kind of content
+var reactionTargets = map[string]reaction.TargetType{
+	"blogs":    reaction.TargetBlog,
+	"comments": reaction.TargetComment,
+}
+
+// AddReaction godoc
+// @Summary React to a blog or comment
+// @Description Adds an emoji reaction from the logged-in author. Reacting twice with the same emoji has no further effect.
+// @Tags Reactions
+// @Produce json
+// @Param target path string true "blogs or comments"
+// @Param id path string true "Blog or comment ID"
+// @Param kind path string true "Reaction (like, love, laugh, celebrate, insightful, sad)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reactions/{target}/{id}/{kind} [put]
+func (h *ReactionHandler) AddReaction(c *gin.Context) {
+	h.react(c, true)
+}
+
+// RemoveReaction godoc
+// @Summary Remove a reaction
+// @Description Removes an emoji reaction left by the logged-in author. Removing a reaction that does not exist has no effect.
+// @Tags Reactions
+// @Produce json
+// @Param target path string true "blogs or comments"
+// @Param id path string true "Blog or comment ID"
+// @Param kind path string true "Reaction (like, love, laugh, celebrate, insightful, sad)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reactions/{target}/{id}/{kind} [delete]
+func (h *ReactionHandler) RemoveReaction(c *gin.Context) {
+	h.react(c, false)
+}
+
+// ListReactions godoc
+// @Summary List reactions
+// @Description Returns the count of each reaction on a blog or comment and who reacted, newest first
+// @Tags Reactions
+// @Produce json
+// @Param target path string true "blogs or comments"
+// @Param id path string true "Blog or comment ID"
+// @Param kind query string false "Only list readers who left this reaction"
+// @Param limit query int false "Limit number of readers" default(20)
+// @Param skip query int false "Number of readers to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reactions/{target}/{id} [get]
+func (h *ReactionHandler) ListReactions(c *gin.Context) {
+	targetType, targetID, ok := h.target(c)
+	if !ok {
+		return
+	}
+
+	kind := reaction.Kind(c.Query("kind"))
+	if kind != "" && !kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reaction"})
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	reactors, err := h.repo.ListReactors(context.Background(), targetType, targetID, kind, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reactions"})
+		return
+	}
+	if reactors == nil {
+		reactors = []*reaction.Reaction{}
+	}
+
+	counts, err := h.repo.Counts(context.Background(), targetType, targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"counts":    counts,
+		"reactors":  reactors,
+		"available": reaction.Emoji,
+	})
+}
+
+// react adds or removes the caller's reaction and returns the new counts
+func (h *ReactionHandler) react(c *gin.Context, add bool) {
+	targetType, targetID, ok := h.target(c)
+	if !ok {
+		return
+	}
+
+	kind := reaction.Kind(c.Param("kind"))
+	if !kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reaction"})
+		return
+	}
+
+	// Reactions are tied to an account, so nobody can take back someone
+	// else's reaction or react many times under made-up names
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+	a, err := h.authorRepo.GetAuthorByID(authorID)
+	if err != nil || a == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "author not found"})
+		return
+	}
+	reactor := "author:" + authorID.Hex()
+
+	if add {
+		_, err = h.repo.Add(context.Background(), &reaction.Reaction{
+			TargetType:  targetType,
+			TargetID:    targetID,
+			Kind:        kind,
+			Reactor:     reactor,
+			ReactorName: a.Name,
+			AuthorID:    &authorID,
+			CreatedAt:   time.Now(),
+		})
+	} else {
+		_, err = h.repo.Remove(context.Background(), targetType, targetID, kind, reactor)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update reaction"})
+		return
+	}
+
+	counts, err := h.repo.Counts(context.Background(), targetType, targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"kind":    kind,
+		"reacted": add,
+		"counts":  counts,
+	})
+}
+
+// target resolves the blog or comment in the URL, which must be visible
+func (h *ReactionHandler) target(c *gin.Context) (reaction.TargetType, primitive.ObjectID, bool) {
+	targetType, ok := reactionTargets[c.Param("target")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reactions are only available on blogs and comments"})
+		return "", primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + string(targetType) + " ID"})
+		return "", primitive.NilObjectID, false
+	}
+
+	switch targetType {
+	case reaction.TargetBlog:
+		if _, err := h.blogRepo.GetByID(context.Background(), id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+			return "", primitive.NilObjectID, false
+		}
+	case reaction.TargetComment:
+		cmt, err := h.commentRepo.GetByID(context.Background(), id)
+		if err != nil || !cmt.IsVisible() {
+			c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+			return "", primitive.NilObjectID, false
+		}
+	}
+
+	return targetType, id, true
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/reaction"
+)
+
+func TestReactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("REJECT: Unknown reaction", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mReact := new(MockReactionRepo)
+		h := NewReactionHandler(mReact, mBlog, nil, new(MockAuthorRepo))
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.PUT("/reactions/:target/:id/:kind", h.AddReaction)
+
+		req, _ := http.NewRequest("PUT", "/reactions/blogs/"+blogID.Hex()+"/shrug", bytes.NewBufferString(`{"username":"ana"}`))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mReact.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Anonymous readers cannot react or take back reactions", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mReact := new(MockReactionRepo)
+		h := NewReactionHandler(mReact, mBlog, nil, new(MockAuthorRepo))
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+
+		for _, method := range []string{"PUT", "DELETE"} {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.PUT("/reactions/:target/:id/:kind", h.AddReaction)
+			r.DELETE("/reactions/:target/:id/:kind", h.RemoveReaction)
+
+			req, _ := http.NewRequest(method, "/reactions/blogs/"+blogID.Hex()+"/love", bytes.NewBufferString(`{"username":"Ana"}`))
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+		mReact.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+		mReact.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SUCCESS: Reactions are keyed by the author's account", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mAuth := new(MockAuthorRepo)
+		mReact := new(MockReactionRepo)
+		h := NewReactionHandler(mReact, mBlog, nil, mAuth)
+
+		blogID := primitive.NewObjectID()
+		authorID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+		mAuth.On("GetAuthorByID", authorID).Return(&author.Author{ID: authorID, Name: "Victor"}, nil)
+
+		var added *reaction.Reaction
+		mReact.On("Add", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			added = args.Get(1).(*reaction.Reaction)
+		}).Return(true, nil)
+		mReact.On("Counts", mock.Anything, reaction.TargetBlog, blogID).Return(map[reaction.Kind]int64{reaction.KindLove: 1}, nil)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", authorID.Hex()) })
+		r.PUT("/reactions/:target/:id/:kind", h.AddReaction)
+
+		req, _ := http.NewRequest("PUT", "/reactions/blogs/"+blogID.Hex()+"/love", bytes.NewBufferString(`{"username":"Ana"}`))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "author:"+authorID.Hex(), added.Reactor)
+		assert.Equal(t, "Victor", added.ReactorName)
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/related"
+	"razorblog-backend/internal/repository"
+)
+
+// RelatedHandler serves related-post recommendations
+type RelatedHandler struct {
+	repo     repository.IRelatedRepository
+	blogRepo repository.IBlogRepository
+}
+
+func NewRelatedHandler(repo repository.IRelatedRepository, blogRepo repository.IBlogRepository) *RelatedHandler {
+	return &RelatedHandler{repo: repo, blogRepo: blogRepo}
+}
+
+// GetRelatedPosts godoc
+// @Summary Get related posts
+// @Description Returns posts similar to a post, most similar first, based on shared tags and category, readers who read both, and the similarity of their titles and text. Recommendations are recomputed in the background every few hours, so new posts may have none yet. Deleted and hidden posts are never included.
+// @Tags Blogs
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param limit query int false "Number of related posts (at most 20)" default(5)
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blogs/{id}/related [get]
+func (h *RelatedHandler) GetRelatedPosts(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit < 1 || limit > related.MaxPosts {
+		limit = 5
+	}
+
+	ctx := context.Background()
+	b, err := h.blogRepo.GetByID(ctx, blogID)
+	if err != nil || b.Hidden {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	matches, err := h.repo.Get(ctx, blogID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch related posts"})
+		return
+	}
+
+	// Posts deleted or hidden since the last run are dropped here
+	ids := make([]primitive.ObjectID, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.BlogID)
+	}
+	blogs, err := h.blogRepo.GetListedByIDs(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch related posts"})
+		return
+	}
+	byID := make(map[primitive.ObjectID]gin.H, len(blogs))
+	for _, rb := range blogs {
+		byID[rb.ID] = blogSummary(rb)
+	}
+
+	result := make([]gin.H, 0, limit)
+	for _, m := range matches {
+		if summary, ok := byID[m.BlogID]; ok && len(result) < limit {
+			result = append(result, gin.H{"blog": summary, "score": m.Score})
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/related"
+)
+
+func TestGetRelatedPosts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	post := &blog.Blog{ID: primitive.NewObjectID(), Title: "Source"}
+
+	send := func(h *RelatedHandler, id, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/blogs/:id/related", h.GetRelatedPosts)
+
+		req, _ := http.NewRequest("GET", "/blogs/"+id+"/related"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Keeps the ranking and drops posts that are no longer listed", func(t *testing.T) {
+		mRepo, mBlog := new(MockRelatedRepo), new(MockBlogRepo)
+		first := &blog.Blog{ID: primitive.NewObjectID(), Title: "First"}
+		gone := primitive.NewObjectID()
+		second := &blog.Blog{ID: primitive.NewObjectID(), Title: "Second"}
+		third := &blog.Blog{ID: primitive.NewObjectID(), Title: "Third"}
+		matches := []related.Match{
+			{BlogID: first.ID, Score: 0.9},
+			{BlogID: gone, Score: 0.8},
+			{BlogID: second.ID, Score: 0.5},
+			{BlogID: third.ID, Score: 0.2},
+		}
+
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mRepo.On("Get", mock.Anything, post.ID).Return(matches, nil)
+		mBlog.On("GetListedByIDs", mock.Anything, []primitive.ObjectID{first.ID, gone, second.ID, third.ID}).
+			Return([]*blog.Blog{third, second, first}, nil)
+
+		w := send(NewRelatedHandler(mRepo, mBlog), post.ID.Hex(), "?limit=2")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp []struct {
+			Blog  struct{ Title string } `json:"blog"`
+			Score float64                `json:"score"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp, 2)
+		assert.Equal(t, "First", resp[0].Blog.Title)
+		assert.Equal(t, "Second", resp[1].Blog.Title)
+		assert.Equal(t, 0.5, resp[1].Score)
+	})
+
+	t.Run("Hidden post returns 404", func(t *testing.T) {
+		mRepo, mBlog := new(MockRelatedRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(&blog.Blog{ID: post.ID, Hidden: true}, nil)
+
+		w := send(NewRelatedHandler(mRepo, mBlog), post.ID.Hex(), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mRepo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing post returns 404", func(t *testing.T) {
+		mRepo, mBlog := new(MockRelatedRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(nil, errors.New("not found"))
+
+		w := send(NewRelatedHandler(mRepo, mBlog), post.ID.Hex(), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid ID returns 400", func(t *testing.T) {
+		w := send(NewRelatedHandler(new(MockRelatedRepo), new(MockBlogRepo)), "nope", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/audit"
+	"razorblog-backend/internal/models/report"
+	"razorblog-backend/internal/repository"
+)
+
+// maxReportDetails caps the free text of a report
+const maxReportDetails = 2000
+
+// ReportHandler handles abuse reports and their triage
+type ReportHandler struct {
+	repo          repository.IReportRepository
+	blogRepo      repository.IBlogRepository
+	commentRepo   *repository.CommentRepository
+	authorRepo    repository.IAuthorRepository
+	auditRepo     repository.IAuditRepository
+	hideThreshold int64
+}
+
+func NewReportHandler(repo repository.IReportRepository, blogRepo repository.IBlogRepository, commentRepo *repository.CommentRepository, authorRepo repository.IAuthorRepository, auditRepo repository.IAuditRepository, hideThreshold int) *ReportHandler {
+	return &ReportHandler{
+		repo:          repo,
+		blogRepo:      blogRepo,
+		commentRepo:   commentRepo,
+		authorRepo:    authorRepo,
+		auditRepo:     auditRepo,
+		hideThreshold: int64(hideThreshold),
+	}
+}
+
+// CreateReport godoc
+// @Summary Report abusive content
+// @Description Reports a blog, comment or author. Each reader can have one open report per target; reporting again returns the existing report. Blogs and comments are hidden once enough readers report them.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param body body object{target_type=string,target_id=string,reason=string,details=string} true "Target (blog, comment, author), reason (spam, harassment, hate_speech, plagiarism, misinformation, other) and optional details"
+// @Success 201 {object} report.Report
+// @Success 200 {object} report.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /reports [post]
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	reporterID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		TargetType report.TargetType `json:"target_type" binding:"required"`
+		TargetID   string            `json:"target_id" binding:"required"`
+		Reason     report.Reason     `json:"reason" binding:"required"`
+		Details    string            `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !body.TargetType.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be blog, comment or author"})
+		return
+	}
+	if !body.Reason.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reason"})
+		return
+	}
+	body.Details = strings.TrimSpace(body.Details)
+	if len(body.Details) > maxReportDetails {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "details are too long"})
+		return
+	}
+
+	targetID, err := primitive.ObjectIDFromHex(body.TargetID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target ID"})
+		return
+	}
+	if !h.targetExists(body.TargetType, targetID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": string(body.TargetType) + " not found"})
+		return
+	}
+
+	rep, created, err := h.repo.Create(context.Background(), &report.Report{
+		TargetType: body.TargetType,
+		TargetID:   targetID,
+		Reason:     body.Reason,
+		Details:    body.Details,
+		ReporterID: reporterID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to file report"})
+		return
+	}
+	if !created {
+		c.JSON(http.StatusOK, rep)
+		return
+	}
+
+	if h.hideThreshold > 0 {
+		n, err := h.repo.CountOpen(context.Background(), rep.TargetType, rep.TargetID)
+		if err == nil && n >= h.hideThreshold {
+			if err := h.setHidden(rep.TargetType, rep.TargetID, true); err != nil {
+				log.Printf("⚠️ Failed to hide reported %s %s: %v", rep.TargetType, rep.TargetID.Hex(), err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusCreated, rep)
+}
+
+// ListReports godoc
+// @Summary List abuse reports
+// @Description Returns reports in a triage state, oldest first (moderators only)
+// @Tags Reports
+// @Produce json
+// @Param status query string false "Report status (open, resolved, dismissed)" default(open)
+// @Param target_type query string false "Only reports on blogs, comments or authors"
+// @Param limit query int false "Limit number of reports" default(20)
+// @Param skip query int false "Number of reports to skip" default(0)
+// @Success 200 {array} report.Report
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/reports [get]
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	if _, ok := requireModerator(c, h.authorRepo); !ok {
+		return
+	}
+
+	status := report.Status(c.DefaultQuery("status", string(report.StatusOpen)))
+	if !status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	targetType := report.TargetType(c.Query("target_type"))
+	if targetType != "" && !targetType.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be blog, comment or author"})
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+
+	reports, err := h.repo.List(context.Background(), status, targetType, limit, skip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reports"})
+		return
+	}
+	if reports == nil {
+		reports = []*report.Report{}
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// ResolveReport godoc
+// @Summary Resolve a report
+// @Description Upholds a report: every open report on the same target is resolved and a reported blog or comment stays hidden (moderators only)
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Param body body object{note=string} false "Moderator's note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/reports/{id}/resolve [patch]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	h.close(c, report.StatusResolved, audit.ActionReportResolved)
+}
+
+// DismissReport godoc
+// @Summary Dismiss a report
+// @Description Rejects a report: every open report on the same target is dismissed and a hidden blog or comment is shown again (moderators only)
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Param body body object{note=string} false "Moderator's note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /moderation/reports/{id}/dismiss [patch]
+func (h *ReportHandler) DismissReport(c *gin.Context) {
+	h.close(c, report.StatusDismissed, audit.ActionReportDismissed)
+}
+
+// close settles all open reports on the target of the report in the URL
+func (h *ReportHandler) close(c *gin.Context, status report.Status, action audit.Action) {
+	moderatorID, ok := requireModerator(c, h.authorRepo)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report ID"})
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	_ = c.ShouldBindJSON(&body)
+
+	rep, err := h.repo.GetByID(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+		return
+	}
+	if rep.Status != report.StatusOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report already " + string(rep.Status)})
+		return
+	}
+
+	closed, err := h.repo.CloseTarget(context.Background(), rep.TargetType, rep.TargetID, status, moderatorID, body.Note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status == report.StatusResolved || h.unreported(rep.TargetType, rep.TargetID) {
+		if err := h.setHidden(rep.TargetType, rep.TargetID, status == report.StatusResolved); err != nil {
+			log.Printf("⚠️ Failed to update visibility of reported %s %s: %v", rep.TargetType, rep.TargetID.Hex(), err)
+		}
+	}
+
+	targetID := rep.TargetID
+	err = h.auditRepo.Record(context.Background(), &audit.Event{
+		ActorID:    moderatorID,
+		Action:     action,
+		TargetType: string(rep.TargetType),
+		TargetID:   &targetID,
+		Reason:     body.Note,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Printf("⚠️ Failed to record audit event %s: %v", action, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status, "closed": closed})
+}
+
+// targetExists checks that reported content is there to be reported
+func (h *ReportHandler) targetExists(targetType report.TargetType, id primitive.ObjectID) bool {
+	switch targetType {
+	case report.TargetBlog:
+		_, err := h.blogRepo.GetByID(context.Background(), id)
+		return err == nil
+	case report.TargetComment:
+		_, err := h.commentRepo.GetByID(context.Background(), id)
+		return err == nil
+	case report.TargetAuthor:
+		a, err := h.authorRepo.GetAuthorByID(id)
+		return err == nil && a != nil
+	}
+	return false
+}
+
+// unreported reports whether a dismissed target can be shown again. A report
+// filed after the others were closed keeps it hidden until it is settled too.
+func (h *ReportHandler) unreported(targetType report.TargetType, id primitive.ObjectID) bool {
+	n, err := h.repo.CountOpen(context.Background(), targetType, id)
+	if err != nil {
+		log.Printf("⚠️ Failed to count open reports on %s %s: %v", targetType, id.Hex(), err)
+		return false
+	}
+	return n == 0
+}
+
+// setHidden hides reported blogs and comments from readers, or shows them
+// again. Authors are only triaged, never hidden.
+func (h *ReportHandler) setHidden(targetType report.TargetType, id primitive.ObjectID, hidden bool) error {
+	switch targetType {
+	case report.TargetBlog:
+		_, err := h.blogRepo.Update(context.Background(), id, bson.M{"hidden": hidden})
+		return err
+
+	case report.TargetComment:
+		cmt, err := h.commentRepo.GetByID(context.Background(), id)
+		if err != nil || cmt.Hidden == hidden {
+			return err
+		}
+		wasVisible := cmt.IsVisible()
+		if err := h.commentRepo.SetHidden(context.Background(), id, hidden); err != nil {
+			return err
+		}
+
+		// Keep the parent's reply count in line with what readers can see
+		cmt.Hidden = hidden
+		if cmt.ParentID != nil && wasVisible != cmt.IsVisible() {
+			delta := 1
+			if wasVisible {
+				delta = -1
+			}
+			return h.commentRepo.IncrementReplies(context.Background(), *cmt.ParentID, delta)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/report"
+)
+
+func TestCreateReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(h *ReportHandler, reporterID primitive.ObjectID, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", reporterID.Hex()) })
+		r.POST("/reports", h.CreateReport)
+
+		req, _ := http.NewRequest("POST", "/reports", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("HIDE: Blog reaching the report threshold", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mReport := new(MockReportRepo)
+		h := NewReportHandler(mReport, mBlog, nil, new(MockAuthorRepo), new(MockAuditRepo), 3)
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+		mReport.On("Create", mock.Anything, mock.Anything).Return(&report.Report{TargetType: report.TargetBlog, TargetID: blogID}, true, nil)
+		mReport.On("CountOpen", mock.Anything, report.TargetBlog, blogID).Return(int64(3), nil)
+		mBlog.On("Update", mock.Anything, blogID, bson.M{"hidden": true}).Return(&blog.Blog{ID: blogID, Hidden: true}, nil)
+
+		w := send(h, primitive.NewObjectID(), `{"target_type":"blog","target_id":"`+blogID.Hex()+`","reason":"plagiarism"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mBlog.AssertCalled(t, "Update", mock.Anything, blogID, bson.M{"hidden": true})
+	})
+
+	t.Run("DEDUPE: Repeat report from the same reader", func(t *testing.T) {
+		mBlog := new(MockBlogRepo)
+		mReport := new(MockReportRepo)
+		h := NewReportHandler(mReport, mBlog, nil, new(MockAuthorRepo), new(MockAuditRepo), 3)
+
+		blogID := primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, blogID).Return(&blog.Blog{ID: blogID}, nil)
+		mReport.On("Create", mock.Anything, mock.Anything).Return(&report.Report{TargetType: report.TargetBlog, TargetID: blogID}, false, nil)
+
+		w := send(h, primitive.NewObjectID(), `{"target_type":"blog","target_id":"`+blogID.Hex()+`","reason":"spam"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mReport.AssertNotCalled(t, "CountOpen", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("REJECT: Unknown reason", func(t *testing.T) {
+		mReport := new(MockReportRepo)
+		h := NewReportHandler(mReport, new(MockBlogRepo), nil, new(MockAuthorRepo), new(MockAuditRepo), 3)
+
+		w := send(h, primitive.NewObjectID(), `{"target_type":"blog","target_id":"`+primitive.NewObjectID().Hex()+`","reason":"boring"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mReport.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDismissReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	moderatorID := primitive.NewObjectID()
+	dismiss := func(h *ReportHandler, reportID primitive.ObjectID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", moderatorID.Hex()) })
+		r.POST("/reports/:id/dismiss", h.DismissReport)
+
+		req, _ := http.NewRequest("POST", "/reports/"+reportID.Hex()+"/dismiss", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	setup := func(openAfterClose int64) (*MockBlogRepo, *ReportHandler, primitive.ObjectID, primitive.ObjectID) {
+		mBlog := new(MockBlogRepo)
+		mReport := new(MockReportRepo)
+		mAuth := new(MockAuthorRepo)
+		h := NewReportHandler(mReport, mBlog, nil, mAuth, new(MockAuditRepo), 3)
+
+		reportID, blogID := primitive.NewObjectID(), primitive.NewObjectID()
+		mAuth.On("GetAuthorByID", moderatorID).Return(&author.Author{ID: moderatorID, Role: author.RoleModerator}, nil)
+		mReport.On("GetByID", mock.Anything, reportID).Return(&report.Report{ID: reportID, TargetType: report.TargetBlog, TargetID: blogID, Status: report.StatusOpen}, nil)
+		mReport.On("CloseTarget", mock.Anything, report.TargetBlog, blogID, report.StatusDismissed, moderatorID, "").Return(int64(3), nil)
+		mReport.On("CountOpen", mock.Anything, report.TargetBlog, blogID).Return(openAfterClose, nil)
+		mBlog.On("Update", mock.Anything, blogID, bson.M{"hidden": false}).Return(&blog.Blog{ID: blogID}, nil)
+		return mBlog, h, reportID, blogID
+	}
+
+	t.Run("SUCCESS: Dismissing the last open reports shows the blog again", func(t *testing.T) {
+		mBlog, h, reportID, blogID := setup(0)
+
+		w := dismiss(h, reportID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mBlog.AssertCalled(t, "Update", mock.Anything, blogID, bson.M{"hidden": false})
+	})
+
+	t.Run("KEEP HIDDEN: A newer report is still open", func(t *testing.T) {
+		mBlog, h, reportID, _ := setup(1)
+
+		w := dismiss(h, reportID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mBlog.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
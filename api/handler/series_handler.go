@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/series"
+	"razorblog-backend/internal/repository"
+)
+
+// SeriesHandler handles HTTP requests for multi-part post series
+type SeriesHandler struct {
+	repo     repository.ISeriesRepository
+	blogRepo repository.IBlogRepository
+}
+
+func NewSeriesHandler(repo repository.ISeriesRepository, blogRepo repository.IBlogRepository) *SeriesHandler {
+	return &SeriesHandler{
+		repo:     repo,
+		blogRepo: blogRepo,
+	}
+}
+
+type seriesRequest struct {
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	PostIDs     []string `json:"post_ids"`
+}
+
+// CreateSeries godoc
+// @Summary Create a series
+// @Description Groups the logged-in author's posts into an ordered series
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param series body object{title=string,description=string,post_ids=[]string} true "Series info"
+// @Success 201 {object} series.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /series [post]
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return
+	}
+
+	var req seriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	postIDs, ok := h.validatePosts(c, authorID, primitive.NilObjectID, req.PostIDs)
+	if !ok {
+		return
+	}
+
+	created, err := h.repo.Create(context.Background(), &series.Series{
+		AuthorID:    authorID,
+		Title:       req.Title,
+		Description: req.Description,
+		PostIDs:     postIDs,
+	})
+	// Another series may have claimed one of the posts since they were checked
+	if errors.Is(err, series.ErrPostInSeries) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create series"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetSeries godoc
+// @Summary Get a series
+// @Description Returns a series with its posts in reading order. Hidden and trashed posts are left out.
+// @Tags Series
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id} [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series ID"})
+		return
+	}
+
+	s, err := h.repo.GetByID(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "series not found"})
+		return
+	}
+
+	// Hidden and trashed posts are left out and the remaining parts renumbered
+	parts, err := listedParts(context.Background(), h.blogRepo, s.PostIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch series posts"})
+		return
+	}
+	posts := make([]gin.H, 0, len(parts))
+	for i, b := range parts {
+		posts = append(posts, gin.H{
+			"part":  i + 1,
+			"id":    b.ID,
+			"title": b.Title,
+			"type":  b.Type,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": s,
+		"posts":  posts,
+	})
+}
+
+// listedParts returns the posts of a series that readers can see, in
+// series order
+func listedParts(ctx context.Context, blogRepo repository.IBlogRepository, postIDs []primitive.ObjectID) ([]*blog.Blog, error) {
+	blogs, err := blogRepo.GetListedByIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*blog.Blog, len(blogs))
+	for _, b := range blogs {
+		byID[b.ID] = b
+	}
+
+	parts := make([]*blog.Blog, 0, len(blogs))
+	for _, id := range postIDs {
+		if b, ok := byID[id]; ok {
+			parts = append(parts, b)
+		}
+	}
+	return parts, nil
+}
+
+// ListSeriesByAuthor godoc
+// @Summary List series for an author
+// @Description Returns all series owned by the given author
+// @Tags Series
+// @Produce json
+// @Param author_id path string true "Author ID"
+// @Success 200 {array} series.Series
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/author/{author_id} [get]
+func (h *SeriesHandler) ListSeriesByAuthor(c *gin.Context) {
+	authorID, err := primitive.ObjectIDFromHex(c.Param("author_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+
+	list, err := h.repo.ListByAuthor(context.Background(), authorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch series"})
+		return
+	}
+	if list == nil {
+		list = []*series.Series{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// UpdateSeries godoc
+// @Summary Update a series
+// @Description Updates the title, description and post order of a series owned by the logged-in author
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param series body object{title=string,description=string,post_ids=[]string} true "Series info"
+// @Success 200 {object} series.Series
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	s, authorID, ok := h.ownedSeries(c)
+	if !ok {
+		return
+	}
+
+	var req seriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	postIDs, ok := h.validatePosts(c, authorID, s.ID, req.PostIDs)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.Update(context.Background(), s.ID, bson.M{
+		"title":       req.Title,
+		"description": req.Description,
+		"post_ids":    postIDs,
+	})
+	if errors.Is(err, series.ErrPostInSeries) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteSeries godoc
+// @Summary Delete a series
+// @Description Deletes a series owned by the logged-in author; its posts are kept
+// @Tags Series
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	s, _, ok := h.ownedSeries(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(context.Background(), s.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series deleted"})
+}
+
+// ownedSeries loads the series in the URL and checks the caller owns it
+func (h *SeriesHandler) ownedSeries(c *gin.Context) (*series.Series, primitive.ObjectID, bool) {
+	authorID, ok := currentAuthorID(c)
+	if !ok {
+		return nil, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series ID"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	s, err := h.repo.GetByID(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "series not found"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	if s.AuthorID != authorID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only modify your own series"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	return s, authorID, true
+}
+
+// validatePosts parses the post IDs of a series and checks that every post
+// exists, belongs to the author, appears once, and is not already part of
+// a different series.
+func (h *SeriesHandler) validatePosts(c *gin.Context, authorID, seriesID primitive.ObjectID, ids []string) ([]primitive.ObjectID, bool) {
+	postIDs := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+
+	for _, idStr := range ids {
+		postID, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID: " + idStr})
+			return nil, false
+		}
+		if seen[postID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "post listed twice: " + idStr})
+			return nil, false
+		}
+		seen[postID] = true
+
+		b, err := h.blogRepo.GetByID(context.Background(), postID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "post not found: " + idStr})
+			return nil, false
+		}
+		if b.AuthorID != authorID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can only add your own posts: " + idStr})
+			return nil, false
+		}
+
+		existing, err := h.repo.GetByPost(context.Background(), postID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		if existing != nil && existing.ID != seriesID {
+			c.JSON(http.StatusConflict, gin.H{"error": "post already belongs to another series: " + idStr})
+			return nil, false
+		}
+
+		postIDs = append(postIDs, postID)
+	}
+
+	return postIDs, true
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/series"
+)
+
+// seriesFixture is a four-part series whose second part is hidden or trashed
+func seriesFixture() (*series.Series, []*blog.Blog) {
+	parts := []*blog.Blog{
+		{ID: primitive.NewObjectID(), Title: "Part one"},
+		{ID: primitive.NewObjectID(), Title: "Part two"},
+		{ID: primitive.NewObjectID(), Title: "Part three"},
+		{ID: primitive.NewObjectID(), Title: "Part four"},
+	}
+	s := &series.Series{ID: primitive.NewObjectID(), Title: "Building a blog"}
+	for _, p := range parts {
+		s.PostIDs = append(s.PostIDs, p.ID)
+	}
+	return s, parts
+}
+
+func TestGetSeries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(h *SeriesHandler, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/series/:id", h.GetSeries)
+
+		req, _ := http.NewRequest("GET", "/series/"+id, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Lists readable parts in order and renumbers them", func(t *testing.T) {
+		s, parts := seriesFixture()
+		mSeries, mBlog := new(MockSeriesRepo), new(MockBlogRepo)
+		mSeries.On("GetByID", mock.Anything, s.ID).Return(s, nil)
+		mBlog.On("GetListedByIDs", mock.Anything, s.PostIDs).Return([]*blog.Blog{parts[3], parts[0], parts[2]}, nil)
+
+		w := send(NewSeriesHandler(mSeries, mBlog), s.ID.Hex())
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Posts []struct {
+				Part  int    `json:"part"`
+				Title string `json:"title"`
+			} `json:"posts"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Posts, 3)
+		assert.Equal(t, "Part one", resp.Posts[0].Title)
+		assert.Equal(t, "Part three", resp.Posts[1].Title)
+		assert.Equal(t, 2, resp.Posts[1].Part)
+		assert.Equal(t, "Part four", resp.Posts[2].Title)
+	})
+
+	t.Run("Missing series returns 404", func(t *testing.T) {
+		mSeries := new(MockSeriesRepo)
+		id := primitive.NewObjectID()
+		mSeries.On("GetByID", mock.Anything, id).Return(nil, assert.AnError)
+
+		w := send(NewSeriesHandler(mSeries, new(MockBlogRepo)), id.Hex())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid ID returns 400", func(t *testing.T) {
+		w := send(NewSeriesHandler(new(MockSeriesRepo), new(MockBlogRepo)), "nope")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetBlog_SeriesNavigation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(s *series.Series, listed []*blog.Blog, post *blog.Blog) map[string]interface{} {
+		mBlog, mAuth, mSeries := new(MockBlogRepo), new(MockAuthorRepo), new(MockSeriesRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mBlog.On("GetListedByIDs", mock.Anything, s.PostIDs).Return(listed, nil)
+		mAuth.On("GetAuthorByID", mock.Anything).Return(&author.Author{Name: "Ada"}, nil)
+		mSeries.On("GetByPost", mock.Anything, post.ID).Return(s, nil)
+		h := NewBlogHandler(mBlog, mAuth, BlogDeps{Series: mSeries})
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/blogs/:id", h.GetBlog)
+		req, _ := http.NewRequest("GET", "/blogs/"+post.ID.Hex(), nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Series map[string]interface{} `json:"series"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Series
+	}
+
+	t.Run("Steps over a hidden or trashed neighbour", func(t *testing.T) {
+		s, parts := seriesFixture()
+		nav := get(s, []*blog.Blog{parts[0], parts[2], parts[3]}, parts[2])
+
+		assert.EqualValues(t, 2, nav["part"])
+		assert.EqualValues(t, 3, nav["total"])
+		assert.Equal(t, "Part one", nav["previous"].(map[string]interface{})["title"])
+		assert.Equal(t, "Part four", nav["next"].(map[string]interface{})["title"])
+	})
+
+	t.Run("Last readable part has no next", func(t *testing.T) {
+		s, parts := seriesFixture()
+		nav := get(s, []*blog.Blog{parts[0], parts[1], parts[2]}, parts[2])
+
+		assert.EqualValues(t, 3, nav["part"])
+		assert.Nil(t, nav["next"])
+	})
+}
+
+func TestCreateSeries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("CONFLICT: Post claimed by another series after it was checked", func(t *testing.T) {
+		mSeries := new(MockSeriesRepo)
+		mBlog := new(MockBlogRepo)
+		h := NewSeriesHandler(mSeries, mBlog)
+
+		authorID, postID := primitive.NewObjectID(), primitive.NewObjectID()
+		mBlog.On("GetByID", mock.Anything, postID).Return(&blog.Blog{ID: postID, AuthorID: authorID}, nil)
+		mSeries.On("GetByPost", mock.Anything, postID).Return(nil, nil)
+		mSeries.On("Create", mock.Anything, mock.Anything).Return(nil, series.ErrPostInSeries)
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) { c.Set("author_id", authorID.Hex()) })
+		r.POST("/series", h.CreateSeries)
+
+		body := `{"title":"Building a blog","post_ids":["` + postID.Hex() + `"]}`
+		req, _ := http.NewRequest("POST", "/series", bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
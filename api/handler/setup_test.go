@@ -2,11 +2,24 @@ package handler
 
 import (
 	"context"
+	"io"
+	"time"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/audit"
 	"razorblog-backend/internal/models/author"
 	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/bookmark"
+	"razorblog-backend/internal/models/export"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/models/reaction"
+	"razorblog-backend/internal/models/related"
+	"razorblog-backend/internal/models/report"
+	"razorblog-backend/internal/models/series"
+	share "razorblog-backend/internal/models/share"
+	"razorblog-backend/internal/service"
 )
 
 // --- MOCK BLOG REPO ---
@@ -27,11 +40,57 @@ func (m *MockBlogRepo) Update(ctx context.Context, id primitive.ObjectID, u bson
 	return args.Get(0).(*blog.Blog), args.Error(1)
 }
 func (m *MockBlogRepo) Delete(ctx context.Context, id primitive.ObjectID) error { return m.Called(ctx, id).Error(0) }
+func (m *MockBlogRepo) Restore(ctx context.Context, id, aID primitive.ObjectID) error { return m.Called(ctx, id, aID).Error(0) }
+func (m *MockBlogRepo) ListTrash(ctx context.Context, aID primitive.ObjectID) ([]*blog.Blog, error) {
+	args := m.Called(ctx, aID)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).([]*blog.Blog), args.Error(1)
+}
 func (m *MockBlogRepo) List(ctx context.Context, l, s int64) ([]*blog.Blog, error) { return nil, nil }
 func (m *MockBlogRepo) ListByAuthor(ctx context.Context, id primitive.ObjectID) ([]*blog.Blog, error) { return nil, nil }
-func (m *MockBlogRepo) IncrementReaders(ctx context.Context, id primitive.ObjectID) error { return nil }
-func (m *MockBlogRepo) LikeBlog(ctx context.Context, bID, uID primitive.ObjectID) error { return nil }
+func (m *MockBlogRepo) ListAllByAuthor(ctx context.Context, id primitive.ObjectID) ([]*blog.Blog, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) ListTrending(ctx context.Context, docType blog.DocumentType, limit int64) ([]*blog.Blog, error) {
+	args := m.Called(ctx, docType, limit)
+	return args.Get(0).([]*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) ListFeed(ctx context.Context, ids []primitive.ObjectID, after *blog.Cursor, limit int64) ([]*blog.Blog, error) {
+	args := m.Called(ctx, ids, after, limit)
+	return args.Get(0).([]*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) GetListedByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*blog.Blog, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) ListTDDs(ctx context.Context, s blog.DecisionStatus, l, sk int64) ([]*blog.Blog, error) { return nil, nil }
+func (m *MockBlogRepo) CountView(ctx context.Context, id primitive.ObjectID, unique bool) error {
+	args := m.Called(ctx, id, unique)
+	return args.Error(0)
+}
+func (m *MockBlogRepo) LikeBlog(ctx context.Context, bID, uID primitive.ObjectID) (bool, error) { return true, nil }
 func (m *MockBlogRepo) UnlikeBlog(ctx context.Context, bID, uID primitive.ObjectID) error { return nil }
+func (m *MockBlogRepo) InviteCoAuthor(ctx context.Context, bID, aID primitive.ObjectID, role blog.ContributionRole) (*blog.Blog, error) {
+	args := m.Called(ctx, bID, aID, role)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) RespondToInvite(ctx context.Context, bID, aID primitive.ObjectID, status blog.InviteStatus) (*blog.Blog, error) {
+	args := m.Called(ctx, bID, aID, status)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) RemoveCoAuthor(ctx context.Context, bID, aID primitive.ObjectID) (*blog.Blog, error) {
+	args := m.Called(ctx, bID, aID)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*blog.Blog), args.Error(1)
+}
+func (m *MockBlogRepo) ReorderCoAuthors(ctx context.Context, bID primitive.ObjectID, current, ordered []blog.CoAuthor) (*blog.Blog, error) {
+	args := m.Called(ctx, bID, current, ordered)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*blog.Blog), args.Error(1)
+}
 
 // --- MOCK AUTHOR REPO ---
 type MockAuthorRepo struct{ mock.Mock }
@@ -46,6 +105,16 @@ func (m *MockAuthorRepo) GetAuthorByEmail(e string) (*author.Author, error) {
 	if args.Get(0) == nil { return nil, args.Error(1) }
 	return args.Get(0).(*author.Author), args.Error(1)
 }
+func (m *MockAuthorRepo) GetAuthorByName(n string) (*author.Author, error) {
+	args := m.Called(n)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*author.Author), args.Error(1)
+}
+func (m *MockAuthorRepo) GetAuthorsByNamesOrIDs(names []string, ids []primitive.ObjectID) ([]*author.Author, error) {
+	args := m.Called(names, ids)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).([]*author.Author), args.Error(1)
+}
 func (m *MockAuthorRepo) CreateAuthor(a *author.Author) (*author.Author, error) {
 	args := m.Called(a)
 	return args.Get(0).(*author.Author), args.Error(1)
@@ -54,3 +123,287 @@ func (m *MockAuthorRepo) UpdateAuthor(id primitive.ObjectID, u bson.M) error {
 	return m.Called(id, u).Error(0)
 }
 func (m *MockAuthorRepo) DeleteAuthor(id primitive.ObjectID) error { return m.Called(id).Error(0) }
+
+// --- MOCK SERIES REPO ---
+type MockSeriesRepo struct{ mock.Mock }
+
+func (m *MockSeriesRepo) Create(ctx context.Context, s *series.Series) (*series.Series, error) {
+	args := m.Called(ctx, s)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*series.Series), args.Error(1)
+}
+func (m *MockSeriesRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*series.Series, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*series.Series), args.Error(1)
+}
+func (m *MockSeriesRepo) GetByPost(ctx context.Context, id primitive.ObjectID) (*series.Series, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*series.Series), args.Error(1)
+}
+func (m *MockSeriesRepo) ListByAuthor(ctx context.Context, id primitive.ObjectID) ([]*series.Series, error) { return nil, nil }
+func (m *MockSeriesRepo) Update(ctx context.Context, id primitive.ObjectID, u bson.M) (*series.Series, error) {
+	args := m.Called(ctx, id, u)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*series.Series), args.Error(1)
+}
+func (m *MockSeriesRepo) Delete(ctx context.Context, id primitive.ObjectID) error { return m.Called(ctx, id).Error(0) }
+
+// --- MOCK AUTHOR DELETION SERVICE ---
+type MockAuthorDeleter struct{ mock.Mock }
+
+func (m *MockAuthorDeleter) DeleteAuthor(ctx context.Context, id primitive.ObjectID, o service.DeletionOptions) (*service.DeletionReport, error) {
+	args := m.Called(ctx, id, o)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*service.DeletionReport), args.Error(1)
+}
+
+// --- MOCK DATA EXPORT SERVICE ---
+type MockDataExporter struct{ mock.Mock }
+
+func (m *MockDataExporter) Collect(ctx context.Context, id primitive.ObjectID) (*service.ExportBundle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*service.ExportBundle), args.Error(1)
+}
+func (m *MockDataExporter) IsLarge(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockDataExporter) StartJob(ctx context.Context, id primitive.ObjectID) (*export.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*export.Job), args.Error(1)
+}
+func (m *MockDataExporter) GetJob(ctx context.Context, jobID, authorID primitive.ObjectID) (*export.Job, error) {
+	args := m.Called(ctx, jobID, authorID)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*export.Job), args.Error(1)
+}
+func (m *MockDataExporter) DownloadJob(ctx context.Context, job *export.Job, w io.Writer) error {
+	args := m.Called(ctx, job, w)
+	if data, ok := args.Get(0).(string); ok {
+		_, _ = io.WriteString(w, data)
+	}
+	return args.Error(1)
+}
+
+// --- MOCK AUDIT REPO ---
+type MockAuditRepo struct{ mock.Mock }
+
+func (m *MockAuditRepo) Record(ctx context.Context, e *audit.Event) error { return nil }
+func (m *MockAuditRepo) ListByActor(ctx context.Context, id primitive.ObjectID) ([]*audit.Event, error) { return nil, nil }
+
+// --- Reaction Repo Mock ---
+type MockReactionRepo struct{ mock.Mock }
+
+func (m *MockReactionRepo) Add(ctx context.Context, r *reaction.Reaction) (bool, error) {
+	args := m.Called(ctx, r)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockReactionRepo) Remove(ctx context.Context, t reaction.TargetType, id primitive.ObjectID, k reaction.Kind, reactor string) (bool, error) {
+	args := m.Called(ctx, t, id, k, reactor)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockReactionRepo) Counts(ctx context.Context, t reaction.TargetType, id primitive.ObjectID) (map[reaction.Kind]int64, error) {
+	args := m.Called(ctx, t, id)
+	return args.Get(0).(map[reaction.Kind]int64), args.Error(1)
+}
+func (m *MockReactionRepo) ListReactors(ctx context.Context, t reaction.TargetType, id primitive.ObjectID, k reaction.Kind, limit, skip int64) ([]*reaction.Reaction, error) {
+	return nil, nil
+}
+
+// --- Report Repo Mock ---
+type MockReportRepo struct{ mock.Mock }
+
+func (m *MockReportRepo) Create(ctx context.Context, r *report.Report) (*report.Report, bool, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*report.Report), args.Bool(1), args.Error(2)
+}
+func (m *MockReportRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*report.Report, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*report.Report), args.Error(1)
+}
+func (m *MockReportRepo) CountOpen(ctx context.Context, t report.TargetType, id primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, t, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockReportRepo) List(ctx context.Context, s report.Status, t report.TargetType, limit, skip int64) ([]*report.Report, error) {
+	return nil, nil
+}
+func (m *MockReportRepo) CloseTarget(ctx context.Context, t report.TargetType, id primitive.ObjectID, s report.Status, moderatorID primitive.ObjectID, note string) (int64, error) {
+	args := m.Called(ctx, t, id, s, moderatorID, note)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// --- MOCK NOTIFICATION REPO ---
+type MockNotificationRepo struct{ mock.Mock }
+
+func (m *MockNotificationRepo) CreateMany(ctx context.Context, notes []*notification.Notification) error {
+	return m.Called(ctx, notes).Error(0)
+}
+func (m *MockNotificationRepo) List(ctx context.Context, recipientID primitive.ObjectID, unreadOnly bool, limit, skip int64) ([]*notification.Notification, error) {
+	args := m.Called(ctx, recipientID, unreadOnly, limit, skip)
+	return args.Get(0).([]*notification.Notification), args.Error(1)
+}
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, recipientID primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, recipientID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, id, recipientID primitive.ObjectID) error {
+	return m.Called(ctx, id, recipientID).Error(0)
+}
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, recipientID primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, recipientID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockNotificationRepo) GetPreferences(ctx context.Context, authorID primitive.ObjectID) (*notification.Preferences, error) {
+	args := m.Called(ctx, authorID)
+	return args.Get(0).(*notification.Preferences), args.Error(1)
+}
+func (m *MockNotificationRepo) SetPreferences(ctx context.Context, prefs *notification.Preferences) error {
+	return m.Called(ctx, prefs).Error(0)
+}
+func (m *MockNotificationRepo) MutedBy(ctx context.Context, t notification.Type, authorIDs []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, t, authorIDs)
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+// --- MOCK FOLLOW REPO ---
+type MockFollowRepo struct{ mock.Mock }
+
+func (m *MockFollowRepo) Follow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, followerID, followeeID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockFollowRepo) Unfollow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, followerID, followeeID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockFollowRepo) IsFollowing(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, followerID, followeeID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockFollowRepo) Following(ctx context.Context, followerID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, followerID)
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+// --- Mock Bookmark Repository ---
+type MockBookmarkRepo struct{ mock.Mock }
+
+func (m *MockBookmarkRepo) Add(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, authorID, blogID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockBookmarkRepo) Remove(ctx context.Context, authorID, blogID primitive.ObjectID) error {
+	args := m.Called(ctx, authorID, blogID)
+	return args.Error(0)
+}
+func (m *MockBookmarkRepo) IsBookmarked(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, authorID, blogID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockBookmarkRepo) List(ctx context.Context, authorID primitive.ObjectID, limit, skip int64) ([]*bookmark.Bookmark, error) {
+	args := m.Called(ctx, authorID, limit, skip)
+	return args.Get(0).([]*bookmark.Bookmark), args.Error(1)
+}
+func (m *MockBookmarkRepo) CreateList(ctx context.Context, l *bookmark.ReadingList) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, l)
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) GetList(ctx context.Context, id primitive.ObjectID) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) ListLists(ctx context.Context, ownerID primitive.ObjectID, publicOnly bool) ([]*bookmark.ReadingList, error) {
+	args := m.Called(ctx, ownerID, publicOnly)
+	return args.Get(0).([]*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) UpdateList(ctx context.Context, id primitive.ObjectID, update bson.M) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id, update)
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) AddEntry(ctx context.Context, id primitive.ObjectID, e bookmark.Entry) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id, e)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) SetEntryNote(ctx context.Context, id, blogID primitive.ObjectID, note string) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id, blogID, note)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) RemoveEntry(ctx context.Context, id, blogID primitive.ObjectID) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id, blogID)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) ReorderEntries(ctx context.Context, id primitive.ObjectID, current, ordered []bookmark.Entry) (*bookmark.ReadingList, error) {
+	args := m.Called(ctx, id, current, ordered)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*bookmark.ReadingList), args.Error(1)
+}
+func (m *MockBookmarkRepo) DeleteList(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// --- Mock Analytics Repository ---
+type MockAnalyticsRepo struct{ mock.Mock }
+
+func (m *MockAnalyticsRepo) Record(ctx context.Context, e analytics.Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+func (m *MockAnalyticsRepo) Series(ctx context.Context, ids []primitive.ObjectID, g analytics.Granularity, from, to time.Time) ([]analytics.Point, error) {
+	args := m.Called(ctx, ids, g, from, to)
+	return args.Get(0).([]analytics.Point), args.Error(1)
+}
+func (m *MockAnalyticsRepo) Breakdown(ctx context.Context, ids []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) (map[string]int, error) {
+	args := m.Called(ctx, ids, g, from, to, field)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+func (m *MockAnalyticsRepo) BreakdownSeries(ctx context.Context, ids []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) ([]analytics.LabelPoint, error) {
+	args := m.Called(ctx, ids, g, from, to, field)
+	return args.Get(0).([]analytics.LabelPoint), args.Error(1)
+}
+func (m *MockAnalyticsRepo) TopPosts(ctx context.Context, ids []primitive.ObjectID, g analytics.Granularity, from, to time.Time, by analytics.Metric, limit int64) ([]analytics.PostTotals, error) {
+	args := m.Called(ctx, ids, g, from, to, by, limit)
+	return args.Get(0).([]analytics.PostTotals), args.Error(1)
+}
+func (m *MockAnalyticsRepo) Popular(ctx context.Context, g analytics.Granularity, from, to time.Time, weights map[analytics.Metric]float64, docType blog.DocumentType, limit int64) ([]analytics.PostTotals, error) {
+	args := m.Called(ctx, g, from, to, weights, docType, limit)
+	return args.Get(0).([]analytics.PostTotals), args.Error(1)
+}
+
+// --- Mock Related Repository ---
+type MockRelatedRepo struct{ mock.Mock }
+
+func (m *MockRelatedRepo) Get(ctx context.Context, blogID primitive.ObjectID) ([]related.Match, error) {
+	args := m.Called(ctx, blogID)
+	return args.Get(0).([]related.Match), args.Error(1)
+}
+
+// --- Mock Share Repository ---
+type MockShareRepo struct{ mock.Mock }
+
+func (m *MockShareRepo) Create(ctx context.Context, s *share.Share) (*share.Share, error) {
+	args := m.Called(ctx, s)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*share.Share), args.Error(1)
+}
+func (m *MockShareRepo) List(ctx context.Context, blogID primitive.ObjectID, limit, skip int64) ([]*share.Share, error) {
+	args := m.Called(ctx, blogID, limit, skip)
+	return args.Get(0).([]*share.Share), args.Error(1)
+}
+func (m *MockShareRepo) Claim(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error) {
+	args := m.Called(ctx, key, now, window)
+	return args.Bool(0), args.Error(1)
+}
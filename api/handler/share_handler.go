@@ -2,33 +2,78 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/notification"
 	models "razorblog-backend/internal/models/share"
+	"razorblog-backend/internal/service"
 )
 
+// maxShareLimit caps how many shares one page returns
+const maxShareLimit = 100
+
 // ShareHandler handles HTTP requests for blog shares
 type ShareHandler struct {
-	repo *repository.ShareRepository
+	repo      repository.IShareRepository
+	blogRepo  repository.IBlogRepository
+	notifier  service.INotificationService
+	analytics repository.IAnalyticsRepository
+	settings  ShareSettings
+	platforms map[string]bool
 }
 
-func NewShareHandler(repo *repository.ShareRepository) *ShareHandler {
-	return &ShareHandler{repo: repo}
+// ShareSettings holds the site-wide share policy
+type ShareSettings struct {
+	Platforms   []string      // Platforms a share may name; empty allows models.DefaultPlatforms
+	DedupWindow time.Duration // Repeat shares of a post to a platform by one client within this window are not counted; 0 counts every share
+	Salt        string        // Mixed into the hashes that identify anonymous clients; random per process when empty
+}
+
+func NewShareHandler(repo repository.IShareRepository, blogRepo repository.IBlogRepository, notifier service.INotificationService, analyticsRepo repository.IAnalyticsRepository, settings ShareSettings) *ShareHandler {
+	allowed := settings.Platforms
+	if len(allowed) == 0 {
+		allowed = models.DefaultPlatforms
+	}
+	platforms := make(map[string]bool, len(allowed))
+	settings.Platforms = make([]string, 0, len(allowed))
+	for _, p := range allowed {
+		if p = models.NormalizePlatform(p); p != "" && !platforms[p] {
+			platforms[p] = true
+			settings.Platforms = append(settings.Platforms, p)
+		}
+	}
+	if settings.Salt == "" {
+		// Unsalted client hashes could be reversed by hashing every IPv4 address
+		buf := make([]byte, 32)
+		_, _ = rand.Read(buf)
+		settings.Salt = hex.EncodeToString(buf)
+	}
+	return &ShareHandler{repo: repo, blogRepo: blogRepo, notifier: notifier, analytics: analyticsRepo, settings: settings, platforms: platforms}
 }
 
 // CreateShare godoc
 // @Summary Create a new share
-// @Description Records a share of a blog post on a platform
+// @Description Records a share of a blog post on a platform. The platform must be one of the site's allowed platforms. Repeating a share of the same post to the same platform shortly afterwards is accepted but not counted again.
 // @Tags Shares
 // @Accept json
 // @Produce json
 // @Param share body map[string]string true "Share info (blog_id, platform)"
 // @Success 201 {object} models.Share
+// @Success 200 {object} map[string]interface{} "Repeat share, not counted"
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /shares [post]
 func (h *ShareHandler) CreateShare(c *gin.Context) {
@@ -38,30 +83,82 @@ func (h *ShareHandler) CreateShare(c *gin.Context) {
 		return
 	}
 
+	s.Platform = models.NormalizePlatform(s.Platform)
 	if s.BlogID.IsZero() || s.Platform == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "blog_id and platform are required"})
 		return
 	}
+	if !h.platforms[s.Platform] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "platform must be one of: " + strings.Join(h.settings.Platforms, ", ")})
+		return
+	}
+
+	ctx := context.Background()
+	b, err := h.blogRepo.GetByID(ctx, s.BlogID)
+	if err != nil || b.Hidden {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	if h.settings.DedupWindow > 0 {
+		// A failed check counts the share rather than losing it
+		counted, err := h.repo.Claim(ctx, h.shareKey(c, &s), time.Now(), h.settings.DedupWindow)
+		if err != nil {
+			log.Printf("⚠️ Failed to check for a repeat share of blog %s: %v", s.BlogID.Hex(), err)
+		} else if !counted {
+			c.JSON(http.StatusOK, gin.H{"blog_id": s.BlogID, "platform": s.Platform, "duplicate": true})
+			return
+		}
+	}
 
-	created, err := h.repo.Create(context.Background(), &s)
+	created, err := h.repo.Create(ctx, &s)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create share"})
 		return
 	}
 
+	recordActivity(h.analytics, analytics.Event{
+		BlogID:   created.BlogID,
+		At:       created.CreatedAt,
+		Counts:   analytics.Counts{Shares: 1},
+		Platform: created.Platform,
+	})
+
+	if h.notifier != nil {
+		notifyPostAuthors(h.notifier, b, nil, notification.Notification{
+			Type:     notification.TypeShare,
+			BlogID:   &created.BlogID,
+			Platform: created.Platform,
+		})
+	}
+
 	c.JSON(http.StatusCreated, created)
 }
 
+// shareKey identifies a share by post, platform and client. Logged-in
+// authors are recognised by account and anyone else by IP address and user
+// agent, hashed so neither is stored.
+func (h *ShareHandler) shareKey(c *gin.Context, s *models.Share) string {
+	client := "h:" + c.ClientIP() + "|" + c.Request.UserAgent()
+	if id := optionalAuthorID(c); id != nil {
+		client = "u:" + id.Hex()
+	}
+	sum := sha256.Sum256([]byte(h.settings.Salt + "|" + s.BlogID.Hex() + "|" + s.Platform + "|" + client))
+	return hex.EncodeToString(sum[:])
+}
+
 // ListShares godoc
 // @Summary List shares for a blog
-// @Description Returns a list of shares for a specific blog post
+// @Description Returns a page of a blog post's shares, newest first. Blogs carry their share counts per platform, and /shares/{blog_id}/stats reports them over time, so most clients do not need the raw events.
 // @Tags Shares
 // @Produce json
 // @Param blog_id path string true "Blog ID"
+// @Param limit query int false "Number of shares, at most 100" default(20)
+// @Param skip query int false "Number of shares to skip" default(0)
 // @Success 200 {array} models.Share
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /blogs/{blog_id}/shares [get]
+// @Router /shares/{blog_id} [get]
 func (h *ShareHandler) ListShares(c *gin.Context) {
 	blogIDStr := c.Param("blog_id")
 	blogID, err := primitive.ObjectIDFromHex(blogIDStr)
@@ -70,12 +167,86 @@ func (h *ShareHandler) ListShares(c *gin.Context) {
 		return
 	}
 
-	shares, err := h.repo.List(context.Background(), blogID)
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxShareLimit {
+		limit = maxShareLimit
+	}
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+	if skip < 0 {
+		skip = 0
+	}
+
+	shares, err := h.repo.List(context.Background(), blogID, limit, skip)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch shares"})
 		return
 	}
+	if shares == nil {
+		shares = []*models.Share{}
+	}
 
 	c.JSON(http.StatusOK, shares)
 }
 
+// GetShareStats godoc
+// @Summary Get share counts for a blog
+// @Description Returns a blog's shares per platform as a time series, with totals for the range and for all time. Shares made before share analytics were recorded only appear in the all-time totals. Hourly data is kept for 90 days.
+// @Tags Shares
+// @Produce json
+// @Param blog_id path string true "Blog ID"
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive when a plain date (YYYY-MM-DD or RFC 3339), default now"
+// @Param granularity query string false "hour or day" default(day)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shares/{blog_id}/stats [get]
+func (h *ShareHandler) GetShareStats(c *gin.Context) {
+	blogID, err := primitive.ObjectIDFromHex(c.Param("blog_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blog ID"})
+		return
+	}
+	g := analytics.Granularity(c.DefaultQuery("granularity", string(analytics.Daily)))
+	if !g.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be hour or day"})
+		return
+	}
+	from, to, err := analyticsRange(c, g, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	b, err := h.blogRepo.GetByID(ctx, blogID)
+	if err != nil || b.Hidden {
+		c.JSON(http.StatusNotFound, gin.H{"error": "blog not found"})
+		return
+	}
+
+	points, err := h.analytics.BreakdownSeries(ctx, []primitive.ObjectID{blogID}, g, from, to, "platforms")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch share stats"})
+		return
+	}
+	series, totals := fillLabelSeries(points, g, from, to)
+
+	allTime := b.Shares
+	if allTime == nil {
+		allTime = map[string]int{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":        from,
+		"to":          to,
+		"granularity": g,
+		"totals":      totals,
+		"all_time":    allTime,
+		"series":      series,
+	})
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/blog"
+	share "razorblog-backend/internal/models/share"
+)
+
+func TestCreateShare(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	post := &blog.Blog{ID: primitive.NewObjectID(), Title: "Shared"}
+	settings := ShareSettings{Platforms: []string{"Twitter", "mastodon"}, DedupWindow: time.Minute}
+
+	send := func(h *ShareHandler, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.POST("/shares", h.CreateShare)
+
+		req, _ := http.NewRequest("POST", "/shares", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	body := func(platform string) string {
+		return `{"blog_id":"` + post.ID.Hex() + `","platform":"` + platform + `"}`
+	}
+
+	t.Run("Counts a share under its normalized platform", func(t *testing.T) {
+		mRepo, mBlog, mAnalytics := new(MockShareRepo), new(MockBlogRepo), new(MockAnalyticsRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(true, nil)
+		mRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *share.Share) bool {
+			return s.Platform == "twitter"
+		})).Return(&share.Share{ID: primitive.NewObjectID(), BlogID: post.ID, Platform: "twitter", CreatedAt: time.Now()}, nil)
+		mAnalytics.On("Record", mock.Anything, mock.MatchedBy(func(e analytics.Event) bool {
+			return e.Platform == "twitter" && e.Counts.Shares == 1
+		})).Return(nil)
+
+		w := send(NewShareHandler(mRepo, mBlog, nil, mAnalytics, settings), body(" Twitter"))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mRepo.AssertExpectations(t)
+		assert.Eventually(t, func() bool { return len(mAnalytics.Calls) == 1 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Repeat share is accepted but not counted", func(t *testing.T) {
+		mRepo, mBlog := new(MockShareRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(false, nil)
+
+		w := send(NewShareHandler(mRepo, mBlog, nil, nil, settings), body("mastodon"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"duplicate":true`)
+		mRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Same client gets the same key for a post and platform", func(t *testing.T) {
+		mRepo, mBlog := new(MockShareRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(false, nil)
+		h := NewShareHandler(mRepo, mBlog, nil, nil, settings)
+
+		send(h, body("twitter"))
+		send(h, body("TWITTER"))
+		send(h, body("mastodon"))
+		keys := []string{}
+		for _, call := range mRepo.Calls {
+			keys = append(keys, call.Arguments.String(1))
+		}
+		assert.Len(t, keys, 3)
+		assert.Equal(t, keys[0], keys[1])
+		assert.NotEqual(t, keys[0], keys[2])
+	})
+
+	t.Run("Unknown platform returns 400", func(t *testing.T) {
+		mRepo, mBlog := new(MockShareRepo), new(MockBlogRepo)
+
+		w := send(NewShareHandler(mRepo, mBlog, nil, nil, settings), body("myspace"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "twitter, mastodon")
+		mBlog.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Default platforms apply when none are configured", func(t *testing.T) {
+		mRepo, mBlog := new(MockShareRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mRepo.On("Create", mock.Anything, mock.Anything).Return(&share.Share{BlogID: post.ID, Platform: "linkedin"}, nil)
+
+		w := send(NewShareHandler(mRepo, mBlog, nil, nil, ShareSettings{}), body("linkedin"))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mRepo.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Hidden blog returns 404", func(t *testing.T) {
+		mRepo, mBlog := new(MockShareRepo), new(MockBlogRepo)
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(&blog.Blog{ID: post.ID, Hidden: true}, nil)
+
+		w := send(NewShareHandler(mRepo, mBlog, nil, nil, settings), body("twitter"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetShareStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	post := &blog.Blog{ID: primitive.NewObjectID(), Shares: map[string]int{"twitter": 9, "email": 1}}
+
+	send := func(h *ShareHandler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/shares/:blog_id/stats", h.GetShareStats)
+
+		req, _ := http.NewRequest("GET", "/shares/"+post.ID.Hex()+"/stats"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Daily series covers every day with platform totals", func(t *testing.T) {
+		mBlog, mAnalytics := new(MockBlogRepo), new(MockAnalyticsRepo)
+		from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+		mBlog.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		mAnalytics.On("BreakdownSeries", mock.Anything, []primitive.ObjectID{post.ID}, analytics.Daily, from, to, "platforms").
+			Return([]analytics.LabelPoint{
+				{Start: from, Counts: map[string]int{"twitter": 2}},
+				{Start: from.Add(48 * time.Hour), Counts: map[string]int{"twitter": 1, "email": 1}},
+			}, nil)
+
+		w := send(NewShareHandler(new(MockShareRepo), mBlog, nil, mAnalytics, ShareSettings{}), "?from=2026-03-01&to=2026-03-03")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Totals  map[string]int         `json:"totals"`
+			AllTime map[string]int         `json:"all_time"`
+			Series  []analytics.LabelPoint `json:"series"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, map[string]int{"twitter": 3, "email": 1}, resp.Totals)
+		assert.Equal(t, post.Shares, resp.AllTime)
+		assert.Len(t, resp.Series, 3)
+		assert.Empty(t, resp.Series[1].Counts)
+	})
+
+	t.Run("Invalid granularity returns 400", func(t *testing.T) {
+		w := send(NewShareHandler(new(MockShareRepo), new(MockBlogRepo), nil, new(MockAnalyticsRepo), ShareSettings{}), "?granularity=week")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNewShareHandler_Salt(t *testing.T) {
+	first := NewShareHandler(nil, nil, nil, nil, ShareSettings{})
+	second := NewShareHandler(nil, nil, nil, nil, ShareSettings{})
+
+	assert.NotEmpty(t, first.settings.Salt, "client hashes are never left unsalted")
+	assert.NotEqual(t, first.settings.Salt, second.settings.Salt)
+	assert.Equal(t, "configured", NewShareHandler(nil, nil, nil, nil, ShareSettings{Salt: "configured"}).settings.Salt)
+}
+
+func TestListShares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	blogID := primitive.NewObjectID()
+
+	list := func(mRepo *MockShareRepo, query string) *httptest.ResponseRecorder {
+		h := NewShareHandler(mRepo, nil, nil, nil, ShareSettings{})
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.GET("/shares/:blog_id", h.ListShares)
+
+		req, _ := http.NewRequest("GET", "/shares/"+blogID.Hex()+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Pages default to 20 shares", func(t *testing.T) {
+		mRepo := new(MockShareRepo)
+		mRepo.On("List", mock.Anything, blogID, int64(20), int64(0)).Return([]*share.Share(nil), nil)
+
+		w := list(mRepo, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+		mRepo.AssertExpectations(t)
+	})
+
+	t.Run("Large pages are capped", func(t *testing.T) {
+		mRepo := new(MockShareRepo)
+		mRepo.On("List", mock.Anything, blogID, int64(maxShareLimit), int64(40)).Return([]*share.Share{}, nil)
+
+		w := list(mRepo, "?limit=100000&skip=40")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mRepo.AssertExpectations(t)
+	})
+}
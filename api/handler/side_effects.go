@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/events"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+)
+
+// The helpers below carry out the side effects of a request: live updates,
+// notifications and analytics. They are best effort. Without the
+// collaborator they do nothing, and a failure is logged rather than
+// failing the request.
+
+// logFailure logs a failed side effect
+func logFailure(what string, err error) {
+	if err != nil {
+		log.Printf("⚠️ Failed to %s: %v", what, err)
+	}
+}
+
+// publishEvent sends a live update to a post's readers
+func publishEvent(broker events.Broker, blogID primitive.ObjectID, eventType string, data interface{}) {
+	if broker == nil {
+		return
+	}
+	err := broker.Publish(context.Background(), events.Event{BlogID: blogID, Type: eventType, Data: data})
+	logFailure("publish "+eventType+" event", err)
+}
+
+// notifyMentions tells the authors mentioned now, but not before, that
+// they were mentioned
+func notifyMentions(mentions service.IMentionService, before, now []primitive.ObjectID, event service.MentionEvent) {
+	if mentions == nil {
+		return
+	}
+
+	fresh := make([]primitive.ObjectID, 0, len(now))
+	for _, id := range now {
+		if !containsID(before, id) {
+			fresh = append(fresh, id)
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+
+	logFailure("send mention notifications", mentions.Notify(context.Background(), fresh, event))
+}
+
+// notifyPostAuthors tells every credited author of a post about activity
+// on it, except those in skip
+func notifyPostAuthors(notifier service.INotificationService, b *blog.Blog, skip []primitive.ObjectID, note notification.Notification) {
+	if notifier == nil {
+		return
+	}
+
+	recipients := make([]primitive.ObjectID, 0, len(b.CoAuthors)+1)
+	if !b.AuthorID.IsZero() {
+		recipients = append(recipients, b.AuthorID)
+	}
+	for _, ca := range b.CoAuthors {
+		if ca.Status == blog.InviteAccepted {
+			recipients = append(recipients, ca.AuthorID)
+		}
+	}
+
+	notes := make([]*notification.Notification, 0, len(recipients))
+	for _, id := range recipients {
+		if containsID(skip, id) {
+			continue
+		}
+		n := note
+		n.RecipientID = id
+		notes = append(notes, &n)
+	}
+	if len(notes) == 0 {
+		return
+	}
+
+	logFailure("send "+string(note.Type)+" notifications", notifier.Notify(context.Background(), notes...))
+}
+
+// recordActivity adds activity to a post's analytics
+func recordActivity(repo repository.IAnalyticsRepository, e analytics.Event) {
+	if repo == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	logFailure("record analytics for post "+e.BlogID.Hex(), repo.Record(context.Background(), e))
+}
@@ -23,36 +23,64 @@ func AuthMiddleware() gin.HandlerFunc {
             return
         }
 
-        // Expect format: "Bearer <token>"
-        parts := strings.Split(authHeader, " ")
-        if len(parts) != 2 || parts[0] != "Bearer" {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
-            c.Abort()
+        if !authenticate(c, authHeader) {
             return
         }
 
-        tokenStr := parts[1]
-
-        // Parse and validate JWT token
-        token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-            if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-                return nil, errors.New("unexpected signing method")
-            }
-            return jwtSecret, nil
-        })
+        c.Next()
+    }
+}
 
-        if err != nil || !token.Valid {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-            c.Abort()
+// OptionalAuthMiddleware identifies the caller when a JWT is supplied but
+// lets anonymous requests through. A token that is present but invalid is
+// still rejected so clients notice expired sessions.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        authHeader := c.GetHeader("Authorization")
+        if authHeader == "" {
+            c.Next()
             return
         }
 
-        // Store claims in context for handlers
-        if claims, ok := token.Claims.(jwt.MapClaims); ok {
-            c.Set("author_id", claims["author_id"])
+        if !authenticate(c, authHeader) {
+            return
         }
 
         c.Next()
     }
 }
 
+// authenticate validates a bearer token and stores its claims in the
+// context. It aborts the request and returns false when the token is bad.
+func authenticate(c *gin.Context, authHeader string) bool {
+    // Expect format: "Bearer <token>"
+    parts := strings.Split(authHeader, " ")
+    if len(parts) != 2 || parts[0] != "Bearer" {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+        c.Abort()
+        return false
+    }
+
+    tokenStr := parts[1]
+
+    // Parse and validate JWT token
+    token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+            return nil, errors.New("unexpected signing method")
+        }
+        return jwtSecret, nil
+    })
+
+    if err != nil || !token.Valid {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+        c.Abort()
+        return false
+    }
+
+    // Store claims in context for handlers
+    if claims, ok := token.Claims.(jwt.MapClaims); ok {
+        c.Set("author_id", claims["author_id"])
+    }
+
+    return true
+}
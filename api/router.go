@@ -11,11 +11,17 @@ import (
 
 	"razorblog-backend/api/handler"
 	"razorblog-backend/api/middleware"
+	"razorblog-backend/configs"
+	"razorblog-backend/internal/events"
+	share "razorblog-backend/internal/models/share"
+	"razorblog-backend/internal/readers"
 	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+	"razorblog-backend/internal/spam"
 )
 
 // RegisterRoutes sets up all routes for the backend
-func RegisterRoutes(r *gin.Engine, client *mongo.Client) {
+func RegisterRoutes(r *gin.Engine, client *mongo.Client, cfg *configs.Config) {
 	log.Println("Registering routes: / , /health, /authors, /blogs")
 
 	// ===== Root Endpoint =====
@@ -67,7 +73,20 @@ r.GET("/health", func(c *gin.Context) {
 		// ===== Author Routes =====
 	db := client.Database("razorblog")
 	authorRepo := repository.NewAuthorRepository(db)
-	authorHandler := handler.NewAuthorHandler(authorRepo)
+	authorDeleter := service.NewAuthorDeletionService(client, db)
+	auditRepo := repository.NewAuditRepository(db)
+	dataExporter, err := service.NewDataExportService(db, cfg.ExportAsyncThreshold, cfg.ExportRetention)
+	if err != nil {
+		log.Fatalf("❌ Failed to set up data exports: %v", err)
+	}
+	ensureIndexes("export job", dataExporter.EnsureIndexes)
+	notificationRepo := repository.NewNotificationRepository(db)
+	ensureIndexes("notification", notificationRepo.EnsureIndexes)
+	notifier := service.NewNotificationService(notificationRepo)
+	mentions := service.NewMentionService(db, authorRepo, notifier)
+	authorHandler := handler.NewAuthorHandler(authorRepo, authorDeleter, auditRepo, dataExporter, mentions)
+	followRepo := repository.NewFollowRepository(db)
+	ensureIndexes("follow", followRepo.EnsureIndexes)
 
 	// Public Author routes
 	r.POST("/authors/register", authorHandler.RegisterAuthor)
@@ -77,22 +96,89 @@ r.GET("/health", func(c *gin.Context) {
 
 	// Protected Author routes
 	authMiddleware := middleware.AuthMiddleware()
+	optionalAuth := middleware.OptionalAuthMiddleware()
 	authorProtected := r.Group("/authors", authMiddleware)
 	{
 		authorProtected.GET("/:id", authorHandler.GetAuthor)
 		authorProtected.PUT("/:id", authorHandler.UpdateAuthor)
 		authorProtected.DELETE("/:id", authorHandler.DeleteAuthor)
+
+		// Personal data export
+		authorProtected.POST("/:id/export", authorHandler.ExportAuthorData)
+		authorProtected.GET("/:id/export/:job_id", authorHandler.GetExportJob)
+		authorProtected.GET("/:id/export/:job_id/download", authorHandler.DownloadExportJob)
+	}
+
+	// ===== Live Events =====
+	var broker events.Broker = events.NewMemoryBroker()
+	if cfg.EventBroker == "mongo" {
+		mongoBroker := events.NewMongoBroker(db)
+		ensureIndexes("event", mongoBroker.EnsureIndexes)
+		// Streams live for the life of the server
+		if err := mongoBroker.Run(context.Background()); err != nil {
+			log.Printf("⚠️ Change streams unavailable, live events stay on this replica: %v", err)
+		}
+		broker = mongoBroker
 	}
 
 	// ===== Blog Routes =====
 	blogRepo := repository.NewBlogRepository(db)
-  blogHandler := handler.NewBlogHandler(blogRepo, authorRepo) // pass authorRepo too
+	ensureIndexes("blog", blogRepo.EnsureIndexes)
+	seriesRepo := repository.NewSeriesRepository(db)
+	ensureIndexes("series", seriesRepo.EnsureIndexes)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	ensureIndexes("bookmark", bookmarkRepo.EnsureIndexes)
+	readerVisitRepo := repository.NewReaderVisitRepository(db)
+	ensureIndexes("reader visit", readerVisitRepo.EnsureIndexes)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	ensureIndexes("analytics", analyticsRepo.EnsureIndexes)
+	// Without a configured salt, anonymous reader hashes could be reversed
+	// by hashing every IPv4 address, so a random salt is stored once and
+	// shared by every replica
+	readerSalt := cfg.ReaderSalt
+	if readerSalt == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		readerSalt, err = repository.NewSecretRepository(db).Get(ctx, "reader_salt")
+		cancel()
+		if err != nil {
+			log.Fatalf("❌ READER_SALT is not set and no stored salt could be loaded: %v", err)
+		}
+		log.Println("READER_SALT is not set; using the salt stored in the database")
+	}
+	readerCounter := readers.NewCounter(readers.Settings{
+		Salt:         readerSalt,
+		Window:       cfg.ReaderWindow,
+		IgnoreAgents: cfg.ReaderIgnoreAgents,
+		Retention:    cfg.ReaderRetention,
+	}, readerVisitRepo)
+  blogHandler := handler.NewBlogHandler(blogRepo, authorRepo, handler.BlogDeps{ // pass authorRepo too
+		Series:    seriesRepo,
+		Mentions:  mentions,
+		Notifier:  notifier,
+		Broker:    broker,
+		Bookmarks: bookmarkRepo,
+		Counter:   readerCounter,
+		Analytics: analyticsRepo,
+	})
 
 
 	// Public Blog routes
 	r.GET("/blogs", blogHandler.ListBlogs)
   r.GET("/blogs/author/:author_id", blogHandler.GetBlogsByAuthor)
-	r.GET("/blogs/:id", blogHandler.GetBlog)
+	r.GET("/blogs/templates", blogHandler.ListTemplates)
+	r.GET("/blogs/templates/:type", blogHandler.GetTemplate)
+	r.GET("/blogs/tdds", blogHandler.ListTDDs)
+	r.GET("/blogs/trending", blogHandler.ListTrending)
+	r.GET("/blogs/popular", blogHandler.ListPopular)
+	r.GET("/blogs/:id", optionalAuth, blogHandler.GetBlog)
+
+	// Similar posts, precomputed by a background job
+	relatedHandler := handler.NewRelatedHandler(repository.NewRelatedRepository(db), blogRepo)
+	r.GET("/blogs/:id/related", relatedHandler.GetRelatedPosts)
+
+	// Live updates for readers on a post page (Server-Sent Events)
+	eventHandler := handler.NewEventHandler(broker, blogRepo)
+	r.GET("/blogs/:id/events", eventHandler.StreamBlogEvents)
 
 	// Protected Blog routes
 	blogProtected := r.Group("/blogs", authMiddleware)
@@ -103,8 +189,12 @@ r.GET("/health", func(c *gin.Context) {
 		// Update blog
 		blogProtected.PUT("/:id", blogHandler.UpdateBlog)
 
-		// Delete blog
+		// Delete blog (moves it to the trash)
 		blogProtected.DELETE("/:id", blogHandler.DeleteBlog)
+
+		// Trash
+		blogProtected.GET("/trash", blogHandler.ListTrash)
+		blogProtected.PATCH("/:id/restore", blogHandler.RestoreBlog)
 	}
 
   
@@ -112,14 +202,103 @@ r.GET("/health", func(c *gin.Context) {
     blogProtected.PATCH("/:id/like", blogHandler.LikeBlog)
     blogProtected.PATCH("/:id/unlike", blogHandler.UnlikeBlog)
 
+    // TDD decision lifecycle (founders only)
+    blogProtected.PATCH("/:id/status", blogHandler.UpdateDecisionStatus)
+
+    // Comment pre-moderation
+    blogProtected.PATCH("/:id/moderation", blogHandler.UpdateCommentModeration)
+
+    // Co-authorship
+    blogProtected.POST("/:id/coauthors", blogHandler.InviteCoAuthor)
+    blogProtected.PUT("/:id/coauthors/order", blogHandler.ReorderCoAuthors)
+    blogProtected.PATCH("/:id/coauthors/accept", blogHandler.AcceptCoAuthorInvite)
+    blogProtected.PATCH("/:id/coauthors/decline", blogHandler.DeclineCoAuthorInvite)
+    blogProtected.DELETE("/:id/coauthors/:author_id", blogHandler.RemoveCoAuthor)
+
+	// ===== Follow Routes =====
+	followHandler := handler.NewFollowHandler(followRepo, authorRepo, blogRepo)
+	authorProtected.POST("/:id/follow", followHandler.FollowAuthor)
+	authorProtected.DELETE("/:id/follow", followHandler.UnfollowAuthor)
+
+	// Posts by followed authors
+	r.GET("/feed", authMiddleware, followHandler.GetFeed)
+
+	// ===== Analytics Routes =====
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsRepo, blogRepo)
+	authorProtected.GET("/:id/analytics", analyticsHandler.GetAuthorAnalytics)
+
+	// ===== Bookmark & Reading List Routes =====
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkRepo, blogRepo)
+	blogProtected.PUT("/:id/bookmark", bookmarkHandler.BookmarkBlog)
+	blogProtected.DELETE("/:id/bookmark", bookmarkHandler.UnbookmarkBlog)
+	r.GET("/bookmarks", authMiddleware, bookmarkHandler.ListBookmarks)
+
+	// Public reading lists; owners also see their private ones
+	r.GET("/reading-lists/author/:author_id", optionalAuth, bookmarkHandler.ListAuthorReadingLists)
+	r.GET("/reading-lists/:id", optionalAuth, bookmarkHandler.GetReadingList)
+
+	readingListProtected := r.Group("/reading-lists", authMiddleware)
+	{
+		readingListProtected.GET("", bookmarkHandler.ListMyReadingLists)
+		readingListProtected.POST("", bookmarkHandler.CreateReadingList)
+		readingListProtected.PUT("/:id", bookmarkHandler.UpdateReadingList)
+		readingListProtected.DELETE("/:id", bookmarkHandler.DeleteReadingList)
+		readingListProtected.POST("/:id/entries", bookmarkHandler.AddReadingListEntry)
+		readingListProtected.PATCH("/:id/entries/:blog_id", bookmarkHandler.UpdateReadingListEntry)
+		readingListProtected.DELETE("/:id/entries/:blog_id", bookmarkHandler.RemoveReadingListEntry)
+		readingListProtected.PUT("/:id/order", bookmarkHandler.ReorderReadingList)
+	}
+
+	// ===== Series Routes =====
+	seriesHandler := handler.NewSeriesHandler(seriesRepo, blogRepo)
+
+	// Public Series routes
+	r.GET("/series/:id", seriesHandler.GetSeries)
+	r.GET("/series/author/:author_id", seriesHandler.ListSeriesByAuthor)
+
+	// Protected Series routes
+	seriesProtected := r.Group("/series", authMiddleware)
+	{
+		seriesProtected.POST("", seriesHandler.CreateSeries)
+		seriesProtected.PUT("/:id", seriesHandler.UpdateSeries)
+		seriesProtected.DELETE("/:id", seriesHandler.DeleteSeries)
+	}
+
 	//  Comment routes
   // ===== Comment Routes =====
 commentRepo := repository.NewCommentRepository(db)
-commentHandler := handler.NewCommentHandler(commentRepo)
-
+spamScorer := spam.NewScorer(spam.Settings{
+	Threshold:       cfg.SpamThreshold,
+	Blocklist:       cfg.SpamBlocklist,
+	MaxLinks:        cfg.SpamMaxLinks,
+	VelocityLimit:   cfg.SpamVelocityLimit,
+	VelocityWindow:  cfg.SpamVelocityWindow,
+	DuplicateWindow: 24 * time.Hour,
+}, commentRepo, repository.NewSpamRepository(db))
+// The classifier starts untrained and picks up its stored training in the background
+go func() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := spamScorer.Load(ctx); err != nil {
+		log.Printf("⚠️ Failed to load spam classifier: %v", err)
+	}
+}()
+commentHandler := handler.NewCommentHandler(commentRepo, handler.CommentDeps{
+	BlogRepo:   blogRepo,
+	AuthorRepo: authorRepo,
+	Scorer:     spamScorer,
+	Mentions:   mentions,
+	Notifier:   notifier,
+	Broker:     broker,
+	Analytics:  analyticsRepo,
+	Settings: handler.CommentSettings{
+		Premoderation: cfg.CommentPremoderation,
+		EditWindow:    cfg.CommentEditWindow,
+	},
+})
 // Public Comment routes
 // @Summary Create a comment
-// @Description Create a new comment for a blog
+// @Description Create a new comment for a blog (optional JWT links it to the author's account)
 // @Tags Comments
 // @Accept json
 // @Produce json
@@ -127,7 +306,7 @@ commentHandler := handler.NewCommentHandler(commentRepo)
 // @Success 201 {object} models.Comment
 // @Failure 400 {object} map[string]string "bad request"
 // @Router /comments [post]
-r.POST("/comments", commentHandler.CreateComment)
+r.POST("/comments", optionalAuth, commentHandler.CreateComment)
 
 // @Summary List comments for a blog
 // @Description List comments with optional pagination
@@ -141,22 +320,131 @@ r.POST("/comments", commentHandler.CreateComment)
 // @Router /comments/{blog_id} [get]
 r.GET("/comments/:blog_id", commentHandler.ListComments)
 
+// @Summary List threaded comments for a blog
+// @Description Paginated top-level comments with nested replies
+// @Tags Comments
+// @Produce json
+// @Param blog_id path string true "Blog ID"
+// @Param limit query int false "Limit"
+// @Param skip query int false "Skip"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "invalid blog id"
+// @Router /comments/{blog_id}/tree [get]
+r.GET("/comments/:blog_id/tree", commentHandler.ListCommentTree)
+
 // @Summary Like a comment
-// @Description Like a comment by username (one like per username)
+// @Description Like a comment by username (one like per username, repeat likes are ignored); a JWT supplies the verified name
 // @Tags Comments
 // @Accept json
 // @Produce json
 // @Param id path string true "Comment ID"
 // @Param body body object{username=string} true "Username liking the comment"
 // @Success 200 {object} models.Comment
-// @Failure 400 {object} map[string]string "bad request or already liked"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /comments/{id}/like [post]
-r.POST("/comments/:id/like", commentHandler.LikeComment)
+r.POST("/comments/:id/like", optionalAuth, commentHandler.LikeComment)
+
+// @Summary Unlike a comment
+// @Description Remove a like by username; unliking twice is a no-op. A JWT supplies the verified name
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param body body object{username=string} true "Username removing the like"
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id}/unlike [post]
+r.POST("/comments/:id/unlike", optionalAuth, commentHandler.UnlikeComment)
+
+// Writer's own comment routes (JWT or the comment's edit token); the
+// post's author can also delete
+r.PUT("/comments/:id", optionalAuth, commentHandler.EditComment)
+r.DELETE("/comments/:id", optionalAuth, commentHandler.DeleteComment)
+
+// Protected Comment routes (post author only)
+commentProtected := r.Group("/comments", authMiddleware)
+{
+	commentProtected.PATCH("/:id/restore", commentHandler.RestoreComment)
+	commentProtected.GET("/:blog_id/trash", commentHandler.ListTrashedComments)
+}
+
+// ===== Reaction Routes =====
+reactionRepo := repository.NewReactionRepository(db)
+ensureIndexes("reaction", reactionRepo.EnsureIndexes)
+reactionHandler := handler.NewReactionHandler(reactionRepo, blogRepo, commentRepo, authorRepo)
+
+// Reactions on blogs and comments; reacting needs a JWT
+r.GET("/reactions/:target/:id", reactionHandler.ListReactions)
+r.PUT("/reactions/:target/:id/:kind", authMiddleware, reactionHandler.AddReaction)
+r.DELETE("/reactions/:target/:id/:kind", authMiddleware, reactionHandler.RemoveReaction)
+
+// ===== Report Routes =====
+reportRepo := repository.NewReportRepository(db)
+ensureIndexes("report", reportRepo.EnsureIndexes)
+reportHandler := handler.NewReportHandler(reportRepo, blogRepo, commentRepo, authorRepo, auditRepo, cfg.ReportHideThreshold)
+
+// Logged-in readers can report blogs, comments and authors
+r.POST("/reports", authMiddleware, reportHandler.CreateReport)
+
+// ===== Moderation Routes =====
+moderationHandler := handler.NewModerationHandler(commentRepo, authorRepo, auditRepo, handler.ModerationDeps{
+	Scorer:    spamScorer,
+	Analytics: analyticsRepo,
+	BlogRepo:  blogRepo,
+	Mentions:  mentions,
+	Notifier:  notifier,
+	Broker:    broker,
+})
+
+// Moderator-only routes
+moderation := r.Group("/moderation", authMiddleware)
+{
+	moderation.GET("/comments", moderationHandler.ListModerationQueue)
+	moderation.POST("/comments/approve", moderationHandler.ApproveComments)
+	moderation.POST("/comments/reject", moderationHandler.RejectComments)
+	moderation.POST("/comments/delete", moderationHandler.DeleteComments)
+	moderation.POST("/comments/spam", moderationHandler.MarkSpam)
+	moderation.POST("/comments/ham", moderationHandler.MarkHam)
+	moderation.GET("/reports", reportHandler.ListReports)
+	moderation.PATCH("/reports/:id/resolve", reportHandler.ResolveReport)
+	moderation.PATCH("/reports/:id/dismiss", reportHandler.DismissReport)
+}
 
 	// Share routes
   // ===== Share Routes =====
 shareRepo := repository.NewShareRepository(db)
-shareHandler := handler.NewShareHandler(shareRepo)
+sharePlatforms := cfg.SharePlatforms
+if len(sharePlatforms) == 0 {
+	sharePlatforms = share.DefaultPlatforms
+}
+ensureIndexes("share", shareRepo.EnsureIndexes)
+// Shares from before counting started are added to the blogs' counts in
+// the background; live shares are only counted from that moment on, so
+// the two cannot overlap
+startCtx, cancelStart := context.WithTimeout(context.Background(), 30*time.Second)
+countingSince, err := shareRepo.StartCounting(startCtx)
+cancelStart()
+if err != nil {
+	log.Printf("⚠️ Share counts will not be backfilled: %v", err)
+} else {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		ran, err := shareRepo.BackfillCounts(ctx, countingSince, sharePlatforms)
+		if err != nil {
+			log.Printf("⚠️ Failed to backfill share counts: %v", err)
+		} else if ran {
+			log.Println("Backfilled share counts")
+		}
+	}()
+}
+shareHandler := handler.NewShareHandler(shareRepo, blogRepo, notifier, analyticsRepo, handler.ShareSettings{
+	Platforms:   sharePlatforms,
+	DedupWindow: cfg.ShareDedupWindow,
+	Salt:        readerSalt,
+})
 
 // Public Share routes
 // @Summary Create a blog share
@@ -168,17 +456,55 @@ shareHandler := handler.NewShareHandler(shareRepo)
 // @Success 201 {object} models.Share
 // @Failure 400 {object} map[string]string "bad request"
 // @Router /shares [post]
-r.POST("/shares", shareHandler.CreateShare)
+r.POST("/shares", optionalAuth, shareHandler.CreateShare)
 
 // @Summary List shares for a blog
-// @Description List all share events for a specific blog
+// @Description List a page of share events for a specific blog, newest first
 // @Tags Shares
 // @Produce json
 // @Param blog_id path string true "Blog ID"
+// @Param limit query int false "Limit, at most 100" default(20)
+// @Param skip query int false "Skip" default(0)
 // @Success 200 {array} models.Share
-// @Failure 400 {object} map[string]string "invalid blog id"
+// @Failure 400 {object} map[string]string "invalid blog id or paging"
 // @Router /shares/{blog_id} [get]
 r.GET("/shares/:blog_id", shareHandler.ListShares)
 
+// @Summary Share counts for a blog over time
+// @Description Shares per platform as a time series, with totals
+// @Tags Shares
+// @Produce json
+// @Param blog_id path string true "Blog ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string "blog not found"
+// @Router /shares/{blog_id}/stats [get]
+r.GET("/shares/:blog_id/stats", shareHandler.GetShareStats)
+
+// ===== Notification Routes =====
+notificationHandler := handler.NewNotificationHandler(notificationRepo, repository.NewDigestRepository(db))
+
+// The logged-in author's notification center
+notifications := r.Group("/notifications", authMiddleware)
+{
+	notifications.GET("", notificationHandler.ListNotifications)
+	notifications.PATCH("/read", notificationHandler.MarkAllNotificationsRead)
+	notifications.PATCH("/:id/read", notificationHandler.MarkNotificationRead)
+	notifications.GET("/preferences", notificationHandler.GetNotificationPreferences)
+	notifications.PUT("/preferences", notificationHandler.UpdateNotificationPreferences)
+	notifications.GET("/digest", notificationHandler.GetDigestSettings)
+	notifications.PUT("/digest", notificationHandler.UpdateDigestSettings)
 }
 
+}
+
+// ensureIndexes creates a collection's indexes in the background so that a
+// slow database does not hold up startup
+func ensureIndexes(name string, fn func(context.Context) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			log.Printf("⚠️ Failed to create %s indexes: %v", name, err)
+		}
+	}()
+}
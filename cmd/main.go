@@ -1,10 +1,12 @@
 package main
 
 import (
+    "context"
     "log"
     "razorblog-backend/api"
     "razorblog-backend/configs"
     "razorblog-backend/internal/database"
+    "razorblog-backend/internal/jobs"
     "time"
 
     "github.com/gin-contrib/cors"
@@ -34,6 +36,11 @@ func main() {
         }
     }()
 
+    // Start background jobs (trash purge)
+    jobCtx, stopJobs := context.WithCancel(context.Background())
+    defer stopJobs()
+    jobs.Start(jobCtx, client.Database("razorblog"), cfg)
+
     // Initialize Gin router
     r := gin.Default()
 
@@ -48,7 +55,7 @@ func main() {
     }))
 
     // Register main API routes (Authors, Blogs, Comments, Shares)
-    api.RegisterRoutes(r, client)
+    api.RegisterRoutes(r, client, cfg)
 
     // Swagger UI route
     r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
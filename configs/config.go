@@ -3,6 +3,9 @@ package configs
 import (
     "log"
     "os"
+    "strconv"
+    "strings"
+    "time"
 
     "github.com/joho/godotenv"
 )
@@ -11,6 +14,81 @@ type Config struct {
     Port     string
     MongoURI string
     JWTSecret string
+
+    // How long trashed posts and comments are kept before being purged
+    TrashRetention time.Duration
+
+    // Accounts with more posts and audit events than this are exported in the background
+    ExportAsyncThreshold int64
+
+    // How long background export archives can be downloaded before they are deleted
+    ExportRetention time.Duration
+
+    // Hold every new comment for moderation, not just those on pre-moderated posts
+    CommentPremoderation bool
+
+    // How long after posting a comment its writer may still edit it
+    CommentEditWindow time.Duration
+
+    // Comments scoring at or above this spam score are held for moderation
+    SpamThreshold float64
+
+    // Words and phrases that mark a comment as spam; nil uses the built-in list
+    SpamBlocklist []string
+
+    // Links a comment may contain before it starts to look like spam
+    SpamMaxLinks int
+
+    // Comments allowed per IP address or username within SpamVelocityWindow
+    SpamVelocityLimit  int
+    SpamVelocityWindow time.Duration
+
+    // Blogs and comments are hidden once this many readers report them; 0 disables auto-hiding
+    ReportHideThreshold int
+
+    // Outgoing mail for activity digests; digests are off while SMTPHost is empty
+    SMTPHost     string
+    SMTPPort     int
+    SMTPUsername string
+    SMTPPassword string
+    MailFrom     string
+
+    // Public address of the site, used to link to posts from emails
+    SiteURL string
+
+    // "mongo" shares live post events between replicas through change
+    // streams; anything else keeps them in-process
+    EventBroker string
+
+    // Secret mixed into the hashes that identify anonymous readers and
+    // share clients; when empty a random salt is generated once and kept
+    // in the database
+    ReaderSalt string
+
+    // Each reader adds to a post's unique readers once per window
+    ReaderWindow time.Duration
+
+    // User agent fragments whose views are never counted, on top of the
+    // built-in bot list; add our server-side renderer's agent here
+    ReaderIgnoreAgents []string
+
+    // How long reader visits are kept to work out which posts are read together
+    ReaderRetention time.Duration
+
+    // How often trending scores are recomputed (0 or less turns this off),
+    // and how quickly engagement stops counting towards them
+    TrendingInterval time.Duration
+    TrendingHalfLife time.Duration
+
+    // How often related posts are recomputed; 0 or less turns this off
+    RelatedInterval time.Duration
+
+    // Platforms a share may name; nil allows the built-in list
+    SharePlatforms []string
+
+    // Repeat shares of a post to a platform by one client within this
+    // window are not counted; 0 counts every share
+    ShareDedupWindow time.Duration
 }
 
 func LoadConfig() *Config {
@@ -22,6 +100,87 @@ func LoadConfig() *Config {
         Port:      os.Getenv("PORT"),
         MongoURI:  os.Getenv("MONGO_URI"),
         JWTSecret: os.Getenv("JWT_SECRET"),
+
+        TrashRetention:       time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+        ExportAsyncThreshold: int64(getEnvInt("EXPORT_ASYNC_THRESHOLD", 200)),
+        ExportRetention:      time.Duration(getEnvInt("EXPORT_RETENTION_HOURS", 24)) * time.Hour,
+        CommentPremoderation: os.Getenv("COMMENT_PREMODERATION") == "true",
+        CommentEditWindow:    time.Duration(getEnvInt("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute,
+
+        SpamThreshold:      getEnvFloat("SPAM_THRESHOLD", 0.7),
+        SpamBlocklist:      getEnvList("SPAM_BLOCKLIST"),
+        SpamMaxLinks:       getEnvInt("SPAM_MAX_LINKS", 2),
+        SpamVelocityLimit:  getEnvInt("SPAM_VELOCITY_LIMIT", 5),
+        SpamVelocityWindow: time.Duration(getEnvInt("SPAM_VELOCITY_WINDOW_MINUTES", 10)) * time.Minute,
+
+        ReportHideThreshold: getEnvInt("REPORT_HIDE_THRESHOLD", 3),
+
+        SMTPHost:     os.Getenv("SMTP_HOST"),
+        SMTPPort:     getEnvInt("SMTP_PORT", 587),
+        SMTPUsername: os.Getenv("SMTP_USERNAME"),
+        SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+        MailFrom:     os.Getenv("MAIL_FROM"),
+        SiteURL:      os.Getenv("SITE_URL"),
+
+        EventBroker: os.Getenv("EVENT_BROKER"),
+
+        ReaderSalt:         os.Getenv("READER_SALT"),
+        ReaderWindow:       time.Duration(getEnvInt("READER_WINDOW_HOURS", 24)) * time.Hour,
+        ReaderIgnoreAgents: getEnvList("READER_IGNORE_AGENTS"),
+        ReaderRetention:    time.Duration(getEnvInt("READER_RETENTION_DAYS", 30)) * 24 * time.Hour,
+
+        TrendingInterval: time.Duration(getEnvInt("TRENDING_INTERVAL_MINUTES", 15)) * time.Minute,
+        TrendingHalfLife: time.Duration(getEnvInt("TRENDING_HALF_LIFE_HOURS", 24)) * time.Hour,
+
+        RelatedInterval: time.Duration(getEnvInt("RELATED_INTERVAL_HOURS", 6)) * time.Hour,
+
+        SharePlatforms:   getEnvList("SHARE_PLATFORMS"),
+        ShareDedupWindow: time.Duration(getEnvInt("SHARE_DEDUP_SECONDS", 60)) * time.Second,
+    }
+}
+
+// getEnvInt reads an integer environment variable, falling back to def
+// when it is unset or not a number
+func getEnvInt(key string, def int) int {
+    v := os.Getenv(key)
+    if v == "" {
+        return def
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil {
+        log.Printf("Invalid %s=%q, using default %d", key, v, def)
+        return def
+    }
+    return n
+}
+
+// getEnvFloat reads a decimal environment variable, falling back to def
+// when it is unset or not a number
+func getEnvFloat(key string, def float64) float64 {
+    v := os.Getenv(key)
+    if v == "" {
+        return def
+    }
+    f, err := strconv.ParseFloat(v, 64)
+    if err != nil {
+        log.Printf("Invalid %s=%q, using default %g", key, v, def)
+        return def
     }
+    return f
 }
 
+// getEnvList reads a comma-separated environment variable, returning nil
+// when it is unset
+func getEnvList(key string) []string {
+    v := os.Getenv(key)
+    if v == "" {
+        return nil
+    }
+    var items []string
+    for _, item := range strings.Split(v, ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
+}
@@ -0,0 +1,192 @@
+// Package digest builds and sends the daily or weekly activity emails that
+// summarise what happened on an author's posts.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/mail"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/digest"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl"))
+)
+
+// Posts lists the posts an author is credited on
+type Posts interface {
+	ListByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error)
+}
+
+// Counter counts per-blog activity, such as comments or shares, in [from, to)
+type Counter interface {
+	CountByBlog(ctx context.Context, blogIDs []primitive.ObjectID, from, to time.Time) (map[primitive.ObjectID]int, error)
+}
+
+// Activity is what happened on one post, or across all of them
+type Activity struct {
+	BlogID   primitive.ObjectID
+	Title    string
+	URL      string
+	Comments int
+	Likes    int // Likes gained
+	Readers  int // Readers gained
+	Shares   int
+}
+
+// Empty reports whether nothing happened
+func (a Activity) Empty() bool {
+	return a.Comments == 0 && a.Likes == 0 && a.Readers == 0 && a.Shares == 0
+}
+
+// Digest summarises an author's activity over one period
+type Digest struct {
+	AuthorName string
+	Frequency  digest.Frequency
+	From, To   time.Time
+	Posts      []Activity // Posts with activity, busiest first
+	Total      Activity
+
+	// Post totals at To, the baseline for the next digest
+	Baseline map[string]digest.Counts
+}
+
+// Empty reports whether there is nothing worth emailing
+func (d *Digest) Empty() bool {
+	return d.Total.Empty()
+}
+
+// Builder aggregates activity from posts, comments and shares
+type Builder struct {
+	posts    Posts
+	comments Counter
+	shares   Counter
+	siteURL  string
+}
+
+// NewBuilder creates a Builder. siteURL, if set, is used to link to posts.
+func NewBuilder(posts Posts, comments, shares Counter, siteURL string) *Builder {
+	return &Builder{posts: posts, comments: comments, shares: shares, siteURL: strings.TrimRight(siteURL, "/")}
+}
+
+// Build summarises the activity on an author's posts between from and to.
+// Likes and readers are measured against baseline, the totals when the
+// previous digest was sent; posts missing from it are new and count in full.
+func (b *Builder) Build(ctx context.Context, a *author.Author, frequency digest.Frequency, from, to time.Time, baseline map[string]digest.Counts) (*Digest, error) {
+	posts, err := b.posts.ListByAuthor(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Digest{
+		AuthorName: a.Name,
+		Frequency:  frequency,
+		From:       from,
+		To:         to,
+		Baseline:   make(map[string]digest.Counts, len(posts)),
+	}
+	if len(posts) == 0 {
+		return d, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	comments, err := b.comments.CountByBlog(ctx, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := b.shares.CountByBlog(ctx, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range posts {
+		now := digest.Counts{Likes: len(p.Likes), Readers: p.Readers}
+		before := baseline[p.ID.Hex()]
+		d.Baseline[p.ID.Hex()] = now
+
+		activity := Activity{
+			BlogID:   p.ID,
+			Title:    p.Title,
+			Comments: comments[p.ID],
+			Likes:    gain(before.Likes, now.Likes),
+			Readers:  gain(before.Readers, now.Readers),
+			Shares:   shares[p.ID],
+		}
+		if activity.Empty() {
+			continue
+		}
+		if b.siteURL != "" {
+			activity.URL = b.siteURL + "/blogs/" + p.ID.Hex()
+		}
+
+		d.Posts = append(d.Posts, activity)
+		d.Total.Comments += activity.Comments
+		d.Total.Likes += activity.Likes
+		d.Total.Readers += activity.Readers
+		d.Total.Shares += activity.Shares
+	}
+
+	sort.SliceStable(d.Posts, func(i, j int) bool {
+		return score(d.Posts[i]) > score(d.Posts[j])
+	})
+	return d, nil
+}
+
+// gain is how much a running total grew; unlikes do not count against it
+func gain(before, now int) int {
+	if now < before {
+		return 0
+	}
+	return now - before
+}
+
+// score orders posts in a digest; direct engagement outweighs readers
+func score(a Activity) int {
+	return a.Comments*3 + a.Shares*3 + a.Likes*2 + a.Readers
+}
+
+// Render turns a digest into an email for the author
+func Render(d *Digest, a *author.Author) (mail.Message, error) {
+	period := "weekly"
+	if d.Frequency == digest.FrequencyDaily {
+		period = "daily"
+	}
+	data := struct {
+		*Digest
+		Period string
+	}{d, period}
+
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return mail.Message{}, err
+	}
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return mail.Message{}, err
+	}
+
+	return mail.Message{
+		To:      a.Email,
+		ToName:  a.Name,
+		Subject: "Your " + period + " RazorBlog activity",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,207 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/mail"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/digest"
+)
+
+type fakePosts []*blog.Blog
+
+func (f fakePosts) ListByAuthor(context.Context, primitive.ObjectID) ([]*blog.Blog, error) {
+	return f, nil
+}
+
+type fakeCounter map[primitive.ObjectID]int
+
+func (f fakeCounter) CountByBlog(context.Context, []primitive.ObjectID, time.Time, time.Time) (map[primitive.ObjectID]int, error) {
+	return f, nil
+}
+
+type fakeSubs struct {
+	sub      digest.Subscription
+	taken    bool // Another server already claimed the period
+	marked   *time.Time
+	baseline map[string]digest.Counts
+	released bool
+}
+
+func (f *fakeSubs) Get(context.Context, primitive.ObjectID) (*digest.Subscription, error) {
+	sub := f.sub
+	return &sub, nil
+}
+
+func (f *fakeSubs) Claim(_ context.Context, _ primitive.ObjectID, sentAt time.Time, baseline map[string]digest.Counts) (bool, error) {
+	if f.taken {
+		return false, nil
+	}
+	f.marked, f.baseline = &sentAt, baseline
+	return true, nil
+}
+
+func (f *fakeSubs) Release(context.Context, *digest.Subscription, time.Time) error {
+	f.released = true
+	return nil
+}
+
+// recorder is a mailer that keeps what it was asked to send
+type recorder struct {
+	sent []mail.Message
+	err  error
+}
+
+func (r *recorder) Send(_ context.Context, msg mail.Message) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+var (
+	quiet = &blog.Blog{ID: primitive.NewObjectID(), Title: "Quiet post", Readers: 10}
+	busy  = &blog.Blog{ID: primitive.NewObjectID(), Title: "Busy <post>", Readers: 50, Likes: make([]primitive.ObjectID, 4)}
+	ada   = &author.Author{ID: primitive.NewObjectID(), Name: "Ada", Email: "ada@example.com"}
+)
+
+func newTestBuilder(comments fakeCounter) *Builder {
+	return NewBuilder(fakePosts{quiet, busy}, comments, fakeCounter{busy.ID: 1}, "https://example.com/")
+}
+
+func TestBuild(t *testing.T) {
+	baseline := map[string]digest.Counts{
+		quiet.ID.Hex(): {Readers: 10},
+		busy.ID.Hex():  {Readers: 20, Likes: 6},
+	}
+	now := time.Now()
+
+	d, err := newTestBuilder(fakeCounter{busy.ID: 2}).Build(context.Background(), ada, digest.FrequencyDaily, now.Add(-24*time.Hour), now, baseline)
+
+	assert.NoError(t, err)
+	assert.Len(t, d.Posts, 1, "posts without activity are left out")
+	assert.Equal(t, Activity{
+		BlogID:   busy.ID,
+		Title:    busy.Title,
+		URL:      "https://example.com/blogs/" + busy.ID.Hex(),
+		Comments: 2,
+		Likes:    0, // Unlikes do not go negative
+		Readers:  30,
+		Shares:   1,
+	}, d.Posts[0])
+	assert.Equal(t, digest.Counts{Readers: 50, Likes: 4}, d.Baseline[busy.ID.Hex()])
+}
+
+func TestRender(t *testing.T) {
+	now := time.Now()
+	d, _ := newTestBuilder(fakeCounter{}).Build(context.Background(), ada, digest.FrequencyWeekly, now.Add(-7*24*time.Hour), now, nil)
+
+	msg, err := Render(d, ada)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ada@example.com", msg.To)
+	assert.Equal(t, "Your weekly RazorBlog activity", msg.Subject)
+	assert.Contains(t, msg.Text, "Busy <post>")
+	assert.Contains(t, msg.HTML, "Busy &lt;post&gt;", "titles are escaped in HTML")
+	assert.True(t, strings.Contains(msg.HTML, `href="https://example.com/blogs/`+busy.ID.Hex()+`"`))
+}
+
+func TestSendDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	lastWeek := now.Truncate(time.Hour).Add(-7 * 24 * time.Hour)
+	yesterday := now.Truncate(time.Hour).Add(-24 * time.Hour)
+
+	t.Run("first check only starts the schedule", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyWeekly}}
+		mailer := &recorder{}
+
+		sent, err := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.NoError(t, err)
+		assert.False(t, sent)
+		assert.Empty(t, mailer.sent)
+		assert.Equal(t, now.Truncate(time.Hour), *subs.marked)
+	})
+
+	t.Run("due digest is emailed", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyWeekly, LastSentAt: &lastWeek}}
+		mailer := &recorder{}
+
+		sent, err := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.NoError(t, err)
+		assert.True(t, sent)
+		assert.Len(t, mailer.sent, 1)
+		assert.NotNil(t, subs.marked)
+	})
+
+	t.Run("weekly digest is not due after a day", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyWeekly, LastSentAt: &yesterday}}
+		mailer := &recorder{}
+
+		sent, _ := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.False(t, sent)
+		assert.Nil(t, subs.marked)
+	})
+
+	t.Run("opted out authors get nothing", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyOff, LastSentAt: &lastWeek}}
+		mailer := &recorder{}
+
+		sent, _ := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.False(t, sent)
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("quiet period advances without an email", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{
+			Frequency:  digest.FrequencyDaily,
+			LastSentAt: &yesterday,
+			Baseline: map[string]digest.Counts{
+				quiet.ID.Hex(): {Readers: 10},
+				busy.ID.Hex():  {Readers: 50, Likes: 4},
+			},
+		}}
+		mailer := &recorder{}
+		builder := NewBuilder(fakePosts{quiet, busy}, fakeCounter{}, fakeCounter{}, "")
+
+		sent, err := NewSender(subs, builder, mailer).SendDue(context.Background(), ada, now)
+
+		assert.NoError(t, err)
+		assert.False(t, sent)
+		assert.Empty(t, mailer.sent)
+		assert.NotNil(t, subs.marked)
+	})
+	t.Run("period claimed by another server is not sent again", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyWeekly, LastSentAt: &lastWeek}, taken: true}
+		mailer := &recorder{}
+
+		sent, err := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.NoError(t, err)
+		assert.False(t, sent)
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("failed email hands the period back", func(t *testing.T) {
+		subs := &fakeSubs{sub: digest.Subscription{Frequency: digest.FrequencyWeekly, LastSentAt: &lastWeek}}
+		mailer := &recorder{err: errors.New("smtp down")}
+
+		sent, err := NewSender(subs, newTestBuilder(fakeCounter{}), mailer).SendDue(context.Background(), ada, now)
+
+		assert.Error(t, err)
+		assert.False(t, sent)
+		assert.True(t, subs.released)
+	})
+}
@@ -0,0 +1,89 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/mail"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/digest"
+)
+
+// Subscriptions stores each author's digest schedule
+type Subscriptions interface {
+	Get(ctx context.Context, authorID primitive.ObjectID) (*digest.Subscription, error)
+	// Claim records that the digest for the period ending at sentAt is
+	// being sent, unless another server already claimed it, and stores the
+	// post totals at that moment
+	Claim(ctx context.Context, authorID primitive.ObjectID, sentAt time.Time, baseline map[string]digest.Counts) (bool, error)
+	// Release hands a claimed period back after its digest failed to send
+	Release(ctx context.Context, sub *digest.Subscription, sentAt time.Time) error
+}
+
+// Sender emails authors whose digest is due
+type Sender struct {
+	subs    Subscriptions
+	builder *Builder
+	mailer  mail.Mailer
+}
+
+func NewSender(subs Subscriptions, builder *Builder, mailer mail.Mailer) *Sender {
+	return &Sender{subs: subs, builder: builder, mailer: mailer}
+}
+
+// SendDue emails an author their digest if one is due at now and reports
+// whether an email went out. An author's first check only records where
+// their first digest starts. Periods without activity advance the schedule
+// without an email. A period another server already claimed is skipped.
+func (s *Sender) SendDue(ctx context.Context, a *author.Author, now time.Time) (bool, error) {
+	sub, err := s.subs.Get(ctx, a.ID)
+	if err != nil {
+		return false, err
+	}
+
+	period := sub.Frequency.Period()
+	if period == 0 || a.Email == "" {
+		return false, nil
+	}
+
+	// Periods end on the hour so an hourly job does not drift
+	end := now.Truncate(time.Hour)
+	if sub.LastSentAt != nil && end.Sub(*sub.LastSentAt) < period {
+		return false, nil
+	}
+
+	from := end.Add(-period)
+	if sub.LastSentAt != nil {
+		from = *sub.LastSentAt
+	}
+	d, err := s.builder.Build(ctx, a, sub.Frequency, from, end, sub.Baseline)
+	if err != nil {
+		return false, err
+	}
+
+	// Every replica runs this job, so the period is claimed before the
+	// email goes out and only the replica that claimed it sends
+	claimed, err := s.subs.Claim(ctx, a.ID, end, d.Baseline)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if sub.LastSentAt == nil || d.Empty() {
+		return false, nil
+	}
+
+	msg, err := Render(d, a)
+	if err == nil {
+		err = s.mailer.Send(ctx, msg)
+	}
+	if err != nil {
+		// Leave the period for the next run to retry
+		if releaseErr := s.subs.Release(ctx, sub, end); releaseErr != nil {
+			return false, errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
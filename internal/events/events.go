@@ -0,0 +1,86 @@
+// Package events fans live updates about a post out to the readers
+// watching it. Handlers publish to a Broker; the SSE endpoint subscribes.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event types sent to readers
+const (
+	TypeComment      = "comment"       // A new comment became visible
+	TypeLikes        = "likes"         // The post's like count changed
+	TypeCommentLikes = "comment_likes" // A comment's like count changed
+	TypeReaders      = "readers"       // The post's reader count changed
+)
+
+// Event is an update about one post
+type Event struct {
+	BlogID primitive.ObjectID
+	Type   string
+	Data   interface{} // Sent to readers as JSON
+}
+
+// Broker delivers events to the subscribers of a post
+type Broker interface {
+	Publish(ctx context.Context, e Event) error
+
+	// Subscribe returns the events for a post until cancel is called. Slow
+	// subscribers miss events rather than holding up publishers.
+	Subscribe(blogID primitive.ObjectID) (events <-chan Event, cancel func())
+}
+
+// subscriberBuffer is how many events a subscriber can fall behind by
+const subscriberBuffer = 16
+
+// MemoryBroker delivers events within this process
+type MemoryBroker struct {
+	mu   sync.RWMutex
+	subs map[primitive.ObjectID]map[chan Event]struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subs: map[primitive.ObjectID]map[chan Event]struct{}{}}
+}
+
+// Publish sends e to every current subscriber of its post
+func (b *MemoryBroker) Publish(_ context.Context, e Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subs[e.BlogID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber for a post
+func (b *MemoryBroker) Subscribe(blogID primitive.ObjectID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[blogID] == nil {
+		b.subs[blogID] = map[chan Event]struct{}{}
+	}
+	b.subs[blogID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs[blogID], ch)
+			if len(b.subs[blogID]) == 0 {
+				delete(b.subs, blogID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
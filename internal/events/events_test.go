@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	ctx := context.Background()
+	watched, other := primitive.NewObjectID(), primitive.NewObjectID()
+
+	t.Run("delivers only the subscribed post's events", func(t *testing.T) {
+		b := NewMemoryBroker()
+		ch, cancel := b.Subscribe(watched)
+		defer cancel()
+
+		_ = b.Publish(ctx, Event{BlogID: other, Type: TypeLikes})
+		_ = b.Publish(ctx, Event{BlogID: watched, Type: TypeComment})
+
+		assert.Equal(t, TypeComment, (<-ch).Type)
+		assert.Empty(t, ch)
+	})
+
+	t.Run("cancel closes the subscription", func(t *testing.T) {
+		b := NewMemoryBroker()
+		ch, cancel := b.Subscribe(watched)
+		cancel()
+		cancel()
+
+		_, open := <-ch
+		assert.False(t, open)
+		assert.NoError(t, b.Publish(ctx, Event{BlogID: watched, Type: TypeLikes}))
+	})
+
+	t.Run("slow subscribers miss events instead of blocking", func(t *testing.T) {
+		b := NewMemoryBroker()
+		ch, cancel := b.Subscribe(watched)
+		defer cancel()
+
+		for i := 0; i < subscriberBuffer+5; i++ {
+			_ = b.Publish(ctx, Event{BlogID: watched, Type: TypeReaders})
+		}
+
+		assert.Len(t, ch, subscriberBuffer)
+	})
+}
+
+func TestMongoBrokerReconnect(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	token, _ := bson.Marshal(bson.M{"_data": "82"})
+
+	// resumedAfter reports whether each attempt to open the stream resumed
+	resumedAfter := func(mt *mtest.T) []bool {
+		var resumed []bool
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			if e.CommandName != "aggregate" {
+				continue
+			}
+			stage := e.Command.Lookup("pipeline").Array().Index(0).Value().Document()
+			_, err := stage.LookupErr("$changeStream", "resumeAfter")
+			resumed = append(resumed, err == nil)
+		}
+		return resumed
+	}
+	opened := func() bson.D {
+		return mtest.CreateCursorResponse(0, "test.blog_events", mtest.FirstBatch)
+	}
+
+	mt.Run("retries from where the stream left off", func(mt *mtest.T) {
+		b := NewMongoBroker(mt.DB)
+		b.retryDelay = time.Millisecond
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 6, Message: "host unreachable"}), opened())
+
+		stream := b.reconnect(context.Background(), token)
+
+		if assert.NotNil(mt, stream) {
+			stream.Close(context.Background())
+		}
+		assert.Equal(mt, []bool{true, true}, resumedAfter(mt))
+	})
+
+	mt.Run("starts from now once the missed events are gone", func(mt *mtest.T) {
+		b := NewMongoBroker(mt.DB)
+		b.retryDelay = time.Millisecond
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 286, Message: "history lost"}), opened())
+
+		stream := b.reconnect(context.Background(), token)
+
+		if assert.NotNil(mt, stream) {
+			stream.Close(context.Background())
+		}
+		assert.Equal(mt, []bool{true, false}, resumedAfter(mt))
+	})
+
+	mt.Run("gives up when cancelled", func(mt *mtest.T) {
+		b := NewMongoBroker(mt.DB)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.Nil(mt, b.reconnect(ctx, token))
+	})
+}
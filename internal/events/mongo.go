@@ -0,0 +1,172 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// eventTTL is how long published events are kept in Mongo. Replicas only
+// need them long enough for their change streams to pick them up.
+const eventTTL = 10 * time.Minute
+
+// maxRetryDelay caps the wait between attempts to reopen a change stream
+const maxRetryDelay = time.Minute
+
+// changeStreamHistoryLost is the server error for a resume token that has
+// fallen off the oplog
+const changeStreamHistoryLost = 286
+
+// MongoBroker shares events between replicas. Publish stores the event in
+// the blog_events collection and every replica's change stream delivers it
+// to its own subscribers. Change streams need a replica set; on a
+// standalone server Run fails and the broker keeps events in-process.
+type MongoBroker struct {
+	collection *mongo.Collection
+	local      *MemoryBroker
+	watching   atomic.Bool
+	retryDelay time.Duration // First wait before reopening a change stream
+}
+
+func NewMongoBroker(db *mongo.Database) *MongoBroker {
+	return &MongoBroker{
+		collection: db.Collection("blog_events"),
+		local:      NewMemoryBroker(),
+		retryDelay: time.Second,
+	}
+}
+
+// storedEvent is an event as saved in Mongo
+type storedEvent struct {
+	BlogID    primitive.ObjectID `bson:"blog_id"`
+	Type      string             `bson:"type"`
+	Data      string             `bson:"data"` // JSON
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// EnsureIndexes expires old events
+func (b *MongoBroker) EnsureIndexes(ctx context.Context) error {
+	_, err := b.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(eventTTL.Seconds())),
+	})
+	return err
+}
+
+// Publish stores the event for every replica, or delivers it locally when
+// change streams are unavailable
+func (b *MongoBroker) Publish(ctx context.Context, e Event) error {
+	if !b.watching.Load() {
+		return b.local.Publish(ctx, e)
+	}
+
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	_, err = b.collection.InsertOne(ctx, storedEvent{
+		BlogID:    e.BlogID,
+		Type:      e.Type,
+		Data:      string(data),
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// Subscribe registers a subscriber on this replica
+func (b *MongoBroker) Subscribe(blogID primitive.ObjectID) (<-chan Event, func()) {
+	return b.local.Subscribe(blogID)
+}
+
+// Run watches for published events until ctx is cancelled. It returns an
+// error straight away if change streams are unavailable. Once running, an
+// interrupted stream is reopened with backoff from where it left off, and
+// events published in the meantime are delivered when it resumes.
+func (b *MongoBroker) Run(ctx context.Context) error {
+	stream, err := b.watch(ctx, nil)
+	if err != nil {
+		return err
+	}
+	b.watching.Store(true)
+
+	go func() {
+		defer b.watching.Store(false)
+		for {
+			b.forward(ctx, stream)
+			resumeAfter, streamErr := stream.ResumeToken(), stream.Err()
+			stream.Close(context.Background())
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Printf("⚠️ Live event stream interrupted, reconnecting: %v", streamErr)
+			if stream = b.reconnect(ctx, resumeAfter); stream == nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// reconnect reopens the change stream after resumeAfter, doubling the delay
+// between attempts up to maxRetryDelay. It returns nil once ctx is
+// cancelled. If the events after resumeAfter are gone from the oplog, the
+// stream starts from now instead.
+func (b *MongoBroker) reconnect(ctx context.Context, resumeAfter bson.Raw) *mongo.ChangeStream {
+	delay := b.retryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		stream, err := b.watch(ctx, resumeAfter)
+		if err == nil {
+			return stream
+		}
+		var serverErr mongo.ServerError
+		if resumeAfter != nil && errors.As(err, &serverErr) && serverErr.HasErrorCode(changeStreamHistoryLost) {
+			log.Printf("⚠️ Live events published while disconnected are lost: %v", err)
+			resumeAfter = nil
+			continue
+		}
+		log.Printf("⚠️ Failed to reconnect the live event stream: %v", err)
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// watch opens a change stream of published events, after resumeAfter if set
+func (b *MongoBroker) watch(ctx context.Context, resumeAfter bson.Raw) (*mongo.ChangeStream, error) {
+	opts := options.ChangeStream()
+	if resumeAfter != nil {
+		opts.SetResumeAfter(resumeAfter)
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	return b.collection.Watch(ctx, pipeline, opts)
+}
+
+// forward hands events from the change stream to local subscribers
+func (b *MongoBroker) forward(ctx context.Context, stream *mongo.ChangeStream) {
+	for stream.Next(ctx) {
+		var change struct {
+			Doc storedEvent `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			continue
+		}
+		_ = b.local.Publish(ctx, Event{
+			BlogID: change.Doc.BlogID,
+			Type:   change.Doc.Type,
+			Data:   json.RawMessage(change.Doc.Data),
+		})
+	}
+}
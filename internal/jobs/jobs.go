@@ -0,0 +1,207 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"razorblog-backend/configs"
+	"razorblog-backend/internal/digest"
+	"razorblog-backend/internal/mail"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/related"
+	"razorblog-backend/internal/recommend"
+	"razorblog-backend/internal/repository"
+	"razorblog-backend/internal/service"
+	"razorblog-backend/internal/trending"
+)
+
+// Start launches the background jobs. They stop when ctx is cancelled.
+func Start(ctx context.Context, db *mongo.Database, cfg *configs.Config) {
+	blogRepo := repository.NewBlogRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+
+	go Every(ctx, time.Hour, func(ctx context.Context) {
+		PurgeTrash(ctx, blogRepo, commentRepo, cfg.TrashRetention)
+	})
+
+	// A ticker cannot run at a zero or negative interval
+	if cfg.TrendingInterval > 0 {
+		ranker := trending.NewRanker(trending.Settings{HalfLife: cfg.TrendingHalfLife}, repository.NewAnalyticsRepository(db), blogRepo)
+		go Every(ctx, cfg.TrendingInterval, func(ctx context.Context) {
+			RankTrending(ctx, ranker)
+		})
+	} else {
+		log.Println("Trending scores disabled: TRENDING_INTERVAL_MINUTES is not positive")
+	}
+
+	if cfg.RelatedInterval > 0 {
+		visitRepo := repository.NewReaderVisitRepository(db)
+		relatedRepo := repository.NewRelatedRepository(db)
+		go Every(ctx, cfg.RelatedInterval, func(ctx context.Context) {
+			RelatePosts(ctx, blogRepo, visitRepo, relatedRepo, cfg.ReaderRetention)
+		})
+	} else {
+		log.Println("Related posts disabled: RELATED_INTERVAL_HOURS is not positive")
+	}
+
+	exporter, err := service.NewDataExportService(db, cfg.ExportAsyncThreshold, cfg.ExportRetention)
+	if err != nil {
+		log.Printf("⚠️ Expired data exports will not be purged: %v", err)
+	} else {
+		go Every(ctx, time.Hour, func(ctx context.Context) {
+			PurgeExports(ctx, exporter)
+		})
+	}
+
+	if cfg.SMTPHost == "" {
+		log.Println("Email digests disabled: SMTP_HOST is not set")
+		return
+	}
+	mailer := mail.NewSMTPMailer(mail.SMTPSettings{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	})
+	builder := digest.NewBuilder(blogRepo, commentRepo, repository.NewShareRepository(db), cfg.SiteURL)
+	sender := digest.NewSender(repository.NewDigestRepository(db), builder, mailer)
+	authorRepo := repository.NewAuthorRepository(db)
+
+	go Every(ctx, time.Hour, func(ctx context.Context) {
+		SendDigests(ctx, authorRepo, sender)
+	})
+}
+
+// Every runs fn immediately and then once per interval until ctx is cancelled
+func Every(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeTrash hard-deletes posts and comments that have been in the trash
+// longer than the retention period. Purged posts take their comments,
+// shares and other dependent data with them.
+func PurgeTrash(ctx context.Context, blogRepo *repository.BlogRepository, commentRepo *repository.CommentRepository, retention time.Duration) {
+	cutoff := time.Now().Add(-retention)
+
+	result, err := blogRepo.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		log.Printf("⚠️ Trash purge failed for blogs: %v", err)
+		return
+	}
+
+	comments, err := commentRepo.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		log.Printf("⚠️ Trash purge failed for comments: %v", err)
+		return
+	}
+
+	if result.Blogs > 0 || comments > 0 {
+		log.Printf("🗑️ Purged %d blogs (%d comments, %d shares, %d reactions, %d reports, %d bookmarks, %d notifications) and %d trashed comments",
+			result.Blogs, result.Comments, result.Shares, result.Reactions, result.Reports, result.Bookmarks, result.Notifications, comments)
+	}
+}
+
+// PurgeExports deletes background data exports, and their archives, once
+// they expire
+func PurgeExports(ctx context.Context, exporter *service.DataExportService) {
+	purged, err := exporter.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Expired data exports not purged: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("🗑️ Purged %d expired data exports", purged)
+	}
+}
+
+// RankTrending recomputes the trending score of every post
+func RankTrending(ctx context.Context, ranker *trending.Ranker) {
+	if _, err := ranker.Recompute(ctx, time.Now()); err != nil {
+		log.Printf("⚠️ Trending scores not updated: %v", err)
+	}
+}
+
+// RelatePosts recomputes the related posts of every listed post from their
+// tags, category and text and from what readers read together within the
+// lookback period
+func RelatePosts(ctx context.Context, blogRepo *repository.BlogRepository, visitRepo *repository.ReaderVisitRepository, relatedRepo *repository.RelatedRepository, lookback time.Duration) {
+	start := time.Now()
+
+	var posts []recommend.Post
+	err := blogRepo.ForEachListed(ctx, func(b *blog.Blog) error {
+		text := []string{b.Content}
+		for _, section := range b.Sections {
+			text = append(text, section)
+		}
+		posts = append(posts, recommend.Post{
+			ID:       b.ID,
+			Category: b.Category,
+			Tags:     b.Tags,
+			Title:    b.Title,
+			Text:     strings.Join(text, "\n"),
+		})
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️ Related posts not updated: %v", err)
+		return
+	}
+
+	co := recommend.NewCoReadership()
+	err = visitRepo.ForEachSession(ctx, start.Add(-lookback), func(blogIDs []primitive.ObjectID) error {
+		co.AddSession(blogIDs)
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️ Related posts not updated: %v", err)
+		return
+	}
+
+	results := recommend.Related(posts, co, recommend.DefaultWeights, related.MaxPosts)
+	if err := relatedRepo.ReplaceAll(ctx, results, start); err != nil {
+		log.Printf("⚠️ Related posts not updated: %v", err)
+	}
+}
+
+// SendDigests emails every author whose activity digest is due. One
+// author's failure does not hold up the rest.
+func SendDigests(ctx context.Context, authorRepo *repository.AuthorRepository, sender *digest.Sender) {
+	now := time.Now()
+	sent := 0
+
+	err := authorRepo.ForEachAuthor(ctx, func(a *author.Author) error {
+		ok, err := sender.SendDue(ctx, a, now)
+		if err != nil {
+			log.Printf("⚠️ Failed to send digest to author %s: %v", a.ID.Hex(), err)
+		}
+		if ok {
+			sent++
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		log.Printf("⚠️ Digest run stopped: %v", err)
+	}
+
+	if sent > 0 {
+		log.Printf("📬 Sent %d activity digests", sent)
+	}
+}
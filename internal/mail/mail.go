@@ -0,0 +1,126 @@
+// Package mail sends email. Code that sends mail depends on the Mailer
+// interface, so tests can record messages instead of talking to an SMTP
+// server.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// Message is an email with a plain-text and an HTML body
+type Message struct {
+	To      string // Address
+	ToName  string // Optional display name
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPSettings configures an SMTPMailer
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string // Leave empty for servers without authentication
+	Password string
+	From     string // Sender address, optionally with a display name
+}
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	settings SMTPSettings
+}
+
+func NewSMTPMailer(settings SMTPSettings) *SMTPMailer {
+	return &SMTPMailer{settings: settings}
+}
+
+// Send delivers msg as a multipart/alternative email
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(m.settings.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	to := &mail.Address{Name: msg.ToName, Address: msg.To}
+
+	body, err := Compose(from, to, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.settings.Username != "" {
+		auth = smtp.PlainAuth("", m.settings.Username, m.settings.Password, m.settings.Host)
+	}
+	addr := net.JoinHostPort(m.settings.Host, strconv.Itoa(m.settings.Port))
+
+	// net/smtp has no context support; give up waiting once ctx is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, from.Address, []string{to.Address}, body)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Compose renders msg as a MIME message with a plain-text part and an HTML
+// alternative
+func Compose(from, to *mail.Address, msg Message) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from.String())
+	fmt.Fprintf(&out, "To: %s\r\n", to.String())
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
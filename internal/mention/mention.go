@@ -0,0 +1,185 @@
+// Package mention turns @name mentions into links to author profiles.
+//
+// Writers can mention an author as @handle (no spaces) or @[Display Name].
+// Resolved mentions are stored as Markdown links to the author's profile,
+// keyed by author ID, e.g. [@Ada Lovelace](/authors/64b...). The ID keeps a
+// mention pointing at the right author after a rename; Relabel refreshes
+// the visible name. Mentions that do not resolve stay as plain text.
+package mention
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPerText caps how many distinct authors one text can mention; further
+// mentions stay as plain text
+const MaxPerText = 10
+
+// MaxTokens caps how many @tokens of one text are looked up, so a text
+// stuffed with @names cannot turn into a large lookup; later tokens stay
+// as written
+const MaxTokens = 50
+
+// pattern matches, in order of preference: an existing profile link, a
+// bracketed display name, and a bare handle
+var pattern = regexp.MustCompile(`\[@([^\]\n]+)\]\(/authors/([0-9a-fA-F]{24})\)|@\[([^\]\n]{1,64})\]|@([\pL\pN_][\pL\pN_.\-]{0,63})`)
+
+// Author is a registered author a mention can point at
+type Author struct {
+	ID   primitive.ObjectID
+	Name string
+}
+
+// Directory looks up registered authors
+type Directory interface {
+	// Lookup returns the authors among the given names (matched
+	// case-insensitively) and IDs, all in one go
+	Lookup(names []string, ids []primitive.ObjectID) []Author
+}
+
+// token is a possible mention found in a text
+type token struct {
+	start, end int
+	name       string             // Handle or display name to look up
+	linked     primitive.ObjectID // Author of an existing link
+	label      string             // Visible name of an existing link
+}
+
+// Link renders a mention of an author
+func Link(id primitive.ObjectID, name string) string {
+	return "[@" + escape(name) + "](/authors/" + id.Hex() + ")"
+}
+
+// Resolve rewrites the mentions in text as profile links and returns the
+// mentioned authors in order of first appearance
+func Resolve(text string, dir Directory) (string, []primitive.ObjectID) {
+	tokens := scan(text)
+	if len(tokens) == 0 {
+		return text, nil
+	}
+
+	var (
+		names     []string
+		linked    []primitive.ObjectID
+		seenNames = map[string]bool{}
+	)
+	for _, t := range tokens {
+		if !t.linked.IsZero() {
+			linked = append(linked, t.linked)
+		} else if key := strings.ToLower(t.name); !seenNames[key] {
+			seenNames[key] = true
+			names = append(names, t.name)
+		}
+	}
+	byName := map[string]Author{}
+	byID := map[primitive.ObjectID]string{}
+	for _, a := range dir.Lookup(names, linked) {
+		byName[strings.ToLower(a.Name)] = a
+		byID[a.ID] = a.Name
+	}
+
+	var (
+		sb   strings.Builder
+		ids  []primitive.ObjectID
+		seen = map[primitive.ObjectID]bool{}
+		last = 0
+	)
+	for _, t := range tokens {
+		var (
+			id   primitive.ObjectID
+			name string
+			ok   bool
+		)
+		if !t.linked.IsZero() {
+			id = t.linked
+			if name, ok = byID[id]; !ok {
+				sb.WriteString(text[last:t.start])
+				sb.WriteString("@" + t.label)
+				last = t.end
+				continue
+			}
+		} else {
+			var a Author
+			a, ok = byName[strings.ToLower(t.name)]
+			id, name = a.ID, a.Name
+		}
+
+		if !ok || (!seen[id] && len(ids) >= MaxPerText) {
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+
+		sb.WriteString(text[last:t.start])
+		sb.WriteString(Link(id, name))
+		last = t.end
+	}
+
+	sb.WriteString(text[last:])
+	return sb.String(), ids
+}
+
+// scan finds the first MaxTokens possible mentions in text
+func scan(text string) []token {
+	var tokens []token
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		if len(tokens) == MaxTokens {
+			break
+		}
+		t := token{start: m[0], end: m[1]}
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+
+		switch {
+		case group(2) != "":
+			// Existing link, e.g. from an edit of already-resolved text
+			id, err := primitive.ObjectIDFromHex(group(2))
+			if err != nil {
+				continue
+			}
+			t.linked, t.label = id, group(1)
+		case group(3) != "":
+			t.name = group(3)
+		default:
+			// A bare handle preceded by a word character is an email
+			// address or similar, not a mention
+			if r, _ := utf8.DecodeLastRuneInString(text[:t.start]); t.start > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+				continue
+			}
+			// Trailing punctuation belongs to the sentence, not the name
+			t.name = strings.TrimRight(group(4), ".-")
+			t.end = t.start + 1 + len(t.name)
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// Relabel updates the visible name of every link to an author
+func Relabel(text string, id primitive.ObjectID, name string) string {
+	link := regexp.MustCompile(`\[@[^\]\n]+\]\(/authors/(?i:` + id.Hex() + `)\)`)
+	return link.ReplaceAllLiteralString(text, Link(id, name))
+}
+
+// Unlink turns every link to an author back into plain text, for authors
+// who no longer exist
+func Unlink(text string, id primitive.ObjectID) string {
+	link := regexp.MustCompile(`\[@([^\]\n]+)\]\(/authors/(?i:` + id.Hex() + `)\)`)
+	return link.ReplaceAllString(text, "@$1")
+}
+
+// escape keeps a name from breaking out of the link text
+func escape(name string) string {
+	return strings.NewReplacer("[", "(", "]", ")", "\n", " ").Replace(name)
+}
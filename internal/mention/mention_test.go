@@ -0,0 +1,120 @@
+package mention
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// directory is an in-memory author lookup
+type directory map[primitive.ObjectID]string
+
+func (d directory) Lookup(names []string, ids []primitive.ObjectID) []Author {
+	var found []Author
+	for id, n := range d {
+		for _, name := range names {
+			if strings.EqualFold(n, name) {
+				found = append(found, Author{ID: id, Name: n})
+			}
+		}
+		for _, linked := range ids {
+			if linked == id {
+				found = append(found, Author{ID: id, Name: n})
+			}
+		}
+	}
+	return found
+}
+
+// countingDirectory records what each lookup asked for
+type countingDirectory struct {
+	directory
+	calls int
+	names int
+}
+
+func (d *countingDirectory) Lookup(names []string, ids []primitive.ObjectID) []Author {
+	d.calls++
+	d.names += len(names) + len(ids)
+	return d.directory.Lookup(names, ids)
+}
+
+func TestResolve(t *testing.T) {
+	ada, grace := primitive.NewObjectID(), primitive.NewObjectID()
+	dir := directory{ada: "ada", grace: "Grace Hopper"}
+
+	t.Run("handle and display name", func(t *testing.T) {
+		text, ids := Resolve("Thanks @Ada and @[grace hopper]!", dir)
+		assert.Equal(t, "Thanks "+Link(ada, "ada")+" and "+Link(grace, "Grace Hopper")+"!", text)
+		assert.Equal(t, []primitive.ObjectID{ada, grace}, ids)
+	})
+
+	t.Run("trailing punctuation stays outside the link", func(t *testing.T) {
+		text, _ := Resolve("ask @ada.", dir)
+		assert.Equal(t, "ask "+Link(ada, "ada")+".", text)
+	})
+
+	t.Run("unknown names and email addresses stay as text", func(t *testing.T) {
+		in := "mail ada@example.com or @nobody"
+		text, ids := Resolve(in, dir)
+		assert.Equal(t, in, text)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("repeated mentions are listed once", func(t *testing.T) {
+		_, ids := Resolve("@ada @ada", dir)
+		assert.Equal(t, []primitive.ObjectID{ada}, ids)
+	})
+
+	t.Run("existing links are kept and relabelled", func(t *testing.T) {
+		text, ids := Resolve("see "+Link(ada, "old name"), dir)
+		assert.Equal(t, "see "+Link(ada, "ada"), text)
+		assert.Equal(t, []primitive.ObjectID{ada}, ids)
+	})
+
+	t.Run("links to missing authors become text", func(t *testing.T) {
+		gone := primitive.NewObjectID()
+		text, ids := Resolve("see "+Link(gone, "Bob"), dir)
+		assert.Equal(t, "see @Bob", text)
+		assert.Empty(t, ids)
+	})
+}
+
+func TestResolve_Lookups(t *testing.T) {
+	ada, grace := primitive.NewObjectID(), primitive.NewObjectID()
+
+	t.Run("every mention is looked up in one call", func(t *testing.T) {
+		dir := &countingDirectory{directory: directory{ada: "ada", grace: "Grace Hopper"}}
+		_, ids := Resolve("@ada @[Grace Hopper] @ADA @nobody "+Link(ada, "ada"), dir)
+
+		assert.Equal(t, []primitive.ObjectID{ada, grace}, ids)
+		assert.Equal(t, 1, dir.calls)
+		assert.Equal(t, 4, dir.names, "repeated names are looked up once")
+	})
+
+	t.Run("texts without mentions need no lookup", func(t *testing.T) {
+		dir := &countingDirectory{directory: directory{ada: "ada"}}
+		Resolve("mail ada@example.com", dir)
+		assert.Zero(t, dir.calls)
+	})
+
+	t.Run("tokens past the cap stay as written", func(t *testing.T) {
+		dir := &countingDirectory{directory: directory{ada: "ada"}}
+		in := strings.Repeat("@x ", MaxTokens) + "@ada"
+		text, ids := Resolve(in, dir)
+
+		assert.Equal(t, in, text)
+		assert.Empty(t, ids)
+		assert.Equal(t, 1, dir.names, "the distinct names under the cap")
+	})
+}
+
+func TestRelabelAndUnlink(t *testing.T) {
+	id := primitive.NewObjectID()
+	text := "hi " + Link(id, "ada") + " and " + Link(id, "ada")
+
+	assert.Equal(t, "hi "+Link(id, "Ada L")+" and "+Link(id, "Ada L"), Relabel(text, id, "Ada L"))
+	assert.Equal(t, "hi @ada and @ada", Unlink(text, id))
+}
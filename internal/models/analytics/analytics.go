@@ -0,0 +1,175 @@
+package analytics
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Granularity is the length of a bucket
+type Granularity string
+
+const (
+	Hourly Granularity = "hour"
+	Daily  Granularity = "day"
+)
+
+// Valid reports whether g is a known granularity
+func (g Granularity) Valid() bool {
+	return g == Hourly || g == Daily
+}
+
+// Duration is the length of one bucket
+func (g Granularity) Duration() time.Duration {
+	if g == Hourly {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Start returns the start of the bucket containing t, in UTC
+func (g Granularity) Start(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Duration())
+}
+
+// HourlyRetention is how long hourly buckets are kept. Daily buckets are
+// kept for good.
+const HourlyRetention = 90 * 24 * time.Hour
+
+// Metric names a counter
+type Metric string
+
+const (
+	MetricViews    Metric = "views"
+	MetricReaders  Metric = "readers"
+	MetricLikes    Metric = "likes"
+	MetricComments Metric = "comments"
+	MetricShares   Metric = "shares"
+)
+
+// Metrics lists every metric
+var Metrics = []Metric{MetricViews, MetricReaders, MetricLikes, MetricComments, MetricShares}
+
+// Valid reports whether m is a known metric
+func (m Metric) Valid() bool {
+	for _, known := range Metrics {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts are the activity counted in a bucket
+type Counts struct {
+	Views    int `bson:"views" json:"views"`       // Views by people other than the post's authors
+	Readers  int `bson:"readers" json:"readers"`   // Unique readers
+	Likes    int `bson:"likes" json:"likes"`       // New likes
+	Comments int `bson:"comments" json:"comments"` // Comments published
+	Shares   int `bson:"shares" json:"shares"`     // Shares on any platform
+}
+
+// Add adds other to c
+func (c *Counts) Add(other Counts) {
+	c.Views += other.Views
+	c.Readers += other.Readers
+	c.Likes += other.Likes
+	c.Comments += other.Comments
+	c.Shares += other.Shares
+}
+
+// Get returns the count for a metric
+func (c Counts) Get(m Metric) int {
+	switch m {
+	case MetricViews:
+		return c.Views
+	case MetricReaders:
+		return c.Readers
+	case MetricLikes:
+		return c.Likes
+	case MetricComments:
+		return c.Comments
+	case MetricShares:
+		return c.Shares
+	}
+	return 0
+}
+
+// Bucket holds one post's activity over one hour or day
+type Bucket struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	BlogID      primitive.ObjectID `bson:"blog_id" json:"blog_id"`
+	Granularity Granularity        `bson:"granularity" json:"granularity"`
+	Start       time.Time          `bson:"start" json:"start"`
+	Counts      Counts             `bson:"counts" json:"counts"`
+	Referrers   map[string]int     `bson:"referrers,omitempty" json:"referrers,omitempty"` // Views by referring site
+	Platforms   map[string]int     `bson:"platforms,omitempty" json:"platforms,omitempty"` // Shares by platform
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty" json:"-"`                  // Set on hourly buckets only
+}
+
+// Event is activity to add to a post's buckets
+type Event struct {
+	BlogID   primitive.ObjectID
+	At       time.Time
+	Counts   Counts
+	Referrer string // Referring site of a view
+	Platform string // Platform of a share
+}
+
+// Point is the activity in one bucket across a set of posts
+type Point struct {
+	Start  time.Time `bson:"_id" json:"start"`
+	Counts `bson:",inline"`
+}
+
+// LabelPoint is a per-label breakdown, such as shares per platform, of one
+// bucket across a set of posts
+type LabelPoint struct {
+	Start  time.Time      `json:"start"`
+	Counts map[string]int `json:"counts"`
+}
+
+// PostTotals is one post's activity over a range
+type PostTotals struct {
+	BlogID primitive.ObjectID `bson:"_id" json:"blog_id"`
+	Counts `bson:",inline"`
+}
+
+// DirectReferrer labels views that came without a referrer
+const DirectReferrer = "direct"
+
+// Referrers are set by clients, so a bucket names at most MaxReferrers
+// sites and counts views from any further ones under OtherReferrer
+const (
+	MaxReferrers  = 50
+	OtherReferrer = "other"
+)
+
+// ReferrerHost reduces a referrer URL to the site it came from, such as
+// "news.ycombinator.com". Views without a usable referrer are "direct".
+func ReferrerHost(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || u.Hostname() == "" {
+		return DirectReferrer
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Referrers and platforms are stored as document keys, where dots would
+// be read as paths and a leading $ as an operator
+var (
+	keyEscaper   = strings.NewReplacer(".", "\uff0e", "$", "\uff04")
+	keyUnescaper = strings.NewReplacer("\uff0e", ".", "\uff04", "$")
+)
+
+// Key escapes a breakdown label for use as a document key
+func Key(label string) string {
+	return keyEscaper.Replace(label)
+}
+
+// Label reverses Key
+func Label(key string) string {
+	return keyUnescaper.Replace(key)
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Action names a kind of audited event
+type Action string
+
+const (
+	ActionLogin       Action = "login"
+	ActionLoginFailed Action = "login_failed"
+	ActionDataExport  Action = "data_export"
+
+	ActionCommentApproved Action = "comment_approved"
+	ActionCommentRejected Action = "comment_rejected"
+	ActionCommentDeleted  Action = "comment_deleted"
+	ActionCommentSpam     Action = "comment_marked_spam"
+	ActionCommentHam      Action = "comment_marked_ham"
+
+	ActionReportResolved  Action = "report_resolved"
+	ActionReportDismissed Action = "report_dismissed"
+)
+
+// Event is an entry in the audit log
+type Event struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ActorID    primitive.ObjectID  `bson:"actor_id" json:"actor_id"` // Author who performed the action
+	Action     Action              `bson:"action" json:"action"`
+	TargetType string              `bson:"target_type,omitempty" json:"target_type,omitempty"` // e.g. author, blog, comment
+	TargetID   *primitive.ObjectID `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	Reason     string              `bson:"reason,omitempty" json:"reason,omitempty"`
+	IP         string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string              `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+}
@@ -8,8 +8,9 @@ import (
 type UserRole string
 
 const (
-    RoleFounder UserRole = "founder"
-    RoleGuest   UserRole = "guest"
+    RoleFounder   UserRole = "founder"
+    RoleModerator UserRole = "moderator"
+    RoleGuest     UserRole = "guest"
 )
 
 type Author struct {
@@ -21,6 +22,16 @@ type Author struct {
     Phone     string             `bson:"phone,omitempty" json:"phone"`
     AvatarURL string             `bson:"avatar_url,omitempty" json:"avatar_url"`
     Bio       string             `bson:"bio,omitempty" json:"bio"`
+
+    // Kept in step by follow and unfollow so profiles need no counting
+    FollowerCount  int `bson:"follower_count" json:"follower_count"`
+    FollowingCount int `bson:"following_count" json:"following_count"`
+
     CreatedAt time.Time          `bson:"created_at" json:"created_at"`
     UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
+
+// IsModerator reports whether the author may moderate community content
+func (a *Author) IsModerator() bool {
+    return a.Role == RoleFounder || a.Role == RoleModerator
+}
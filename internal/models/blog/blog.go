@@ -13,16 +13,37 @@ const (
     TypeCaseStudy DocumentType = "case_study"
 )
 
+// Valid reports whether t is a known document type
+func (t DocumentType) Valid() bool {
+    return t == TypeBlog || t == TypeTDD || t == TypeCaseStudy
+}
+
 type Blog struct {
     ID        primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
     AuthorID  primitive.ObjectID   `bson:"author_id" json:"author_id"`
     Title     string               `bson:"title" json:"title"`
     Content   string               `bson:"content" json:"content"`
+    Sections  map[string]string    `bson:"sections,omitempty" json:"sections,omitempty"` // Structured sections for TDDs and case studies
     ImageURL  string               `bson:"image_url,omitempty" json:"image_url"`
     Type      DocumentType         `bson:"type" json:"type"` // New: blog, tdd, or case_study
     Category  string               `bson:"category" json:"category"`
-    Readers   int                  `bson:"readers" json:"readers"`
+    Tags      []string             `bson:"tags,omitempty" json:"tags,omitempty"` // Lowercase topic tags, see NormalizeTags
+    Readers   int                  `bson:"readers" json:"readers"` // Unique readers, each counted once per counting window
+    Views     int                  `bson:"views" json:"views"` // Every view by a person other than the post's authors
+    TrendingScore float64          `bson:"trending_score,omitempty" json:"trending_score,omitempty"` // Recent engagement with time decay, recomputed by a background job
     CreatedAt time.Time            `bson:"created_at" json:"created_at"`
     UpdatedAt time.Time            `bson:"updated_at" json:"updated_at"`
+    DeletedAt *time.Time           `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Set while the post is in the trash
     Likes     []primitive.ObjectID `bson:"likes,omitempty" json:"likes,omitempty"`
+    Reactions map[string]int       `bson:"reactions,omitempty" json:"reactions,omitempty"` // Count of each emoji reaction
+    Shares    map[string]int       `bson:"shares,omitempty" json:"shares,omitempty"` // Count of shares on each platform
+    Mentions  []primitive.ObjectID `bson:"mentions,omitempty" json:"mentions,omitempty"` // Authors @mentioned in the content
+    PremoderateComments bool       `bson:"premoderate_comments" json:"premoderate_comments"` // Hold new comments for moderation
+    Hidden    bool                 `bson:"hidden,omitempty" json:"hidden,omitempty"` // Hidden from readers while abuse reports are reviewed
+    CoAuthors []CoAuthor           `bson:"co_authors,omitempty" json:"co_authors,omitempty"` // Ordered contributor credits
+
+    // Decision lifecycle, only used by TDD documents
+    Status       DecisionStatus      `bson:"status,omitempty" json:"status,omitempty"`
+    Supersedes   *primitive.ObjectID `bson:"supersedes,omitempty" json:"supersedes,omitempty"`
+    SupersededBy *primitive.ObjectID `bson:"superseded_by,omitempty" json:"superseded_by,omitempty"`
 }
@@ -0,0 +1,65 @@
+package blog
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContributionRole describes how a co-author contributed to a post
+type ContributionRole string
+
+const (
+	RoleCoAuthor ContributionRole = "author"
+	RoleReviewer ContributionRole = "reviewer"
+	RoleEditor   ContributionRole = "editor"
+)
+
+// Valid reports whether r is a known contribution role
+func (r ContributionRole) Valid() bool {
+	return r == RoleCoAuthor || r == RoleReviewer || r == RoleEditor
+}
+
+// InviteStatus tracks whether an invited co-author has responded
+type InviteStatus string
+
+const (
+	InvitePending  InviteStatus = "pending"
+	InviteAccepted InviteStatus = "accepted"
+	InviteDeclined InviteStatus = "declined"
+)
+
+// CoAuthor is a credited contributor on a post, in display order
+type CoAuthor struct {
+	AuthorID    primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Role        ContributionRole   `bson:"role" json:"role"`
+	Status      InviteStatus       `bson:"status" json:"status"`
+	InvitedAt   time.Time          `bson:"invited_at" json:"invited_at"`
+	RespondedAt *time.Time         `bson:"responded_at,omitempty" json:"responded_at,omitempty"`
+}
+
+// CoAuthorIndex returns the position of an author in the co-author list, or -1
+func (b *Blog) CoAuthorIndex(authorID primitive.ObjectID) int {
+	for i, ca := range b.CoAuthors {
+		if ca.AuthorID == authorID {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsCredited reports whether an author is credited on the post: the
+// owner and every co-author who has accepted their invitation
+func (b *Blog) IsCredited(authorID primitive.ObjectID) bool {
+	if b.AuthorID == authorID {
+		return true
+	}
+	idx := b.CoAuthorIndex(authorID)
+	return idx >= 0 && b.CoAuthors[idx].Status == InviteAccepted
+}
+
+// CanEdit reports whether an author may edit the post. Every credited
+// author has edit rights.
+func (b *Blog) CanEdit(authorID primitive.ObjectID) bool {
+	return b.IsCredited(authorID)
+}
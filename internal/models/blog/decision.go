@@ -0,0 +1,48 @@
+package blog
+
+// DecisionStatus tracks where a technical design document is in its lifecycle
+type DecisionStatus string
+
+const (
+	StatusProposed    DecisionStatus = "proposed"
+	StatusAccepted    DecisionStatus = "accepted"
+	StatusImplemented DecisionStatus = "implemented"
+	StatusRejected    DecisionStatus = "rejected"
+	StatusSuperseded  DecisionStatus = "superseded"
+)
+
+// decisionTransitions lists the statuses each status may move to.
+// Rejected and superseded are terminal.
+var decisionTransitions = map[DecisionStatus][]DecisionStatus{
+	StatusProposed:    {StatusAccepted, StatusRejected, StatusSuperseded},
+	StatusAccepted:    {StatusImplemented, StatusRejected, StatusSuperseded},
+	StatusImplemented: {StatusSuperseded},
+}
+
+// Valid reports whether s is a known decision status
+func (s DecisionStatus) Valid() bool {
+	switch s {
+	case StatusProposed, StatusAccepted, StatusImplemented, StatusRejected, StatusSuperseded:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a TDD may move from s to next
+func (s DecisionStatus) CanTransitionTo(next DecisionStatus) bool {
+	for _, allowed := range decisionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// DecisionStatusOf returns the effective status of a TDD.
+// Documents created before statuses existed are treated as proposed.
+func DecisionStatusOf(b *Blog) DecisionStatus {
+	if b.Status == "" {
+		return StatusProposed
+	}
+	return b.Status
+}
@@ -0,0 +1,39 @@
+package blog
+
+import (
+	"errors"
+	"strings"
+)
+
+// Limits on the tags of a post
+const (
+	MaxTags      = 10
+	MaxTagLength = 32
+)
+
+var (
+	ErrTooManyTags = errors.New("a post can have at most 10 tags")
+	ErrTagTooLong  = errors.New("tags can be at most 32 characters")
+)
+
+// NormalizeTags lowercases and trims tags, joining inner whitespace with
+// hyphens, and drops empty and repeated ones
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), "-")
+		if t == "" || seen[t] {
+			continue
+		}
+		if len(t) > MaxTagLength {
+			return nil, ErrTagTooLong
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	if len(normalized) > MaxTags {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
@@ -0,0 +1,91 @@
+package blog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section describes one part of a structured document
+type Section struct {
+	Key         string `json:"key"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// Template is the schema of sections a document type must provide
+type Template struct {
+	Type     DocumentType `json:"type"`
+	Sections []Section    `json:"sections"`
+}
+
+// Templates holds the section schema for every structured document type.
+// Plain blogs have no template and keep using the free-form Content field.
+var Templates = map[DocumentType]Template{
+	TypeTDD: {
+		Type: TypeTDD,
+		Sections: []Section{
+			{Key: "context", Title: "Context", Description: "Background and the problem that prompted this design", Required: true},
+			{Key: "goals", Title: "Goals", Description: "What this design must achieve", Required: true},
+			{Key: "non_goals", Title: "Non-goals", Description: "What is explicitly out of scope", Required: true},
+			{Key: "design", Title: "Design", Description: "The proposed solution in detail", Required: true},
+			{Key: "alternatives", Title: "Alternatives", Description: "Options considered and why they were not chosen", Required: true},
+			{Key: "decision", Title: "Decision", Description: "The outcome and its rationale", Required: true},
+		},
+	},
+	TypeCaseStudy: {
+		Type: TypeCaseStudy,
+		Sections: []Section{
+			{Key: "problem", Title: "Problem", Description: "The situation or challenge being studied", Required: true},
+			{Key: "approach", Title: "Approach", Description: "How the problem was tackled", Required: true},
+			{Key: "results", Title: "Results & Metrics", Description: "Measured outcomes and what was learned", Required: true},
+		},
+	},
+}
+
+// TemplateFor returns the template for a document type, if it has one
+func TemplateFor(t DocumentType) (Template, bool) {
+	tpl, ok := Templates[t]
+	return tpl, ok
+}
+
+// Skeleton returns an empty section map that clients can fill in
+func (t Template) Skeleton() map[string]string {
+	skeleton := make(map[string]string, len(t.Sections))
+	for _, s := range t.Sections {
+		skeleton[s.Key] = ""
+	}
+	return skeleton
+}
+
+// ValidateSections checks that sections match the template of the given type.
+// Required sections must be non-empty and unknown section keys are rejected.
+func ValidateSections(t DocumentType, sections map[string]string) error {
+	tpl, ok := TemplateFor(t)
+	if !ok {
+		if len(sections) > 0 {
+			return fmt.Errorf("document type %q does not support sections", t)
+		}
+		return nil
+	}
+
+	known := make(map[string]bool, len(tpl.Sections))
+	var missing []string
+	for _, s := range tpl.Sections {
+		known[s.Key] = true
+		if s.Required && strings.TrimSpace(sections[s.Key]) == "" {
+			missing = append(missing, s.Key)
+		}
+	}
+
+	for key := range sections {
+		if !known[key] {
+			return fmt.Errorf("unknown section %q for document type %q", key, t)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required sections: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package bookmark
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bookmark saves a post for an author to read later
+type Bookmark struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthorID  primitive.ObjectID `bson:"author_id" json:"author_id"`
+	BlogID    primitive.ObjectID `bson:"blog_id" json:"blog_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// MaxEntries caps how many posts one reading list can hold
+const MaxEntries = 500
+
+var (
+	ErrAlreadyListed = errors.New("post is already in this list")
+	ErrListFull      = errors.New("reading list is full")
+)
+
+// ReadingList is a named, ordered collection of posts kept by an author
+type ReadingList struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OwnerID     primitive.ObjectID `bson:"owner_id" json:"owner_id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Public      bool               `bson:"public" json:"public"`   // Anyone can view public lists; private ones only their owner
+	Entries     []Entry            `bson:"entries" json:"entries"` // In the owner's chosen order
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Entry is a post in a reading list
+type Entry struct {
+	BlogID  primitive.ObjectID `bson:"blog_id" json:"blog_id"`
+	Note    string             `bson:"note,omitempty" json:"note,omitempty"` // The owner's note on the post
+	AddedAt time.Time          `bson:"added_at" json:"added_at"`
+}
+
+// EntryIndex returns the position of a post in the list, or -1
+func (l *ReadingList) EntryIndex(blogID primitive.ObjectID) int {
+	for i, e := range l.Entries {
+		if e.BlogID == blogID {
+			return i
+		}
+	}
+	return -1
+}
+
+// VisibleTo reports whether an author may view the list. A nil viewer is
+// an anonymous reader.
+func (l *ReadingList) VisibleTo(viewerID *primitive.ObjectID) bool {
+	return l.Public || (viewerID != nil && *viewerID == l.OwnerID)
+}
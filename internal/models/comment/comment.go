@@ -5,14 +5,82 @@ import (
 	"time"
 )
 
+// Status is the moderation state of a comment
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusSpam     Status = "spam"
+)
+
+// Valid reports whether s is a known moderation status
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusRejected, StatusSpam:
+		return true
+	}
+	return false
+}
+
 // Comment represents a comment left by a reader on a blog
 type Comment struct {
-	ID        primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-	BlogID    primitive.ObjectID   `bson:"blog_id" json:"blog_id"`             // Blog this comment belongs to
-	Username  string               `bson:"username" json:"username"`           // Name of the commentor
-	Content   string               `bson:"content" json:"content"`             // Comment text
-	Likes     int                  `bson:"likes" json:"likes"`                 // Number of likes
-	LikedBy   []string             `bson:"liked_by,omitempty" json:"liked_by"` // Track users who liked this comment (to ensure one like per person)
-	CreatedAt time.Time            `bson:"created_at" json:"created_at"`       // Timestamp
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	BlogID        primitive.ObjectID   `bson:"blog_id" json:"blog_id"`                           // Blog this comment belongs to
+	ParentID      *primitive.ObjectID  `bson:"parent_id,omitempty" json:"parent_id,omitempty"`   // Comment being replied to, nil for top-level comments
+	Ancestors     []primitive.ObjectID `bson:"ancestors,omitempty" json:"-"`                     // Materialized path from the thread root down to the parent
+	Depth         int                  `bson:"depth" json:"depth"`                               // 0 for top-level comments
+	ReplyCount    int                  `bson:"reply_count" json:"reply_count"`                   // Number of direct replies
+	Username      string               `bson:"username" json:"username"`                         // Name of the commentor
+	AuthorID      *primitive.ObjectID  `bson:"author_id,omitempty" json:"author_id,omitempty"`   // Registered author who wrote the comment, nil for anonymous comments
+	AvatarURL     string               `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"` // Avatar of the registered author
+	Verified      bool                 `bson:"verified" json:"verified"`                         // Username was taken from an authenticated account
+	IsPostAuthor  bool                 `bson:"is_post_author" json:"is_post_author"`             // Written by one of the post's credited authors
+	Content       string               `bson:"content" json:"content"`                           // Comment text
+	Mentions      []primitive.ObjectID `bson:"mentions,omitempty" json:"mentions,omitempty"`     // Authors @mentioned in the content
+	Likes         int                  `bson:"likes" json:"likes"`                               // Number of likes
+	LikedBy       []string             `bson:"liked_by,omitempty" json:"liked_by"`               // Track users who liked this comment (to ensure one like per person)
+	Reactions     map[string]int       `bson:"reactions,omitempty" json:"reactions,omitempty"`   // Count of each emoji reaction
+	Status        Status               `bson:"status,omitempty" json:"status"`                   // Moderation state; comments without one predate moderation and count as approved
+	SpamScore     float64              `bson:"spam_score,omitempty" json:"-"`                    // Combined spam score between 0 and 1, shown to moderators only
+	SpamReasons   []string             `bson:"spam_reasons,omitempty" json:"-"`                  // Signals behind the spam score, shown to moderators only
+	Fingerprint   string               `bson:"fingerprint,omitempty" json:"-"`                   // Hash of the normalised content, used to spot repeated comments
+	IP            string               `bson:"ip,omitempty" json:"-"`                            // Address the comment was posted from
+	TrainedAs     Status               `bson:"trained_as,omitempty" json:"-"`                    // Label the spam classifier learned from this comment
+	EditedAt      *time.Time           `bson:"edited_at,omitempty" json:"edited_at,omitempty"`   // Set once the writer has edited the comment
+	History       []Revision           `bson:"history,omitempty" json:"history,omitempty"`       // Earlier versions of the content, oldest first
+	Withdrawn     bool                 `bson:"withdrawn,omitempty" json:"withdrawn,omitempty"`   // Deleted by its writer but kept as a tombstone for its replies
+	Hidden        bool                 `bson:"hidden,omitempty" json:"hidden,omitempty"`         // Hidden from readers while abuse reports are reviewed
+	EditTokenHash string               `bson:"edit_token_hash,omitempty" json:"-"`               // Proves ownership of anonymous comments
+	EditToken     string               `bson:"-" json:"edit_token,omitempty"`                    // Returned once, when an anonymous comment is created
+	CreatedAt     time.Time            `bson:"created_at" json:"created_at"`                     // Timestamp
+	DeletedAt     *time.Time           `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Set while the comment is in the trash
+}
+
+// Revision is an earlier version of an edited comment
+type Revision struct {
+	Content  string    `bson:"content" json:"content"`
+	EditedAt time.Time `bson:"edited_at" json:"edited_at"` // When this version was replaced
 }
 
+// Tombstone is the text shown in place of a comment withdrawn by its writer
+const Tombstone = "[deleted]"
+
+// ModerationView is a comment as moderators see it, including the spam
+// signals kept from readers and writers so spammers cannot tune against them
+type ModerationView struct {
+	*Comment
+	SpamScore   float64  `json:"spam_score,omitempty"`
+	SpamReasons []string `json:"spam_reasons,omitempty"`
+}
+
+// ForModeration returns the moderators' view of the comment
+func (c *Comment) ForModeration() ModerationView {
+	return ModerationView{Comment: c, SpamScore: c.SpamScore, SpamReasons: c.SpamReasons}
+}
+
+// IsVisible reports whether readers can see the comment
+func (c *Comment) IsVisible() bool {
+	return c.DeletedAt == nil && !c.Hidden && (c.Status == "" || c.Status == StatusApproved)
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpamSignalsStayWithModerators(t *testing.T) {
+	cmt := &Comment{Content: "Buy now", SpamScore: 0.9, SpamReasons: []string{"blocklist"}}
+
+	public, err := json.Marshal(cmt)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(public), "spam_score")
+	assert.NotContains(t, string(public), "spam_reasons")
+
+	moderation, err := json.Marshal(cmt.ForModeration())
+	assert.NoError(t, err)
+	assert.Contains(t, string(moderation), `"spam_score":0.9`)
+	assert.Contains(t, string(moderation), `"spam_reasons":["blocklist"]`)
+	assert.Contains(t, string(moderation), `"content":"Buy now"`)
+}
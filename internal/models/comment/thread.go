@@ -0,0 +1,41 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MaxDepth is the deepest a reply may be nested; top-level comments have depth 0
+const MaxDepth = 5
+
+// Node is a comment together with its replies
+type Node struct {
+	*Comment
+	Replies []*Node `json:"replies"`
+}
+
+// BuildTree arranges thread roots and their descendants into trees.
+// Replies are attached to their parent, or to the nearest ancestor that is
+// still present when the parent itself is missing (e.g. it was trashed).
+func BuildTree(roots, replies []*Comment) []*Node {
+	nodes := make(map[primitive.ObjectID]*Node, len(roots)+len(replies))
+	tree := make([]*Node, 0, len(roots))
+
+	for _, c := range roots {
+		n := &Node{Comment: c, Replies: []*Node{}}
+		nodes[c.ID] = n
+		tree = append(tree, n)
+	}
+	for _, c := range replies {
+		nodes[c.ID] = &Node{Comment: c, Replies: []*Node{}}
+	}
+
+	// Replies arrive oldest first, so each reply's ancestors are already known
+	for _, c := range replies {
+		for i := len(c.Ancestors) - 1; i >= 0; i-- {
+			if parent, ok := nodes[c.Ancestors[i]]; ok {
+				parent.Replies = append(parent.Replies, nodes[c.ID])
+				break
+			}
+		}
+	}
+
+	return tree
+}
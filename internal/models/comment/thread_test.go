@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reply is a comment nested under the given ancestors, root first
+func reply(ancestors ...*Comment) *Comment {
+	c := &Comment{ID: primitive.NewObjectID(), Depth: len(ancestors)}
+	for _, a := range ancestors {
+		c.Ancestors = append(c.Ancestors, a.ID)
+	}
+	if len(ancestors) > 0 {
+		parentID := ancestors[len(ancestors)-1].ID
+		c.ParentID = &parentID
+	}
+	return c
+}
+
+// ids lists the comment IDs of a level of the tree
+func ids(nodes []*Node) []primitive.ObjectID {
+	out := make([]primitive.ObjectID, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, n.ID)
+	}
+	return out
+}
+
+func TestBuildTree(t *testing.T) {
+	t.Run("Keeps the order of roots and replies", func(t *testing.T) {
+		newer, older := reply(), reply()
+		first := reply(newer)
+		second := reply(newer)
+		nested := reply(newer, first)
+
+		tree := BuildTree([]*Comment{newer, older}, []*Comment{first, second, nested})
+
+		assert.Equal(t, []primitive.ObjectID{newer.ID, older.ID}, ids(tree))
+		assert.Equal(t, []primitive.ObjectID{first.ID, second.ID}, ids(tree[0].Replies))
+		assert.Equal(t, []primitive.ObjectID{nested.ID}, ids(tree[0].Replies[0].Replies))
+		assert.Empty(t, tree[1].Replies)
+		assert.NotNil(t, tree[1].Replies, "leaves serialise as [] rather than null")
+	})
+
+	t.Run("Attaches orphans to their nearest remaining ancestor", func(t *testing.T) {
+		root := reply()
+		trashed := reply(root)
+		orphan := reply(root, trashed)
+		grandchild := reply(root, trashed, orphan)
+
+		tree := BuildTree([]*Comment{root}, []*Comment{orphan, grandchild})
+
+		assert.Equal(t, []primitive.ObjectID{orphan.ID}, ids(tree[0].Replies))
+		assert.Equal(t, []primitive.ObjectID{grandchild.ID}, ids(tree[0].Replies[0].Replies))
+	})
+
+	t.Run("Drops replies whose whole thread is missing", func(t *testing.T) {
+		root := reply()
+		stray := reply(reply())
+
+		tree := BuildTree([]*Comment{root}, []*Comment{stray})
+
+		assert.Len(t, tree, 1)
+		assert.Empty(t, tree[0].Replies)
+	})
+}
@@ -0,0 +1,53 @@
+package digest
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Frequency is how often an author receives an activity digest
+type Frequency string
+
+const (
+	FrequencyDaily  Frequency = "daily"
+	FrequencyWeekly Frequency = "weekly"
+	FrequencyOff    Frequency = "off" // The author opted out
+)
+
+// DefaultFrequency applies to authors who never chose one
+const DefaultFrequency = FrequencyWeekly
+
+// Valid reports whether f is a known frequency
+func (f Frequency) Valid() bool {
+	return f == FrequencyDaily || f == FrequencyWeekly || f == FrequencyOff
+}
+
+// Period returns how much activity one digest covers, or 0 when digests
+// are off
+func (f Frequency) Period() time.Duration {
+	switch f {
+	case FrequencyDaily:
+		return 24 * time.Hour
+	case FrequencyWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Counts are a post's running totals when a digest was last sent. Likes and
+// readers are only kept as totals, so the next digest reports the growth
+// since then.
+type Counts struct {
+	Likes   int `bson:"likes" json:"likes"`
+	Readers int `bson:"readers" json:"readers"`
+}
+
+// Subscription is an author's digest schedule and where the last digest
+// left off
+type Subscription struct {
+	AuthorID   primitive.ObjectID `bson:"_id" json:"-"`
+	Frequency  Frequency          `bson:"frequency" json:"frequency"`
+	LastSentAt *time.Time         `bson:"last_sent_at,omitempty" json:"last_sent_at,omitempty"` // End of the period the last digest covered
+	Baseline   map[string]Counts  `bson:"baseline,omitempty" json:"-"`                          // Totals per blog ID at LastSentAt
+}
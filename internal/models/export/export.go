@@ -0,0 +1,29 @@
+package export
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobStatus tracks the progress of an asynchronous data export
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is an asynchronous personal data export for a large account
+type Job struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AuthorID    primitive.ObjectID  `bson:"author_id" json:"author_id"`
+	Status      JobStatus           `bson:"status" json:"status"`
+	FileID      *primitive.ObjectID `bson:"file_id,omitempty" json:"-"` // GridFS file holding the ZIP archive
+	Size        int64               `bson:"size,omitempty" json:"size,omitempty"`
+	Error       string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // When the job and its archive are cleaned up
+}
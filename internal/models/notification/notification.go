@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Type is what a notification is about
+type Type string
+
+const (
+	TypeComment Type = "comment" // Someone commented on the recipient's post
+	TypeLike    Type = "like"    // Someone liked the recipient's post
+	TypeShare   Type = "share"   // Someone shared the recipient's post
+	TypeMention Type = "mention" // Someone mentioned the recipient in a post or comment
+)
+
+// Types lists every notification type
+var Types = []Type{TypeComment, TypeLike, TypeShare, TypeMention}
+
+// Valid reports whether t is a known notification type
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification tells an author about activity that concerns them
+type Notification struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	RecipientID primitive.ObjectID  `bson:"recipient_id" json:"recipient_id"`
+	Type        Type                `bson:"type" json:"type"`
+	ActorID     *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Registered author who caused it, nil for anonymous readers
+	ActorName   string              `bson:"actor_name" json:"actor_name"`
+	BlogID      *primitive.ObjectID `bson:"blog_id,omitempty" json:"blog_id,omitempty"`
+	CommentID   *primitive.ObjectID `bson:"comment_id,omitempty" json:"comment_id,omitempty"`
+	Platform    string              `bson:"platform,omitempty" json:"platform,omitempty"` // Where a post was shared
+	Key         string              `bson:"key,omitempty" json:"-"`                       // Stops the same event notifying twice, e.g. a post liked again after an unlike
+	Read        bool                `bson:"read" json:"read"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	ReadAt      *time.Time          `bson:"read_at,omitempty" json:"read_at,omitempty"`
+}
+
+// Preferences records which notification types an author has turned off.
+// Every type is on until the author mutes it.
+type Preferences struct {
+	AuthorID primitive.ObjectID `bson:"_id" json:"-"`
+	Muted    []Type             `bson:"muted" json:"muted"`
+}
+
+// Enabled reports whether the author wants notifications of type t
+func (p *Preferences) Enabled(t Type) bool {
+	if p == nil {
+		return true
+	}
+	for _, m := range p.Muted {
+		if m == t {
+			return false
+		}
+	}
+	return true
+}
+
+// Settings returns every type with whether it is on
+func (p *Preferences) Settings() map[Type]bool {
+	settings := make(map[Type]bool, len(Types))
+	for _, t := range Types {
+		settings[t] = p.Enabled(t)
+	}
+	return settings
+}
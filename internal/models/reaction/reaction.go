@@ -0,0 +1,55 @@
+package reaction
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kind is one of the emoji reactions readers can leave
+type Kind string
+
+const (
+	KindLike       Kind = "like"
+	KindLove       Kind = "love"
+	KindLaugh      Kind = "laugh"
+	KindCelebrate  Kind = "celebrate"
+	KindInsightful Kind = "insightful"
+	KindSad        Kind = "sad"
+)
+
+// Emoji maps each reaction to the emoji shown for it
+var Emoji = map[Kind]string{
+	KindLike:       "👍",
+	KindLove:       "❤️",
+	KindLaugh:      "😂",
+	KindCelebrate:  "🎉",
+	KindInsightful: "💡",
+	KindSad:        "😢",
+}
+
+// Valid reports whether k is a known reaction
+func (k Kind) Valid() bool {
+	_, ok := Emoji[k]
+	return ok
+}
+
+// TargetType is the kind of content a reaction is left on
+type TargetType string
+
+const (
+	TargetBlog    TargetType = "blog"
+	TargetComment TargetType = "comment"
+)
+
+// Reaction records one reader reacting to a blog or comment
+type Reaction struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TargetType  TargetType          `bson:"target_type" json:"target_type"`
+	TargetID    primitive.ObjectID  `bson:"target_id" json:"target_id"`
+	Kind        Kind                `bson:"kind" json:"kind"`
+	Reactor     string              `bson:"reactor" json:"-"`                               // Identity key, unique per reader
+	ReactorName string              `bson:"reactor_name" json:"name"`                       // Display name of the reader
+	AuthorID    *primitive.ObjectID `bson:"author_id,omitempty" json:"author_id,omitempty"` // Registered author who reacted, nil for anonymous readers
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
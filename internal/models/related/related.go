@@ -0,0 +1,23 @@
+package related
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPosts is how many related posts are kept for each post
+const MaxPosts = 20
+
+// Match is a post related to another, with how closely
+type Match struct {
+	BlogID primitive.ObjectID `bson:"blog_id" json:"blog_id"`
+	Score  float64            `bson:"score" json:"score"` // Between 0 and 1, higher is more similar
+}
+
+// Related holds the precomputed related posts of one post, best first
+type Related struct {
+	BlogID     primitive.ObjectID `bson:"_id" json:"blog_id"`
+	Posts      []Match            `bson:"posts" json:"posts"`
+	ComputedAt time.Time          `bson:"computed_at" json:"computed_at"`
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TargetType is the kind of content being reported
+type TargetType string
+
+const (
+	TargetBlog    TargetType = "blog"
+	TargetComment TargetType = "comment"
+	TargetAuthor  TargetType = "author"
+)
+
+// Valid reports whether t is a reportable kind of content
+func (t TargetType) Valid() bool {
+	switch t {
+	case TargetBlog, TargetComment, TargetAuthor:
+		return true
+	}
+	return false
+}
+
+// Reason is the category a reporter picks
+type Reason string
+
+const (
+	ReasonSpam           Reason = "spam"
+	ReasonHarassment     Reason = "harassment"
+	ReasonHateSpeech     Reason = "hate_speech"
+	ReasonPlagiarism     Reason = "plagiarism"
+	ReasonMisinformation Reason = "misinformation"
+	ReasonOther          Reason = "other"
+)
+
+// Valid reports whether r is a known reason category
+func (r Reason) Valid() bool {
+	switch r {
+	case ReasonSpam, ReasonHarassment, ReasonHateSpeech, ReasonPlagiarism, ReasonMisinformation, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Status is where a report is in triage
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusResolved  Status = "resolved"  // A moderator acted on the content
+	StatusDismissed Status = "dismissed" // A moderator found nothing wrong
+)
+
+// Valid reports whether s is a known report status
+func (s Status) Valid() bool {
+	switch s {
+	case StatusOpen, StatusResolved, StatusDismissed:
+		return true
+	}
+	return false
+}
+
+// Report is a reader's complaint about a blog, comment or author
+type Report struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TargetType TargetType          `bson:"target_type" json:"target_type"`
+	TargetID   primitive.ObjectID  `bson:"target_id" json:"target_id"`
+	Reason     Reason              `bson:"reason" json:"reason"`
+	Details    string              `bson:"details,omitempty" json:"details,omitempty"` // Free text from the reporter
+	ReporterID primitive.ObjectID  `bson:"reporter_id" json:"reporter_id"`
+	Status     Status              `bson:"status" json:"status"`
+	ResolvedBy *primitive.ObjectID `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"` // Moderator who closed the report
+	Note       string              `bson:"note,omitempty" json:"note,omitempty"`               // Moderator's note when closing
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	ResolvedAt *time.Time          `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
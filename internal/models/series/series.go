@@ -0,0 +1,32 @@
+package series
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrPostInSeries is returned when a post is saved into a second series
+var ErrPostInSeries = errors.New("post already belongs to another series")
+
+// Series groups posts into an ordered, multi-part collection
+type Series struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	AuthorID    primitive.ObjectID   `bson:"author_id" json:"author_id"` // Owner of the series
+	Title       string               `bson:"title" json:"title"`
+	Description string               `bson:"description" json:"description"`
+	PostIDs     []primitive.ObjectID `bson:"post_ids" json:"post_ids"` // Posts in reading order
+	CreatedAt   time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// IndexOf returns the position of a post in the series, or -1
+func (s *Series) IndexOf(postID primitive.ObjectID) int {
+	for i, id := range s.PostIDs {
+		if id == postID {
+			return i
+		}
+	}
+	return -1
+}
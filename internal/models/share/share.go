@@ -2,6 +2,7 @@ package models
 
 import (
     "go.mongodb.org/mongo-driver/bson/primitive"
+    "strings"
     "time"
 )
 
@@ -9,7 +10,18 @@ import (
 type Share struct {
     ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
     BlogID    primitive.ObjectID `bson:"blog_id" json:"blog_id"`       // Blog being shared
-    Platform  string             `bson:"platform" json:"platform"`     // e.g., Twitter, Facebook
+    Platform  string             `bson:"platform" json:"platform"`     // One of the allowed platforms, lowercase
     CreatedAt time.Time          `bson:"created_at" json:"created_at"` // Timestamp of the share
 }
 
+// DefaultPlatforms are the platforms a share may name when none are configured
+var DefaultPlatforms = []string{
+    "twitter", "facebook", "linkedin", "reddit", "hackernews", "mastodon",
+    "bluesky", "whatsapp", "telegram", "email", "copy_link",
+}
+
+// NormalizePlatform trims and lowercases a platform name so "Twitter" and
+// "twitter " count as the same platform
+func NormalizePlatform(p string) string {
+    return strings.ToLower(strings.TrimSpace(p))
+}
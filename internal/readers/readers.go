@@ -0,0 +1,137 @@
+// Package readers decides which post views count towards a post's total
+// views and unique readers.
+package readers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultBots are user agent fragments of crawlers, link previewers,
+// monitors and scripted clients. Matching is case-insensitive.
+var DefaultBots = []string{
+	"bot", "crawler", "spider", "slurp", "archiver", "facebookexternalhit",
+	"embedly", "quora link preview", "whatsapp", "skypeuripreview",
+	"headlesschrome", "lighthouse", "prerender", "pingdom", "uptime",
+	"curl/", "wget/", "python-requests", "go-http-client", "okhttp", "axios/",
+}
+
+// DefaultWindow is how long a reader is counted once per post
+const DefaultWindow = 24 * time.Hour
+
+// DefaultRetention is how long visits are kept once their window ends
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Settings tune reader counting
+type Settings struct {
+	Salt         string        // Secret mixed into anonymous visitor hashes; random per process when empty
+	Window       time.Duration // A visitor counts as one unique reader per post within each window
+	IgnoreAgents []string      // Further user agent fragments to ignore, such as our own server-side renderer
+	Retention    time.Duration // How long visits are kept after their window ends, for working out co-readership
+}
+
+// Store remembers which visitors have been counted
+type Store interface {
+	// RecordVisit stores the visitor for the post and window, reporting
+	// whether this is their first visit in it
+	RecordVisit(ctx context.Context, blogID primitive.ObjectID, visitor string, window, expires time.Time) (bool, error)
+}
+
+// Visit is a single fetch of a post
+type Visit struct {
+	BlogID    primitive.ObjectID
+	UserID    *primitive.ObjectID // Logged-in reader, if any
+	IP        string
+	UserAgent string
+}
+
+// Result says how a visit was counted
+type Result struct {
+	Counted bool // Adds to the total views
+	Unique  bool // Adds to the unique readers
+}
+
+// Counter filters and deduplicates post views
+type Counter struct {
+	salt      string
+	window    time.Duration
+	retention time.Duration
+	ignore    []string
+	store     Store
+}
+
+func NewCounter(settings Settings, store Store) *Counter {
+	salt := settings.Salt
+	if salt == "" {
+		buf := make([]byte, 32)
+		_, _ = rand.Read(buf)
+		salt = hex.EncodeToString(buf)
+		log.Println("⚠️ No reader salt configured; anonymous readers may be counted again after a restart")
+	}
+	window := settings.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	retention := settings.Retention
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	ignore := make([]string, 0, len(DefaultBots)+len(settings.IgnoreAgents))
+	for _, a := range append(DefaultBots, settings.IgnoreAgents...) {
+		ignore = append(ignore, strings.ToLower(a))
+	}
+
+	return &Counter{salt: salt, window: window, retention: retention, ignore: ignore, store: store}
+}
+
+// IsBot reports whether the user agent belongs to a crawler or other
+// automated client. Requests without a user agent are treated as bots.
+func (c *Counter) IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, fragment := range c.ignore {
+		if strings.Contains(ua, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Count records a visit. Bots are not counted at all; everyone else adds a
+// view, and a reader only adds to the unique count on their first visit to
+// the post within the current window.
+func (c *Counter) Count(ctx context.Context, v Visit, now time.Time) (Result, error) {
+	if c.IsBot(v.UserAgent) {
+		return Result{}, nil
+	}
+
+	window := now.UTC().Truncate(c.window)
+	unique, err := c.store.RecordVisit(ctx, v.BlogID, c.visitor(v, window), window, window.Add(c.window+c.retention))
+	if err != nil {
+		return Result{Counted: true}, err
+	}
+	return Result{Counted: true, Unique: unique}, nil
+}
+
+// visitor identifies the reader: logged-in readers by account, everyone
+// else by a salted hash of their address and browser. The window is part
+// of the hash so the same person cannot be followed from one window to the
+// next.
+func (c *Counter) visitor(v Visit, window time.Time) string {
+	if v.UserID != nil {
+		return "u:" + v.UserID.Hex()
+	}
+	sum := sha256.Sum256([]byte(c.salt + "|" + window.Format(time.RFC3339) + "|" + v.IP + "|" + v.UserAgent))
+	return "h:" + hex.EncodeToString(sum[:])
+}
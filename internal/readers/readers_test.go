@@ -0,0 +1,79 @@
+package readers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps visits in a set
+type memoryStore map[string]bool
+
+func (s memoryStore) RecordVisit(ctx context.Context, blogID primitive.ObjectID, visitor string, window, expires time.Time) (bool, error) {
+	key := blogID.Hex() + visitor + window.String()
+	if s[key] {
+		return false, nil
+	}
+	s[key] = true
+	return true, nil
+}
+
+const browser = "Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"
+
+func TestCount(t *testing.T) {
+	now := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	blogID := primitive.NewObjectID()
+	ctx := context.Background()
+
+	t.Run("repeat visits in a window count once", func(t *testing.T) {
+		c := NewCounter(Settings{Salt: "s", Window: 24 * time.Hour}, memoryStore{})
+		v := Visit{BlogID: blogID, IP: "10.0.0.1", UserAgent: browser}
+
+		first, _ := c.Count(ctx, v, now)
+		again, _ := c.Count(ctx, v, now.Add(time.Hour))
+		assert.Equal(t, Result{Counted: true, Unique: true}, first)
+		assert.Equal(t, Result{Counted: true}, again)
+
+		nextDay, _ := c.Count(ctx, v, now.Add(24*time.Hour))
+		assert.True(t, nextDay.Unique)
+	})
+
+	t.Run("different browsers on one address are different readers", func(t *testing.T) {
+		c := NewCounter(Settings{Salt: "s"}, memoryStore{})
+		a, _ := c.Count(ctx, Visit{BlogID: blogID, IP: "10.0.0.1", UserAgent: browser}, now)
+		b, _ := c.Count(ctx, Visit{BlogID: blogID, IP: "10.0.0.1", UserAgent: browser + " Mobile"}, now)
+		assert.True(t, a.Unique)
+		assert.True(t, b.Unique)
+	})
+
+	t.Run("logged-in readers are counted by account", func(t *testing.T) {
+		c := NewCounter(Settings{Salt: "s"}, memoryStore{})
+		userID := primitive.NewObjectID()
+		home, _ := c.Count(ctx, Visit{BlogID: blogID, UserID: &userID, IP: "10.0.0.1", UserAgent: browser}, now)
+		phone, _ := c.Count(ctx, Visit{BlogID: blogID, UserID: &userID, IP: "10.9.9.9", UserAgent: "Safari"}, now)
+		assert.True(t, home.Unique)
+		assert.False(t, phone.Unique)
+	})
+
+	t.Run("bots and configured agents are ignored", func(t *testing.T) {
+		c := NewCounter(Settings{Salt: "s", IgnoreAgents: []string{"RazorRender"}}, memoryStore{})
+		for _, ua := range []string{"", "Googlebot/2.1", "curl/8.0", "razorrender/1.0"} {
+			res, _ := c.Count(ctx, Visit{BlogID: blogID, IP: "10.0.0.1", UserAgent: ua}, now)
+			assert.Equal(t, Result{}, res, ua)
+		}
+	})
+}
+
+func TestVisitorHashHidesAddress(t *testing.T) {
+	c := NewCounter(Settings{Salt: "s"}, memoryStore{})
+	window := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	v := Visit{IP: "10.0.0.1", UserAgent: browser}
+
+	id := c.visitor(v, window)
+	assert.NotContains(t, id, "10.0.0.1")
+	assert.NotEqual(t, id, c.visitor(v, window.Add(24*time.Hour)))
+	assert.NotEqual(t, id, NewCounter(Settings{Salt: "other"}, memoryStore{}).visitor(v, window))
+}
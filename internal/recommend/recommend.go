@@ -0,0 +1,189 @@
+// Package recommend finds related posts from shared tags and category,
+// co-readership and the similarity of their text
+package recommend
+
+import (
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/related"
+)
+
+// Post is what recommendations are computed from
+type Post struct {
+	ID       primitive.ObjectID
+	Category string
+	Tags     []string
+	Title    string
+	Text     string // Content and sections
+}
+
+// Weights say how much each signal contributes to a pair's score. They
+// should add up to 1 so scores stay between 0 and 1.
+type Weights struct {
+	Tags      float64 // Overlap of the two posts' tags
+	Category  float64 // Same category
+	CoReaders float64 // Readers who read both posts
+	Text      float64 // TF-IDF cosine similarity of title and text
+}
+
+// DefaultWeights rely on content and readers about equally
+var DefaultWeights = Weights{Tags: 0.3, Category: 0.1, CoReaders: 0.3, Text: 0.3}
+
+// Pairs scoring below this are not considered related
+const minScore = 0.05
+
+// Related returns up to limit related posts for each post, best first.
+// Sharing a category only adds to the score of posts that are related in
+// some other way, so big categories do not make everything related.
+func Related(posts []Post, co *CoReadership, w Weights, limit int) map[primitive.ObjectID][]related.Match {
+	byID := make(map[primitive.ObjectID]*Post, len(posts))
+	docs := make(map[primitive.ObjectID][]string, len(posts))
+	tagIndex := map[string][]primitive.ObjectID{}
+	for i := range posts {
+		p := &posts[i]
+		byID[p.ID] = p
+		// The title says more about the subject than any sentence in the body
+		title := tokenize(p.Title)
+		docs[p.ID] = append(append(title, title...), tokenize(p.Text)...)
+		for _, t := range p.Tags {
+			tagIndex[t] = append(tagIndex[t], p.ID)
+		}
+	}
+
+	scores := map[pair]*signals{}
+	get := func(a, b primitive.ObjectID) *signals {
+		k := newPair(a, b)
+		s, ok := scores[k]
+		if !ok {
+			s = &signals{}
+			scores[k] = s
+		}
+		return s
+	}
+
+	// Text: accumulate dot products through an inverted index
+	vectors := tfidf(docs)
+	termIndex := map[string][]primitive.ObjectID{}
+	for id, v := range vectors {
+		for t := range v {
+			termIndex[t] = append(termIndex[t], id)
+		}
+	}
+	for t, ids := range termIndex {
+		for i := 0; i < len(ids); i++ {
+			for j := i + 1; j < len(ids); j++ {
+				get(ids[i], ids[j]).text += vectors[ids[i]][t] * vectors[ids[j]][t]
+			}
+		}
+	}
+
+	// Tags: count shared tags, turned into overlap below
+	for _, ids := range tagIndex {
+		for i := 0; i < len(ids); i++ {
+			for j := i + 1; j < len(ids); j++ {
+				get(ids[i], ids[j]).sharedTags++
+			}
+		}
+	}
+
+	// Co-readership between posts that still exist
+	if co != nil {
+		for k := range co.shared {
+			if byID[k.a] != nil && byID[k.b] != nil {
+				get(k.a, k.b).coReaders = co.Similarity(k.a, k.b)
+			}
+		}
+	}
+
+	matches := map[primitive.ObjectID][]related.Match{}
+	for k, s := range scores {
+		a, b := byID[k.a], byID[k.b]
+		score := w.Text*s.text + w.CoReaders*s.coReaders
+		if s.sharedTags > 0 {
+			union := len(a.Tags) + len(b.Tags) - s.sharedTags
+			score += w.Tags * float64(s.sharedTags) / float64(union)
+		}
+		if a.Category != "" && a.Category == b.Category {
+			score += w.Category
+		}
+		score = math.Min(score, 1)
+		if score < minScore {
+			continue
+		}
+		matches[a.ID] = append(matches[a.ID], related.Match{BlogID: b.ID, Score: score})
+		matches[b.ID] = append(matches[b.ID], related.Match{BlogID: a.ID, Score: score})
+	}
+
+	for id, m := range matches {
+		sort.Slice(m, func(i, j int) bool {
+			if m[i].Score != m[j].Score {
+				return m[i].Score > m[j].Score
+			}
+			return m[i].BlogID.Hex() < m[j].BlogID.Hex()
+		})
+		if len(m) > limit {
+			m = m[:limit]
+		}
+		matches[id] = m
+	}
+	return matches
+}
+
+// signals collects the evidence that two posts are related
+type signals struct {
+	text       float64
+	sharedTags int
+	coReaders  float64
+}
+
+// pair is an unordered pair of posts
+type pair struct{ a, b primitive.ObjectID }
+
+func newPair(a, b primitive.ObjectID) pair {
+	if b.Hex() < a.Hex() {
+		a, b = b, a
+	}
+	return pair{a, b}
+}
+
+// Readers who opened more posts than this in one sitting are most likely
+// crawling the site and would make every post look related
+const maxSessionPosts = 30
+
+// CoReadership counts how many readers each post has and how many read
+// each pair of posts in the same sitting
+type CoReadership struct {
+	readers map[primitive.ObjectID]int
+	shared  map[pair]int
+}
+
+func NewCoReadership() *CoReadership {
+	return &CoReadership{readers: map[primitive.ObjectID]int{}, shared: map[pair]int{}}
+}
+
+// AddSession records the posts one reader read within one counting window
+func (c *CoReadership) AddSession(blogIDs []primitive.ObjectID) {
+	if len(blogIDs) > maxSessionPosts {
+		return
+	}
+	for i, a := range blogIDs {
+		c.readers[a]++
+		for _, b := range blogIDs[i+1:] {
+			if a != b {
+				c.shared[newPair(a, b)]++
+			}
+		}
+	}
+}
+
+// Similarity is the cosine similarity of the two posts' reader sets
+func (c *CoReadership) Similarity(a, b primitive.ObjectID) float64 {
+	shared := c.shared[newPair(a, b)]
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / math.Sqrt(float64(c.readers[a])*float64(c.readers[b]))
+}
@@ -0,0 +1,77 @@
+package recommend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/related"
+)
+
+func ids(matches []related.Match) []primitive.ObjectID {
+	out := make([]primitive.ObjectID, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.BlogID)
+	}
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize(`<p>Scaling <b>Postgres</b> with the [read replicas](https://example.com/x) we've used</p>`)
+	assert.Equal(t, []string{"scaling", "postgres", "read", "replicas"}, got)
+}
+
+func TestRelated(t *testing.T) {
+	postgres := Post{ID: primitive.NewObjectID(), Category: "databases", Tags: []string{"postgres", "scaling"},
+		Title: "Scaling Postgres reads", Text: "Read replicas and connection pooling let Postgres serve more reads."}
+	replicas := Post{ID: primitive.NewObjectID(), Category: "databases", Tags: []string{"postgres"},
+		Title: "Postgres replicas in practice", Text: "Replication lag on read replicas and how pooling hides it."}
+	hiring := Post{ID: primitive.NewObjectID(), Category: "team",
+		Title: "How we hire engineers", Text: "Interviews, take-home exercises and onboarding buddies."}
+	onboarding := Post{ID: primitive.NewObjectID(), Category: "team",
+		Title: "Onboarding week", Text: "A new engineer's first week: buddies, docs and a first deploy."}
+	posts := []Post{postgres, replicas, hiring, onboarding}
+
+	t.Run("content and tags", func(t *testing.T) {
+		got := Related(posts, nil, DefaultWeights, 5)
+		assert.Equal(t, []primitive.ObjectID{replicas.ID}, ids(got[postgres.ID]))
+		assert.Equal(t, []primitive.ObjectID{postgres.ID}, ids(got[replicas.ID]))
+		assert.NotContains(t, ids(got[hiring.ID]), postgres.ID)
+	})
+
+	t.Run("co-readership links otherwise unrelated posts", func(t *testing.T) {
+		co := NewCoReadership()
+		for i := 0; i < 5; i++ {
+			co.AddSession([]primitive.ObjectID{postgres.ID, hiring.ID})
+		}
+		got := Related(posts, co, DefaultWeights, 5)
+		assert.Contains(t, ids(got[postgres.ID]), hiring.ID)
+	})
+
+	t.Run("limit keeps the best matches", func(t *testing.T) {
+		got := Related(posts, nil, Weights{Category: 0.5, Text: 0.5}, 1)
+		for _, m := range got {
+			assert.Len(t, m, 1)
+		}
+	})
+}
+
+func TestCoReadership(t *testing.T) {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	co := NewCoReadership()
+	co.AddSession([]primitive.ObjectID{a, b})
+	co.AddSession([]primitive.ObjectID{a})
+
+	assert.InDelta(t, 0.7071, co.Similarity(a, b), 1e-4)
+	assert.Equal(t, co.Similarity(a, b), co.Similarity(b, a))
+	assert.Zero(t, co.Similarity(a, c))
+
+	crawl := make([]primitive.ObjectID, maxSessionPosts+1)
+	for i := range crawl {
+		crawl[i] = primitive.NewObjectID()
+	}
+	crawl[0], crawl[1] = a, c
+	co.AddSession(crawl)
+	assert.Zero(t, co.Similarity(a, c))
+}
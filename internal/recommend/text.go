@@ -0,0 +1,126 @@
+package recommend
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Terms kept per post; the rest add little but cost comparisons
+const maxTerms = 64
+
+var markupPattern = regexp.MustCompile(`<[^>]*>|\]\([^)]*\)|https?://\S+`)
+
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		about above after again against all also and any are because been before being below
+		between both but can could did does doing down during each few for from further had
+		has have having her here hers herself him himself his how into its itself just more
+		most not now off once only other our ours ourselves out over own same she should
+		some such than that the their theirs them themselves then there these they this
+		those through too under until very was were what when where which while who whom
+		why will with would you your yours yourself yourselves use using used one two new
+		like get make way well may might must also can't don't it's we're we've i'm`) {
+		stopwords[w] = true
+	}
+}
+
+// tokenize splits text into lowercase words, leaving out markup, links,
+// stopwords and very short words
+func tokenize(text string) []string {
+	text = markupPattern.ReplaceAllString(text, " ")
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	tokens := words[:0]
+	for _, w := range words {
+		w = strings.Trim(w, "'")
+		if len(w) < 3 || stopwords[w] {
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+// vector is a unit-length TF-IDF vector
+type vector map[string]float64
+
+// tfidf weighs each post's terms by how often they appear in it and how
+// rare they are across all posts
+func tfidf(docs map[primitive.ObjectID][]string) map[primitive.ObjectID]vector {
+	df := map[string]int{}
+	for _, tokens := range docs {
+		seen := map[string]bool{}
+		for _, t := range tokens {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make(map[primitive.ObjectID]vector, len(docs))
+	for id, tokens := range docs {
+		tf := map[string]float64{}
+		for _, t := range tokens {
+			tf[t]++
+		}
+
+		v := vector{}
+		for t, count := range tf {
+			// Terms in every post say nothing about similarity
+			if w := (count / float64(len(tokens))) * math.Log(n/float64(df[t])); w > 0 {
+				v[t] = w
+			}
+		}
+		vectors[id] = normalize(strongest(v, maxTerms))
+	}
+	return vectors
+}
+
+// strongest keeps the n highest weighted terms
+func strongest(v vector, n int) vector {
+	if len(v) <= n {
+		return v
+	}
+	terms := make([]string, 0, len(v))
+	for t := range v {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if v[terms[i]] != v[terms[j]] {
+			return v[terms[i]] > v[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+
+	kept := make(vector, n)
+	for _, t := range terms[:n] {
+		kept[t] = v[t]
+	}
+	return kept
+}
+
+func normalize(v vector) vector {
+	norm := 0.0
+	for _, w := range v {
+		norm += w * w
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	for t := range v {
+		v[t] /= norm
+	}
+	return v
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/blog"
+)
+
+// AnalyticsRepository keeps hourly and daily activity counters per post
+type AnalyticsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAnalyticsRepository(db *mongo.Database) *AnalyticsRepository {
+	return &AnalyticsRepository{collection: db.Collection("post_analytics")}
+}
+
+// EnsureIndexes keeps one bucket per post, granularity and start, and
+// drops hourly buckets once they are past retention
+func (r *AnalyticsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "granularity", Value: 1}, {Key: "start", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+// Record adds the event to the post's hourly and daily buckets
+func (r *AnalyticsRepository) Record(ctx context.Context, e analytics.Event) error {
+	inc := bson.M{}
+	for _, m := range analytics.Metrics {
+		if n := e.Counts.Get(m); n != 0 {
+			inc["counts."+string(m)] = n
+		}
+	}
+	if e.Counts.Shares != 0 && e.Platform != "" {
+		inc["platforms."+analytics.Key(strings.ToLower(e.Platform))] = e.Counts.Shares
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, 2)
+	for _, g := range []analytics.Granularity{analytics.Hourly, analytics.Daily} {
+		start := g.Start(e.At)
+		update := bson.M{"$inc": inc}
+		if g == analytics.Hourly {
+			update["$setOnInsert"] = bson.M{"expires_at": start.Add(analytics.HourlyRetention)}
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"blog_id": e.BlogID, "granularity": g, "start": start}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+
+	if e.Counts.Views != 0 && e.Referrer != "" {
+		return r.recordReferrer(ctx, e)
+	}
+	return nil
+}
+
+// recordReferrer adds the event's views to its referring site in the
+// post's hourly and daily buckets, which Record has already created. A
+// bucket that names MaxReferrers sites counts further sites as
+// OtherReferrer, so it cannot grow without bound.
+func (r *AnalyticsRepository) recordReferrer(ctx context.Context, e analytics.Event) error {
+	key := "referrers." + analytics.Key(e.Referrer)
+	named := bson.M{"$expr": bson.M{"$lt": bson.A{
+		bson.M{"$size": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$referrers", bson.M{}}}}},
+		analytics.MaxReferrers,
+	}}}
+
+	for _, g := range []analytics.Granularity{analytics.Hourly, analytics.Daily} {
+		bucket := bson.M{"blog_id": e.BlogID, "granularity": g, "start": g.Start(e.At)}
+		res, err := r.collection.UpdateOne(ctx,
+			bson.M{"$and": bson.A{bucket, bson.M{"$or": bson.A{bson.M{key: bson.M{"$exists": true}}, named}}}},
+			bson.M{"$inc": bson.M{key: e.Counts.Views}},
+		)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount > 0 {
+			continue
+		}
+		if _, err := r.collection.UpdateOne(ctx, bucket,
+			bson.M{"$inc": bson.M{"referrers." + analytics.OtherReferrer: e.Counts.Views}},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketsIn matches the buckets of the given posts that start within
+// [from, to)
+func bucketsIn(blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time) bson.D {
+	return bson.D{{Key: "$match", Value: bson.M{
+		"blog_id":     bson.M{"$in": blogIDs},
+		"granularity": g,
+		"start":       bson.M{"$gte": from, "$lt": to},
+	}}}
+}
+
+// sumCounts is a $group stage adding up every metric
+func sumCounts(id interface{}) bson.D {
+	group := bson.M{"_id": id}
+	for _, m := range analytics.Metrics {
+		group[string(m)] = bson.M{"$sum": "$counts." + string(m)}
+	}
+	return bson.D{{Key: "$group", Value: group}}
+}
+
+// Series adds up the posts' activity per bucket, oldest first. Buckets
+// without any activity are left out.
+func (r *AnalyticsRepository) Series(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time) ([]analytics.Point, error) {
+	points := []analytics.Point{}
+	if len(blogIDs) == 0 {
+		return points, nil
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		bucketsIn(blogIDs, g, from, to),
+		sumCounts("$start"),
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// Breakdown adds up a per-label map of the posts' buckets, "referrers" or
+// "platforms", over the range
+func (r *AnalyticsRepository) Breakdown(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) (map[string]int, error) {
+	totals := map[string]int{}
+	if len(blogIDs) == 0 {
+		return totals, nil
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		bucketsIn(blogIDs, g, from, to),
+		{{Key: "$project", Value: bson.M{"entry": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$" + field, bson.M{}}}}}}},
+		{{Key: "$unwind", Value: "$entry"}},
+		{{Key: "$group", Value: bson.M{"_id": "$entry.k", "count": bson.M{"$sum": "$entry.v"}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			Key   string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		totals[analytics.Label(row.Key)] = row.Count
+	}
+	return totals, cursor.Err()
+}
+
+// BreakdownSeries is Breakdown per bucket, oldest first. Buckets without
+// any entries are left out.
+func (r *AnalyticsRepository) BreakdownSeries(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) ([]analytics.LabelPoint, error) {
+	points := []analytics.LabelPoint{}
+	if len(blogIDs) == 0 {
+		return points, nil
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		bucketsIn(blogIDs, g, from, to),
+		{{Key: "$project", Value: bson.M{"start": 1, "entry": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$" + field, bson.M{}}}}}}},
+		{{Key: "$unwind", Value: "$entry"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"start": "$start", "key": "$entry.k"},
+			"count": bson.M{"$sum": "$entry.v"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$_id.start",
+			"entries": bson.M{"$push": bson.M{"key": "$_id.key", "count": "$count"}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			Start   time.Time `bson:"_id"`
+			Entries []struct {
+				Key   string `bson:"key"`
+				Count int    `bson:"count"`
+			} `bson:"entries"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		p := analytics.LabelPoint{Start: row.Start, Counts: make(map[string]int, len(row.Entries))}
+		for _, e := range row.Entries {
+			p.Counts[analytics.Label(e.Key)] = e.Count
+		}
+		points = append(points, p)
+	}
+	return points, cursor.Err()
+}
+
+// TopPosts ranks the posts by a metric over the range
+func (r *AnalyticsRepository) TopPosts(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, by analytics.Metric, limit int64) ([]analytics.PostTotals, error) {
+	top := []analytics.PostTotals{}
+	if len(blogIDs) == 0 {
+		return top, nil
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		bucketsIn(blogIDs, g, from, to),
+		sumCounts("$blog_id"),
+		{{Key: "$sort", Value: bson.D{{Key: string(by), Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &top); err != nil {
+		return nil, err
+	}
+	return top, nil
+}
+
+// ForEachBucket calls fn with every bucket of the granularity that started
+// at or after from, stopping at the first error
+func (r *AnalyticsRepository) ForEachBucket(ctx context.Context, g analytics.Granularity, from time.Time, fn func(*analytics.Bucket) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{"granularity": g, "start": bson.M{"$gte": from}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var b analytics.Bucket
+		if err := cursor.Decode(&b); err != nil {
+			return err
+		}
+		if err := fn(&b); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Popular ranks listed posts, optionally of one type, by their weighted
+// engagement in the buckets starting within [from, to)
+func (r *AnalyticsRepository) Popular(ctx context.Context, g analytics.Granularity, from, to time.Time, weights map[analytics.Metric]float64, docType blog.DocumentType, limit int64) ([]analytics.PostTotals, error) {
+	terms := bson.A{}
+	for m, w := range weights {
+		terms = append(terms, bson.M{"$multiply": bson.A{"$" + string(m), w}})
+	}
+	blogFilter := bson.M{}
+	if docType != "" {
+		blogFilter["type"] = docType
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"granularity": g, "start": bson.M{"$gte": from, "$lt": to}}}},
+		sumCounts("$blog_id"),
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$add": terms}}}},
+		{{Key: "$match", Value: bson.M{"score": bson.M{"$gt": 0}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "blogs",
+			"let":  bson.M{"id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$id"}}}},
+				bson.M{"$match": listed(blogFilter)},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "blog",
+		}}},
+		{{Key: "$match", Value: bson.M{"blog": bson.M{"$ne": bson.A{}}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	top := []analytics.PostTotals{}
+	if err := cursor.All(ctx, &top); err != nil {
+		return nil, err
+	}
+	return top, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"razorblog-backend/internal/models/analytics"
+)
+
+func TestAnalyticsRecord_Referrers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	updated := func(n int) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+	view := analytics.Event{
+		BlogID:   primitive.NewObjectID(),
+		At:       time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Counts:   analytics.Counts{Views: 1},
+		Referrer: "spam.example",
+	}
+
+	// referrerIncs returns the referrer keys incremented after the counts
+	referrerIncs := func(mt *mtest.T) []string {
+		mt.GetStartedEvent() // counts
+		var keys []string
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			inc := e.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$inc").Document()
+			keys = append(keys, inc.Index(0).Key())
+		}
+		return keys
+	}
+
+	mt.Run("Names the referring site while the bucket has room", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(2), updated(1), updated(1))
+
+		assert.NoError(mt, NewAnalyticsRepository(mt.DB).Record(context.Background(), view))
+		assert.Equal(mt, []string{"referrers.spam．example", "referrers.spam．example"}, referrerIncs(mt))
+	})
+
+	mt.Run("Counts further sites as other once the bucket is full", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(2), updated(0), updated(1), updated(1))
+
+		assert.NoError(mt, NewAnalyticsRepository(mt.DB).Record(context.Background(), view))
+		assert.Equal(mt, []string{"referrers.spam．example", "referrers.other", "referrers.spam．example"}, referrerIncs(mt))
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/audit"
+)
+
+// AuditRepository stores the audit log
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		collection: db.Collection("audit_events"),
+	}
+}
+
+// Record appends an event to the audit log
+func (r *AuditRepository) Record(ctx context.Context, e *audit.Event) error {
+	e.ID = primitive.NewObjectID()
+	e.CreatedAt = time.Now()
+	_, err := r.collection.InsertOne(ctx, e)
+	return err
+}
+
+// ListByActor returns every event performed by an author, newest first
+func (r *AuditRepository) ListByActor(ctx context.Context, actorID primitive.ObjectID) ([]*audit.Event, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"actor_id": actorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*audit.Event
+	for cursor.Next(ctx) {
+		var e audit.Event
+		if err := cursor.Decode(&e); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
@@ -3,6 +3,8 @@ package repository
 import (
     "context"
     "errors"
+    "regexp"
+    "strings"
     "time"
 
     "razorblog-backend/internal/models/author"
@@ -64,6 +66,49 @@ func (r *AuthorRepository) GetAuthorByEmail(email string) (*author.Author, error
     return &a, nil
 }
 
+// GetAuthorByName finds an author whose name matches case-insensitively
+func (r *AuthorRepository) GetAuthorByName(name string) (*author.Author, error) {
+    var a author.Author
+    filter := bson.M{"name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(name)) + "$", Options: "i"}}
+    err := r.collection.FindOne(context.Background(), filter).Decode(&a)
+    if err != nil {
+        if errors.Is(err, mongo.ErrNoDocuments) {
+            return nil, nil
+        }
+        return nil, err
+    }
+    return &a, nil
+}
+
+// GetAuthorsByNamesOrIDs finds, in one query, the authors whose names match
+// any of names case-insensitively or whose IDs are among ids
+func (r *AuthorRepository) GetAuthorsByNamesOrIDs(names []string, ids []primitive.ObjectID) ([]*author.Author, error) {
+    var or bson.A
+    if len(names) > 0 {
+        patterns := make(bson.A, 0, len(names))
+        for _, name := range names {
+            patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(name)) + "$", Options: "i"})
+        }
+        or = append(or, bson.M{"name": bson.M{"$in": patterns}})
+    }
+    if len(ids) > 0 {
+        or = append(or, bson.M{"_id": bson.M{"$in": ids}})
+    }
+    if len(or) == 0 {
+        return nil, nil
+    }
+
+    cursor, err := r.collection.Find(context.Background(), bson.M{"$or": or})
+    if err != nil {
+        return nil, err
+    }
+    var authors []*author.Author
+    if err := cursor.All(context.Background(), &authors); err != nil {
+        return nil, err
+    }
+    return authors, nil
+}
+
 // UpdateAuthor updates an existing author
 func (r *AuthorRepository) UpdateAuthor(id primitive.ObjectID, update bson.M) error {
     update["updated_at"] = time.Now()
@@ -81,3 +126,23 @@ func (r *AuthorRepository) DeleteAuthor(id primitive.ObjectID) error {
     return err
 }
 
+
+// ForEachAuthor calls fn for every author, stopping at the first error
+func (r *AuthorRepository) ForEachAuthor(ctx context.Context, fn func(*author.Author) error) error {
+    cursor, err := r.collection.Find(ctx, bson.M{})
+    if err != nil {
+        return err
+    }
+    defer cursor.Close(ctx)
+
+    for cursor.Next(ctx) {
+        var a author.Author
+        if err := cursor.Decode(&a); err != nil {
+            return err
+        }
+        if err := fn(&a); err != nil {
+            return err
+        }
+    }
+    return cursor.Err()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestGetAuthorsByNamesOrIDs(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Looks every name and ID up in one query", func(mt *mtest.T) {
+		adaID, linkedID := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.authors", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: adaID}, {Key: "name", Value: "Ada"}},
+		))
+
+		authors, err := NewAuthorRepository(mt.DB).GetAuthorsByNamesOrIDs([]string{"ada", "a.b"}, []primitive.ObjectID{linkedID})
+
+		assert.NoError(mt, err)
+		assert.Len(mt, authors, 1)
+		assert.Equal(mt, []string{"find authors"}, commands(mt))
+	})
+
+	mt.Run("Skips the query when there is nothing to look up", func(mt *mtest.T) {
+		authors, err := NewAuthorRepository(mt.DB).GetAuthorsByNamesOrIDs(nil, nil)
+
+		assert.NoError(mt, err)
+		assert.Empty(mt, authors)
+		assert.Empty(mt, commands(mt))
+	})
+}
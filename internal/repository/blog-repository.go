@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,25 +12,69 @@ import (
 
 	"razorblog-backend/internal/models/blog"
 	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/reaction"
+	"razorblog-backend/internal/models/report"
 )
 
 type BlogRepository struct {
-	collection    *mongo.Collection
-	authorCol     *mongo.Collection
+	collection      *mongo.Collection
+	authorCol       *mongo.Collection
+	commentCol      *mongo.Collection
+	shareCol        *mongo.Collection
+	seriesCol       *mongo.Collection
+	reactionCol     *mongo.Collection
+	reportCol       *mongo.Collection
+	bookmarkCol     *mongo.Collection
+	readingListCol  *mongo.Collection
+	notificationCol *mongo.Collection
+	analyticsCol    *mongo.Collection
+	visitCol        *mongo.Collection
+	relatedCol      *mongo.Collection
 }
 
 func NewBlogRepository(db *mongo.Database) *BlogRepository {
 	return &BlogRepository{
-		collection: db.Collection("blogs"),
-		authorCol:  db.Collection("authors"),
+		collection:      db.Collection("blogs"),
+		authorCol:       db.Collection("authors"),
+		commentCol:      db.Collection("comments"),
+		shareCol:        db.Collection("shares"),
+		seriesCol:       db.Collection("series"),
+		reactionCol:     db.Collection("reactions"),
+		reportCol:       db.Collection("reports"),
+		bookmarkCol:     db.Collection("bookmarks"),
+		readingListCol:  db.Collection("reading_lists"),
+		notificationCol: db.Collection("notifications"),
+		analyticsCol:    db.Collection("post_analytics"),
+		visitCol:        db.Collection("reader_visits"),
+		relatedCol:      db.Collection("related_posts"),
 	}
 }
 
+// notDeleted narrows a filter to posts that are not in the trash
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// listed narrows a filter to posts shown in public listings: not in the
+// trash and not hidden by abuse reports
+func listed(filter bson.M) bson.M {
+	filter["hidden"] = bson.M{"$ne": true}
+	return notDeleted(filter)
+}
+
 func (r *BlogRepository) Create(ctx context.Context, b *blog.Blog) (*blog.Blog, error) {
 	b.ID = primitive.NewObjectID()
 	b.CreatedAt = time.Now()
 	b.UpdatedAt = time.Now()
 	b.Readers = 0
+	b.Views = 0
+	b.TrendingScore = 0
+	b.Reactions = nil
+	b.Shares = nil
+	b.Likes = nil
+	b.Hidden = false
+	b.DeletedAt = nil
 	_, err := r.collection.InsertOne(ctx, b)
 	if err != nil {
 		return nil, err
@@ -39,7 +84,7 @@ func (r *BlogRepository) Create(ctx context.Context, b *blog.Blog) (*blog.Blog,
 
 func (r *BlogRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*blog.Blog, error) {
 	var b blog.Blog
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&b)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&b)
 	if err != nil {
 		return nil, err
 	}
@@ -65,21 +110,190 @@ func (r *BlogRepository) Update(ctx context.Context, id primitive.ObjectID, upda
 	update["updated_at"] = time.Now()
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updatedBlog blog.Blog
-	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": update}, opts).Decode(&updatedBlog)
+	err := r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": id}), bson.M{"$set": update}, opts).Decode(&updatedBlog)
 	if err != nil {
 		return nil, err
 	}
 	return &updatedBlog, nil
 }
 
+// Delete moves a blog to the trash. It is hard-deleted by PurgeDeleted
+// once the retention period has passed.
 func (r *BlogRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+	res, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Restore takes an author's blog out of the trash
+func (r *BlogRepository) Restore(ctx context.Context, id, authorID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "author_id": authorID, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListTrash returns an author's trashed blogs, most recently deleted first
+func (r *BlogRepository) ListTrash(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		bson.M{"author_id": authorID, "deleted_at": bson.M{"$exists": true}},
+		options.Find().SetSort(bson.M{"deleted_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var blogs []*blog.Blog
+	for cursor.Next(ctx) {
+		var b blog.Blog
+		if err := cursor.Decode(&b); err != nil {
+			return nil, err
+		}
+		blogs = append(blogs, &b)
+	}
+	return blogs, nil
+}
+
+// PurgeResult reports how many documents a purge removed
+type PurgeResult struct {
+	Blogs         int64 `json:"blogs"`
+	Comments      int64 `json:"comments"`
+	Shares        int64 `json:"shares"`
+	Reactions     int64 `json:"reactions"`
+	Reports       int64 `json:"reports"`
+	Bookmarks     int64 `json:"bookmarks"`
+	Notifications int64 `json:"notifications"`
+}
+
+// PurgeDeleted hard-deletes blogs that were trashed before the cutoff,
+// along with everything that belongs to them: comments, shares, reactions
+// and reports on the posts and their comments, bookmarks, notifications,
+// analytics, reader visits and related posts. The posts are also dropped
+// from series, reading lists and other posts' related posts. Blogs go
+// last, so a purge that fails part way is finished by the next run.
+func (r *BlogRepository) PurgeDeleted(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		bson.M{"deleted_at": bson.M{"$lt": before}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	result := &PurgeResult{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	inIDs := bson.M{"$in": ids}
+	byBlog := bson.M{"blog_id": inIDs}
+
+	commentIDs, err := r.commentCol.Distinct(ctx, "_id", byBlog)
+	if err != nil {
+		return nil, err
+	}
+	// Reactions and reports name their target by type and ID
+	onTargets := func(blogType, commentType string) bson.M {
+		return bson.M{"$or": bson.A{
+			bson.M{"target_type": blogType, "target_id": inIDs},
+			bson.M{"target_type": commentType, "target_id": bson.M{"$in": commentIDs}},
+		}}
+	}
+
+	reactions, err := r.reactionCol.DeleteMany(ctx, onTargets(string(reaction.TargetBlog), string(reaction.TargetComment)))
+	if err != nil {
+		return nil, err
+	}
+	result.Reactions = reactions.DeletedCount
+
+	reports, err := r.reportCol.DeleteMany(ctx, onTargets(string(report.TargetBlog), string(report.TargetComment)))
+	if err != nil {
+		return nil, err
+	}
+	result.Reports = reports.DeletedCount
+
+	comments, err := r.commentCol.DeleteMany(ctx, byBlog)
+	if err != nil {
+		return nil, err
+	}
+	result.Comments = comments.DeletedCount
+
+	shares, err := r.shareCol.DeleteMany(ctx, byBlog)
+	if err != nil {
+		return nil, err
+	}
+	result.Shares = shares.DeletedCount
+
+	bookmarks, err := r.bookmarkCol.DeleteMany(ctx, byBlog)
+	if err != nil {
+		return nil, err
+	}
+	result.Bookmarks = bookmarks.DeletedCount
+
+	notifications, err := r.notificationCol.DeleteMany(ctx, byBlog)
+	if err != nil {
+		return nil, err
+	}
+	result.Notifications = notifications.DeletedCount
+
+	for _, col := range []*mongo.Collection{r.analyticsCol, r.visitCol} {
+		if _, err := col.DeleteMany(ctx, byBlog); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.relatedCol.DeleteMany(ctx, bson.M{"_id": inIDs}); err != nil {
+		return nil, err
+	}
+	if _, err := r.relatedCol.UpdateMany(ctx, bson.M{"posts.blog_id": inIDs}, bson.M{"$pull": bson.M{"posts": byBlog}}); err != nil {
+		return nil, err
+	}
+	if _, err := r.readingListCol.UpdateMany(ctx, bson.M{"entries.blog_id": inIDs}, bson.M{"$pull": bson.M{"entries": byBlog}}); err != nil {
+		return nil, err
+	}
+	if _, err := r.seriesCol.UpdateMany(ctx, bson.M{"post_ids": inIDs}, bson.M{"$pull": bson.M{"post_ids": inIDs}}); err != nil {
+		return nil, err
+	}
+
+	blogs, err := r.collection.DeleteMany(ctx, bson.M{"_id": inIDs})
+	if err != nil {
+		return nil, err
+	}
+	result.Blogs = blogs.DeletedCount
+
+	return result, nil
 }
 
 func (r *BlogRepository) List(ctx context.Context, limit int64, skip int64) ([]*blog.Blog, error) {
 	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, listed(bson.M{}), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -125,34 +339,211 @@ func (r *BlogRepository) GetBlogsWithAuthors(ctx context.Context, limit, skip in
 	return blogs, authorMap, nil
 }
 
-func (r *BlogRepository) IncrementReaders(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"readers": 1}})
+// CountView adds a view to the post and, for a reader's first visit in the
+// counting window, a unique reader
+func (r *BlogRepository) CountView(ctx context.Context, id primitive.ObjectID, unique bool) error {
+	inc := bson.M{"views": 1}
+	if unique {
+		inc["readers"] = 1
+	}
+	_, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), bson.M{"$inc": inc})
 	return err
 }
 
-func (r *BlogRepository) LikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) error {
-	_, err := r.collection.UpdateOne(
+// LikeBlog adds the user's like, reporting false if they already liked it
+func (r *BlogRepository) LikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) (bool, error) {
+	res, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": blogID},
+		notDeleted(bson.M{"_id": blogID}),
 		bson.M{"$addToSet": bson.M{"likes": userID}},
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
 }
 
 func (r *BlogRepository) UnlikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": blogID},
+		notDeleted(bson.M{"_id": blogID}),
 		bson.M{"$pull": bson.M{"likes": userID}},
 	)
 	return err
 }
 
-//Getting a blog by author Id
+// coAuthorUpdate applies an update to a post's co-authors and returns the
+// updated post, or mongo.ErrNoDocuments if filter matches nothing
+func (r *BlogRepository) coAuthorUpdate(ctx context.Context, filter, update bson.M, opts ...*options.FindOneAndUpdateOptions) (*blog.Blog, error) {
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = time.Now()
+
+	opts = append(opts, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	var b blog.Blog
+	if err := r.collection.FindOneAndUpdate(ctx, notDeleted(filter), update, opts...).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// InviteCoAuthor invites an author to be credited on a post. An author
+// already listed gets the new role, and a declined invitation is sent
+// again.
+func (r *BlogRepository) InviteCoAuthor(ctx context.Context, blogID, authorID primitive.ObjectID, role blog.ContributionRole) (*blog.Blog, error) {
+	now := time.Now()
+	// The author may be added or removed between the two updates, so a
+	// miss on both is retried once before the post counts as missing
+	for attempt := 0; attempt < 2; attempt++ {
+		b, err := r.coAuthorUpdate(ctx,
+			bson.M{"_id": blogID, "co_authors.author_id": authorID},
+			bson.M{
+				"$set": bson.M{
+					"co_authors.$[listed].role":         role,
+					"co_authors.$[declined].status":     blog.InvitePending,
+					"co_authors.$[declined].invited_at": now,
+				},
+				"$unset": bson.M{"co_authors.$[declined].responded_at": ""},
+			},
+			options.FindOneAndUpdate().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+				bson.M{"listed.author_id": authorID},
+				bson.M{"declined.author_id": authorID, "declined.status": blog.InviteDeclined},
+			}}),
+		)
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return b, err
+		}
+
+		b, err = r.coAuthorUpdate(ctx,
+			bson.M{"_id": blogID, "co_authors.author_id": bson.M{"$ne": authorID}},
+			bson.M{"$push": bson.M{"co_authors": blog.CoAuthor{
+				AuthorID:  authorID,
+				Role:      role,
+				Status:    blog.InvitePending,
+				InvitedAt: now,
+			}}},
+		)
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return b, err
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// RespondToInvite records an author's answer to their pending invitation.
+// It returns mongo.ErrNoDocuments if there is no pending invitation.
+func (r *BlogRepository) RespondToInvite(ctx context.Context, blogID, authorID primitive.ObjectID, status blog.InviteStatus) (*blog.Blog, error) {
+	return r.coAuthorUpdate(ctx,
+		bson.M{"_id": blogID, "co_authors": bson.M{"$elemMatch": bson.M{"author_id": authorID, "status": blog.InvitePending}}},
+		bson.M{"$set": bson.M{
+			"co_authors.$.status":       status,
+			"co_authors.$.responded_at": time.Now(),
+		}},
+	)
+}
+
+// RemoveCoAuthor takes an author off a post's co-authors. It returns
+// mongo.ErrNoDocuments if they are not listed.
+func (r *BlogRepository) RemoveCoAuthor(ctx context.Context, blogID, authorID primitive.ObjectID) (*blog.Blog, error) {
+	return r.coAuthorUpdate(ctx,
+		bson.M{"_id": blogID, "co_authors.author_id": authorID},
+		bson.M{"$pull": bson.M{"co_authors": bson.M{"author_id": authorID}}},
+	)
+}
+
+// ReorderCoAuthors replaces a post's co-authors with the same entries in a
+// new order. It returns mongo.ErrNoDocuments if the list no longer matches
+// current, so a change made meanwhile is not overwritten.
+func (r *BlogRepository) ReorderCoAuthors(ctx context.Context, blogID primitive.ObjectID, current, ordered []blog.CoAuthor) (*blog.Blog, error) {
+	return r.coAuthorUpdate(ctx,
+		bson.M{"_id": blogID, "co_authors": current},
+		bson.M{"$set": bson.M{"co_authors": ordered}},
+	)
+}
+
+// ListFeed returns the newest listed posts written or co-written by any of
+// the given authors, starting after the cursor when one is given. The feed
+// is assembled when read, so publishing costs the same however many
+// followers an author has.
+func (r *BlogRepository) ListFeed(ctx context.Context, authorIDs []primitive.ObjectID, after *blog.Cursor, limit int64) ([]*blog.Blog, error) {
+	if len(authorIDs) == 0 {
+		return []*blog.Blog{}, nil
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"author_id": bson.M{"$in": authorIDs}},
+		bson.M{"co_authors": bson.M{"$elemMatch": bson.M{
+			"author_id": bson.M{"$in": authorIDs},
+			"status":    blog.InviteAccepted,
+		}}},
+	}}
+	if after != nil {
+		// Mongo stores times to the millisecond, as do cursors
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": after.CreatedAt}},
+			bson.M{"created_at": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+		}}}}
+	}
+
+	opts := options.Find().
+		SetLimit(limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, listed(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var blogs []*blog.Blog
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// GetListedByIDs returns the posts among ids that are shown in public
+// listings, in no particular order
+func (r *BlogRepository) GetListedByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*blog.Blog, error) {
+	if len(ids) == 0 {
+		return []*blog.Blog{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, listed(bson.M{"_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var blogs []*blog.Blog
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// EnsureIndexes supports listing posts by author, newest first, which the
+// feed and author pages rely on
+func (r *BlogRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "author_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "co_authors.author_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "trending_score", Value: -1}}},
+	})
+	return err
+}
+
+//Getting a blog by author Id, including posts they co-authored
 func (r *BlogRepository) ListByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error) {
 	cursor, err := r.collection.Find(
 		ctx,
-		bson.M{"author_id": authorID},
+		listed(bson.M{"$or": bson.A{
+			bson.M{"author_id": authorID},
+			bson.M{"co_authors": bson.M{"$elemMatch": bson.M{
+				"author_id": authorID,
+				"status":    blog.InviteAccepted,
+			}}},
+		}}),
 		options.Find().SetSort(bson.M{"created_at": -1}),
 	)
 	if err != nil {
@@ -172,3 +563,124 @@ func (r *BlogRepository) ListByAuthor(ctx context.Context, authorID primitive.Ob
 	return blogs, nil
 }
 
+// ListTrending returns listed posts, optionally of one type, with the
+// highest trending scores first. Posts without a score are left out.
+func (r *BlogRepository) ListTrending(ctx context.Context, docType blog.DocumentType, limit int64) ([]*blog.Blog, error) {
+	filter := bson.M{"trending_score": bson.M{"$gt": 0}}
+	if docType != "" {
+		filter["type"] = docType
+	}
+
+	cursor, err := r.collection.Find(ctx, listed(filter),
+		options.Find().SetSort(bson.D{{Key: "trending_score", Value: -1}, {Key: "_id", Value: 1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blogs := []*blog.Blog{}
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// SetTrendingScores stores the given scores and clears the score of every
+// post not among them
+func (r *BlogRepository) SetTrendingScores(ctx context.Context, scores map[primitive.ObjectID]float64) error {
+	ids := make([]primitive.ObjectID, 0, len(scores))
+	models := make([]mongo.WriteModel, 0, len(scores))
+	for id, score := range scores {
+		ids = append(ids, id)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"trending_score": score}}))
+	}
+
+	if _, err := r.collection.UpdateMany(ctx,
+		bson.M{"trending_score": bson.M{"$exists": true}, "_id": bson.M{"$nin": ids}},
+		bson.M{"$unset": bson.M{"trending_score": ""}},
+	); err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// ForEachListed calls fn with every listed post, stopping at the first error
+func (r *BlogRepository) ForEachListed(ctx context.Context, fn func(*blog.Blog) error) error {
+	cursor, err := r.collection.Find(ctx, listed(bson.M{}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var b blog.Blog
+		if err := cursor.Decode(&b); err != nil {
+			return err
+		}
+		if err := fn(&b); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// ListAllByAuthor returns every post the author wrote or co-wrote that is
+// not in the trash, including posts hidden from readers
+func (r *BlogRepository) ListAllByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		notDeleted(bson.M{"$or": bson.A{
+			bson.M{"author_id": authorID},
+			bson.M{"co_authors": bson.M{"$elemMatch": bson.M{
+				"author_id": authorID,
+				"status":    blog.InviteAccepted,
+			}}},
+		}}),
+		options.Find().SetSort(bson.M{"created_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blogs := []*blog.Blog{}
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// ListTDDs returns TDD documents, optionally filtered by decision status
+func (r *BlogRepository) ListTDDs(ctx context.Context, status blog.DecisionStatus, limit, skip int64) ([]*blog.Blog, error) {
+	filter := listed(bson.M{"type": blog.TypeTDD})
+	if status == blog.StatusProposed {
+		// TDDs created before statuses existed have no status and count as proposed
+		filter["status"] = bson.M{"$in": bson.A{blog.StatusProposed, nil}}
+	} else if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var blogs []*blog.Blog
+	for cursor.Next(ctx) {
+		var b blog.Blog
+		if err := cursor.Decode(&b); err != nil {
+			return nil, err
+		}
+		blogs = append(blogs, &b)
+	}
+	return blogs, nil
+}
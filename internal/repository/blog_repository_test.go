@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// commands lists the command name and collection of every command the
+// mock deployment received
+func commands(mt *mtest.T) []string {
+	var sent []string
+	for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+		if e.CommandName == "endSessions" {
+			continue
+		}
+		sent = append(sent, e.CommandName+" "+e.Command.Index(0).Value().StringValue())
+	}
+	return sent
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Cascades to everything keyed by the purged posts", func(mt *mtest.T) {
+		blogID, commentID := primitive.NewObjectID(), primitive.NewObjectID()
+		deleted := func(n int) bson.D { return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}) }
+		updated := func(n int) bson.D {
+			return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.blogs", mtest.FirstBatch, bson.D{{Key: "_id", Value: blogID}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{commentID}}),
+			deleted(3), // reactions
+			deleted(1), // reports
+			deleted(2), // comments
+			deleted(4), // shares
+			deleted(5), // bookmarks
+			deleted(6), // notifications
+			deleted(1), // post_analytics
+			deleted(1), // reader_visits
+			deleted(1), // related_posts
+			updated(1), // related_posts
+			updated(1), // reading_lists
+			updated(1), // series
+			deleted(1), // blogs
+		)
+
+		result, err := NewBlogRepository(mt.DB).PurgeDeleted(context.Background(), time.Now())
+		assert.NoError(mt, err)
+		assert.Equal(mt, &PurgeResult{
+			Blogs: 1, Comments: 2, Shares: 4, Reactions: 3, Reports: 1, Bookmarks: 5, Notifications: 6,
+		}, result)
+		assert.Equal(mt, []string{
+			"find blogs",
+			"distinct comments",
+			"delete reactions",
+			"delete reports",
+			"delete comments",
+			"delete shares",
+			"delete bookmarks",
+			"delete notifications",
+			"delete post_analytics",
+			"delete reader_visits",
+			"delete related_posts",
+			"update related_posts",
+			"update reading_lists",
+			"update series",
+			"delete blogs",
+		}, commands(mt))
+	})
+
+	mt.Run("Nothing to purge touches nothing else", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.blogs", mtest.FirstBatch))
+
+		result, err := NewBlogRepository(mt.DB).PurgeDeleted(context.Background(), time.Now())
+		assert.NoError(mt, err)
+		assert.Equal(mt, &PurgeResult{}, result)
+		assert.Equal(mt, []string{"find blogs"}, commands(mt))
+	})
+}
+
+func TestCoAuthorUpdates(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	blogID, authorID := primitive.NewObjectID(), primitive.NewObjectID()
+	found := func() bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: blogID}}})
+	}
+	missed := func() bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+	}
+	// update returns the update document of the next findAndModify
+	update := func(mt *mtest.T) bson.Raw {
+		e := mt.GetStartedEvent()
+		assert.Equal(mt, "findAndModify", e.CommandName)
+		return e.Command.Lookup("update").Document()
+	}
+
+	mt.Run("Changes the role of an author already listed in place", func(mt *mtest.T) {
+		mt.AddMockResponses(found())
+
+		_, err := NewBlogRepository(mt.DB).InviteCoAuthor(context.Background(), blogID, authorID, "reviewer")
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, "reviewer", update(mt).Lookup("$set", "co_authors.$[listed].role").StringValue())
+	})
+
+	mt.Run("Pushes a new invitation rather than rewriting the list", func(mt *mtest.T) {
+		mt.AddMockResponses(missed(), found())
+
+		_, err := NewBlogRepository(mt.DB).InviteCoAuthor(context.Background(), blogID, authorID, "reviewer")
+
+		assert.NoError(mt, err)
+		update(mt)
+		pushed := update(mt).Lookup("$push", "co_authors").Document()
+		assert.Equal(mt, authorID, pushed.Lookup("author_id").ObjectID())
+		assert.Equal(mt, "pending", pushed.Lookup("status").StringValue())
+	})
+
+	mt.Run("Reports a missing post after retrying once", func(mt *mtest.T) {
+		mt.AddMockResponses(missed(), missed(), missed(), missed())
+
+		_, err := NewBlogRepository(mt.DB).InviteCoAuthor(context.Background(), blogID, authorID, "reviewer")
+
+		assert.ErrorIs(mt, err, mongo.ErrNoDocuments)
+	})
+
+	mt.Run("Only answers a pending invitation", func(mt *mtest.T) {
+		mt.AddMockResponses(missed())
+
+		_, err := NewBlogRepository(mt.DB).RespondToInvite(context.Background(), blogID, authorID, "accepted")
+
+		assert.ErrorIs(mt, err, mongo.ErrNoDocuments)
+		e := mt.GetStartedEvent()
+		match := e.Command.Lookup("query", "co_authors", "$elemMatch").Document()
+		assert.Equal(mt, "pending", match.Lookup("status").StringValue())
+	})
+
+	mt.Run("Pulls a removed co-author", func(mt *mtest.T) {
+		mt.AddMockResponses(found())
+
+		_, err := NewBlogRepository(mt.DB).RemoveCoAuthor(context.Background(), blogID, authorID)
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, authorID, update(mt).Lookup("$pull", "co_authors", "author_id").ObjectID())
+	})
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/bookmark"
+)
+
+// BookmarkRepository stores bookmarks and reading lists
+type BookmarkRepository struct {
+	collection *mongo.Collection
+	lists      *mongo.Collection
+}
+
+func NewBookmarkRepository(db *mongo.Database) *BookmarkRepository {
+	return &BookmarkRepository{
+		collection: db.Collection("bookmarks"),
+		lists:      db.Collection("reading_lists"),
+	}
+}
+
+// EnsureIndexes allows each post to be bookmarked once per author and
+// supports listing an author's bookmarks and reading lists
+func (r *BookmarkRepository) EnsureIndexes(ctx context.Context) error {
+	if _, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "author_id", Value: 1}, {Key: "blog_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	_, err := r.lists.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	return err
+}
+
+// Add bookmarks a post. It reports false if it was already bookmarked.
+func (r *BookmarkRepository) Add(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, bookmark.Bookmark{
+		ID:        primitive.NewObjectID(),
+		AuthorID:  authorID,
+		BlogID:    blogID,
+		CreatedAt: time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Remove deletes a bookmark
+func (r *BookmarkRepository) Remove(ctx context.Context, authorID, blogID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"author_id": authorID, "blog_id": blogID})
+	return err
+}
+
+// IsBookmarked reports whether an author bookmarked a post
+func (r *BookmarkRepository) IsBookmarked(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"author_id": authorID, "blog_id": blogID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// List returns an author's bookmarks, newest first
+func (r *BookmarkRepository) List(ctx context.Context, authorID primitive.ObjectID, limit, skip int64) ([]*bookmark.Bookmark, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"author_id": authorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []*bookmark.Bookmark
+	if err := cursor.All(ctx, &bookmarks); err != nil {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+// CreateList stores a new reading list
+func (r *BookmarkRepository) CreateList(ctx context.Context, l *bookmark.ReadingList) (*bookmark.ReadingList, error) {
+	now := time.Now()
+	l.ID = primitive.NewObjectID()
+	l.Entries = []bookmark.Entry{}
+	l.CreatedAt = now
+	l.UpdatedAt = now
+
+	if _, err := r.lists.InsertOne(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// GetList finds a reading list
+func (r *BookmarkRepository) GetList(ctx context.Context, id primitive.ObjectID) (*bookmark.ReadingList, error) {
+	var l bookmark.ReadingList
+	if err := r.lists.FindOne(ctx, bson.M{"_id": id}).Decode(&l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ListLists returns an author's reading lists, newest first, optionally
+// only the public ones
+func (r *BookmarkRepository) ListLists(ctx context.Context, ownerID primitive.ObjectID, publicOnly bool) ([]*bookmark.ReadingList, error) {
+	filter := bson.M{"owner_id": ownerID}
+	if publicOnly {
+		filter["public"] = true
+	}
+
+	cursor, err := r.lists.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []*bookmark.ReadingList
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// UpdateList changes a reading list's details or entries
+func (r *BookmarkRepository) UpdateList(ctx context.Context, id primitive.ObjectID, update bson.M) (*bookmark.ReadingList, error) {
+	update["updated_at"] = time.Now()
+
+	var l bookmark.ReadingList
+	err := r.lists.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// updateEntries applies an update to a reading list's entries and returns
+// the updated list, or mongo.ErrNoDocuments if filter matches nothing
+func (r *BookmarkRepository) updateEntries(ctx context.Context, filter, update bson.M) (*bookmark.ReadingList, error) {
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = time.Now()
+
+	var l bookmark.ReadingList
+	err := r.lists.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// AddEntry appends a post to the end of a reading list. It returns
+// bookmark.ErrAlreadyListed or bookmark.ErrListFull when the post cannot
+// be added, checked in the same update that adds it.
+func (r *BookmarkRepository) AddEntry(ctx context.Context, listID primitive.ObjectID, e bookmark.Entry) (*bookmark.ReadingList, error) {
+	l, err := r.updateEntries(ctx,
+		bson.M{
+			"_id":              listID,
+			"entries.blog_id":  bson.M{"$ne": e.BlogID},
+			fmt.Sprintf("entries.%d", bookmark.MaxEntries-1): bson.M{"$exists": false},
+		},
+		bson.M{"$push": bson.M{"entries": e}},
+	)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return l, err
+	}
+
+	// Find out why the list did not match
+	current, err := r.GetList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if current.EntryIndex(e.BlogID) >= 0 {
+		return nil, bookmark.ErrAlreadyListed
+	}
+	return nil, bookmark.ErrListFull
+}
+
+// SetEntryNote replaces the note on a post in a reading list; an empty
+// note removes it. It returns mongo.ErrNoDocuments if the post is not in
+// the list.
+func (r *BookmarkRepository) SetEntryNote(ctx context.Context, listID, blogID primitive.ObjectID, note string) (*bookmark.ReadingList, error) {
+	update := bson.M{"$set": bson.M{"entries.$.note": note}}
+	if note == "" {
+		update = bson.M{"$unset": bson.M{"entries.$.note": ""}}
+	}
+	return r.updateEntries(ctx, bson.M{"_id": listID, "entries.blog_id": blogID}, update)
+}
+
+// RemoveEntry takes a post out of a reading list. It returns
+// mongo.ErrNoDocuments if the post is not in the list.
+func (r *BookmarkRepository) RemoveEntry(ctx context.Context, listID, blogID primitive.ObjectID) (*bookmark.ReadingList, error) {
+	return r.updateEntries(ctx,
+		bson.M{"_id": listID, "entries.blog_id": blogID},
+		bson.M{"$pull": bson.M{"entries": bson.M{"blog_id": blogID}}},
+	)
+}
+
+// ReorderEntries replaces a reading list's entries with the same entries
+// in a new order. It returns mongo.ErrNoDocuments if the entries no longer
+// match current, so a change made meanwhile is not overwritten.
+func (r *BookmarkRepository) ReorderEntries(ctx context.Context, listID primitive.ObjectID, current, ordered []bookmark.Entry) (*bookmark.ReadingList, error) {
+	return r.updateEntries(ctx,
+		bson.M{"_id": listID, "entries": current},
+		bson.M{"$set": bson.M{"entries": ordered}},
+	)
+}
+
+// DeleteList removes a reading list
+func (r *BookmarkRepository) DeleteList(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.lists.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"razorblog-backend/internal/models/bookmark"
+)
+
+func TestAddEntry(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	listID, blogID := primitive.NewObjectID(), primitive.NewObjectID()
+	missed := func() bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+	}
+	list := func(entries ...bookmark.Entry) bson.D {
+		raw, _ := bson.Marshal(bookmark.ReadingList{ID: listID, Entries: entries})
+		var d bson.D
+		_ = bson.Unmarshal(raw, &d)
+		return mtest.CreateCursorResponse(0, "test.reading_lists", mtest.FirstBatch, d)
+	}
+
+	mt.Run("Pushes the entry only if the list has room and lacks the post", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: listID}}}))
+
+		_, err := NewBookmarkRepository(mt.DB).AddEntry(context.Background(), listID, bookmark.Entry{BlogID: blogID})
+
+		assert.NoError(mt, err)
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(mt, blogID, cmd.Lookup("query", "entries.blog_id", "$ne").ObjectID())
+		assert.False(mt, cmd.Lookup("query", "entries.499", "$exists").Boolean())
+		assert.Equal(mt, blogID, cmd.Lookup("update", "$push", "entries", "blog_id").ObjectID())
+	})
+
+	mt.Run("Reports a post already in the list", func(mt *mtest.T) {
+		mt.AddMockResponses(missed(), list(bookmark.Entry{BlogID: blogID}))
+
+		_, err := NewBookmarkRepository(mt.DB).AddEntry(context.Background(), listID, bookmark.Entry{BlogID: blogID})
+
+		assert.ErrorIs(mt, err, bookmark.ErrAlreadyListed)
+	})
+
+	mt.Run("Reports a full list", func(mt *mtest.T) {
+		mt.AddMockResponses(missed(), list(bookmark.Entry{BlogID: primitive.NewObjectID()}))
+
+		_, err := NewBookmarkRepository(mt.DB).AddEntry(context.Background(), listID, bookmark.Entry{BlogID: blogID})
+
+		assert.ErrorIs(mt, err, bookmark.ErrListFull)
+	})
+}
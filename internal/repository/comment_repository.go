@@ -24,12 +24,28 @@ func NewCommentRepository(db *mongo.Database) *CommentRepository {
 	}
 }
 
+// visible narrows a filter to comments readers can see: not trashed, not
+// hidden by abuse reports, and approved (or created before moderation existed)
+func visible(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	filter["hidden"] = bson.M{"$ne": true}
+	filter["status"] = bson.M{"$in": bson.A{models.StatusApproved, nil}}
+	return filter
+}
+
 // Create inserts a new comment into the database
 func (r *CommentRepository) Create(ctx context.Context, cmt *models.Comment) (*models.Comment, error) {
 	cmt.ID = primitive.NewObjectID()
 	cmt.CreatedAt = time.Now()
 	cmt.Likes = 0
 	cmt.LikedBy = []string{}
+	cmt.ReplyCount = 0
+	cmt.Reactions = nil
+	cmt.Hidden = false
+	cmt.DeletedAt = nil
+	if cmt.Status == "" {
+		cmt.Status = models.StatusApproved
+	}
 	_, err := r.collection.InsertOne(ctx, cmt)
 	if err != nil {
 		return nil, err
@@ -40,7 +56,7 @@ func (r *CommentRepository) Create(ctx context.Context, cmt *models.Comment) (*m
 // List returns comments for a specific blog with pagination
 func (r *CommentRepository) List(ctx context.Context, blogID primitive.ObjectID, limit, skip int64) ([]*models.Comment, error) {
 	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
-	cursor, err := r.collection.Find(ctx, bson.M{"blog_id": blogID}, opts)
+	cursor, err := r.collection.Find(ctx, visible(bson.M{"blog_id": blogID}), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -57,38 +73,368 @@ func (r *CommentRepository) List(ctx context.Context, blogID primitive.ObjectID,
 	return comments, nil
 }
 
-// Like adds a like to a comment if the username hasn't liked it yet
+// IncrementReplies adjusts the direct reply count of a comment
+func (r *CommentRepository) IncrementReplies(ctx context.Context, commentID primitive.ObjectID, delta int) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": commentID}, bson.M{"$inc": bson.M{"reply_count": delta}})
+	return err
+}
+
+// ListThreads returns a page of top-level comments for a blog, newest first,
+// together with all of their replies, oldest first
+func (r *CommentRepository) ListThreads(ctx context.Context, blogID primitive.ObjectID, limit, skip int64) ([]*models.Comment, []*models.Comment, error) {
+	rootFilter := visible(bson.M{
+		"blog_id":   blogID,
+		"parent_id": bson.M{"$exists": false},
+	})
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
+
+	var roots []*models.Comment
+	cursor, err := r.collection.Find(ctx, rootFilter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cursor.All(ctx, &roots); err != nil {
+		return nil, nil, err
+	}
+	if len(roots) == 0 {
+		return roots, nil, nil
+	}
+
+	rootIDs := make([]primitive.ObjectID, 0, len(roots))
+	for _, c := range roots {
+		rootIDs = append(rootIDs, c.ID)
+	}
+
+	var replies []*models.Comment
+	cursor, err = r.collection.Find(
+		ctx,
+		visible(bson.M{"ancestors": bson.M{"$in": rootIDs}}),
+		options.Find().SetSort(bson.M{"created_at": 1}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cursor.All(ctx, &replies); err != nil {
+		return nil, nil, err
+	}
+
+	return roots, replies, nil
+}
+
+// CountThreads returns the number of top-level comments on a blog
+func (r *CommentRepository) CountThreads(ctx context.Context, blogID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, visible(bson.M{
+		"blog_id":   blogID,
+		"parent_id": bson.M{"$exists": false},
+	}))
+}
+
+// Like adds a like to a comment. It is idempotent: liking twice leaves a
+// single like. The filter only matches while the username is absent, so
+// concurrent requests cannot both count.
 func (r *CommentRepository) Like(ctx context.Context, commentID primitive.ObjectID, username string) (*models.Comment, error) {
-	// Check if user already liked
+	return r.toggleLike(ctx, commentID,
+		bson.M{"$ne": username},
+		bson.M{"$inc": bson.M{"likes": 1}, "$push": bson.M{"liked_by": username}},
+	)
+}
+
+// Unlike removes a like from a comment. Unliking a comment that was not
+// liked is a no-op.
+func (r *CommentRepository) Unlike(ctx context.Context, commentID primitive.ObjectID, username string) (*models.Comment, error) {
+	return r.toggleLike(ctx, commentID,
+		username,
+		bson.M{"$inc": bson.M{"likes": -1}, "$pull": bson.M{"liked_by": username}},
+	)
+}
+
+// toggleLike applies update when liked_by matches condition and returns the
+// comment as it stands afterwards, changed or not
+func (r *CommentRepository) toggleLike(ctx context.Context, commentID primitive.ObjectID, condition interface{}, update bson.M) (*models.Comment, error) {
+	update["$set"] = bson.M{"updated_at": time.Now()}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
 	var c models.Comment
-	if err := r.collection.FindOne(ctx, bson.M{"_id": commentID}).Decode(&c); err != nil {
+	err := r.collection.FindOneAndUpdate(ctx, visible(bson.M{"_id": commentID, "liked_by": condition}), update, opts).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Already in the requested state, or the comment is gone
+		err = r.collection.FindOne(ctx, visible(bson.M{"_id": commentID})).Decode(&c)
+	}
+	if err != nil {
 		return nil, err
 	}
+	return &c, nil
+}
 
-	for _, user := range c.LikedBy {
-		if user == username {
-			return nil, errors.New("user already liked this comment")
+// GetByID finds a comment that is not in the trash
+func (r *CommentRepository) GetByID(ctx context.Context, commentID primitive.ObjectID) (*models.Comment, error) {
+	var c models.Comment
+	err := r.collection.FindOne(ctx, bson.M{"_id": commentID, "deleted_at": bson.M{"$exists": false}}).Decode(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetTrashedByID finds a comment that is in the trash
+func (r *CommentRepository) GetTrashedByID(ctx context.Context, commentID primitive.ObjectID) (*models.Comment, error) {
+	var c models.Comment
+	err := r.collection.FindOne(ctx, bson.M{"_id": commentID, "deleted_at": bson.M{"$exists": true}}).Decode(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Delete moves a comment to the trash
+func (r *CommentRepository) Delete(ctx context.Context, commentID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": commentID, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Restore takes a comment out of the trash
+func (r *CommentRepository) Restore(ctx context.Context, commentID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": commentID, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListTrash returns the trashed comments of a blog, most recently deleted first
+func (r *CommentRepository) ListTrash(ctx context.Context, blogID primitive.ObjectID) ([]*models.Comment, error) {
+	opts := options.Find().SetSort(bson.M{"deleted_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"blog_id": blogID, "deleted_at": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*models.Comment
+	for cursor.Next(ctx) {
+		var c models.Comment
+		if err := cursor.Decode(&c); err != nil {
+			return nil, err
 		}
+		comments = append(comments, &c)
+	}
+	return comments, nil
+}
+
+// PurgeDeleted hard-deletes comments that were trashed before the cutoff
+func (r *CommentRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ListByStatus returns comments in a moderation state, oldest first so the
+// queue is worked in order. A nil blogID lists across all blogs.
+func (r *CommentRepository) ListByStatus(ctx context.Context, status models.Status, blogID *primitive.ObjectID, limit, skip int64) ([]*models.Comment, error) {
+	filter := bson.M{"status": status, "deleted_at": bson.M{"$exists": false}}
+	if blogID != nil {
+		filter["blog_id"] = *blogID
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*models.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// SetStatus moves comments to a moderation state and returns how many changed
+func (r *CommentRepository) SetStatus(ctx context.Context, ids []primitive.ObjectID, status models.Status) (int64, error) {
+	res, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"status": status}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// DeleteMany moves comments to the trash and returns how many were trashed
+func (r *CommentRepository) DeleteMany(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	res, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// GetByIDs returns the non-trashed comments among ids, whatever their status
+func (r *CommentRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*models.Comment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*models.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CountByFingerprint counts comments with the same wording posted since the given time
+func (r *CommentRepository) CountByFingerprint(ctx context.Context, fingerprint string, since time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"fingerprint": fingerprint,
+		"created_at":  bson.M{"$gte": since},
+	})
+}
+
+// CountRecent counts comments posted from an IP address or under a username
+// since the given time
+func (r *CommentRepository) CountRecent(ctx context.Context, ip, username string, since time.Time) (int64, error) {
+	who := bson.A{bson.M{"username": username}}
+	if ip != "" {
+		who = append(who, bson.M{"ip": ip})
+	}
+	return r.collection.CountDocuments(ctx, bson.M{
+		"$or":        who,
+		"created_at": bson.M{"$gte": since},
+	})
+}
+
+// SetTrainedAs records which label a comment was used to train the spam
+// classifier with
+func (r *CommentRepository) SetTrainedAs(ctx context.Context, commentID primitive.ObjectID, label models.Status) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": commentID}, bson.M{"$set": bson.M{"trained_as": label}})
+	return err
+}
+
+// Edit replaces the content of a comment, keeping the previous version in
+// its history. Extra fields (e.g. a new moderation status) are set alongside.
+func (r *CommentRepository) Edit(ctx context.Context, cmt *models.Comment, content string, fields bson.M) (*models.Comment, error) {
+	now := time.Now()
+	set := bson.M{"content": content, "edited_at": now}
+	for k, v := range fields {
+		set[k] = v
 	}
 
 	update := bson.M{
-		"$inc":  bson.M{"likes": 1},
-		"$push": bson.M{"liked_by": username},
-		"$set":  bson.M{"updated_at": time.Now()},
+		"$set":  set,
+		"$push": bson.M{"history": models.Revision{Content: cmt.Content, EditedAt: now}},
 	}
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	var updatedComment models.Comment
-	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": commentID}, update, opts).Decode(&updatedComment); err != nil {
+	// Matching on the current content stops two concurrent edits from
+	// silently overwriting each other
+	var updated models.Comment
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{
+		"_id":        cmt.ID,
+		"content":    cmt.Content,
+		"withdrawn":  bson.M{"$ne": true},
+		"deleted_at": bson.M{"$exists": false},
+	}, update, opts).Decode(&updated)
+	if err != nil {
 		return nil, err
 	}
+	return &updated, nil
+}
 
-	return &updatedComment, nil
+// Withdraw replaces a comment with a tombstone, dropping its content,
+// history and link to the writer while keeping its place in the thread
+func (r *CommentRepository) Withdraw(ctx context.Context, commentID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": commentID, "deleted_at": bson.M{"$exists": false}},
+		bson.M{
+			"$set": bson.M{
+				"content":   models.Tombstone,
+				"username":  models.Tombstone,
+				"withdrawn": true,
+				"verified":  false,
+			},
+			"$unset": bson.M{
+				"author_id":       "",
+				"avatar_url":      "",
+				"history":         "",
+				"edited_at":       "",
+				"edit_token_hash": "",
+				"mentions":        "",
+				"fingerprint":     "",
+				"ip":              "",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-// Delete removes a comment by ID
-func (r *CommentRepository) Delete(ctx context.Context, commentID primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": commentID})
+// SetHidden hides a comment from readers, or shows it again
+func (r *CommentRepository) SetHidden(ctx context.Context, commentID primitive.ObjectID, hidden bool) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": commentID}, bson.M{"$set": bson.M{"hidden": hidden}})
 	return err
 }
 
+// CountByBlog returns how many visible comments each of the given blogs
+// received in [from, to)
+func (r *CommentRepository) CountByBlog(ctx context.Context, blogIDs []primitive.ObjectID, from, to time.Time) (map[primitive.ObjectID]int, error) {
+	return countByBlog(ctx, r.collection, visible(bson.M{
+		"blog_id":    bson.M{"$in": blogIDs},
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}))
+}
+
+// countByBlog groups the documents matching filter by blog
+func countByBlog(ctx context.Context, col *mongo.Collection, filter bson.M) (map[primitive.ObjectID]int, error) {
+	cursor, err := col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": "$blog_id", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		BlogID primitive.ObjectID `bson:"_id"`
+		Count  int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]int, len(rows))
+	for _, row := range rows {
+		counts[row.BlogID] = row.Count
+	}
+	return counts, nil
+}
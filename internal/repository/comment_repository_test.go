@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	models "razorblog-backend/internal/models/comment"
+)
+
+func TestCommentCreate_IgnoresClientState(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Trash and moderation fields are reset", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		deletedAt := time.Now()
+		cmt := &models.Comment{
+			BlogID:     primitive.NewObjectID(),
+			Content:    "Already in the trash?",
+			DeletedAt:  &deletedAt,
+			Hidden:     true,
+			ReplyCount: 7,
+		}
+
+		created, err := NewCommentRepository(mt.DB).Create(context.Background(), cmt)
+		assert.NoError(mt, err)
+		assert.Nil(mt, created.DeletedAt)
+
+		doc := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		_, err = doc.LookupErr("deleted_at")
+		assert.Error(mt, err, "deleted_at must not be stored")
+		assert.Equal(mt, int32(0), doc.Lookup("reply_count").Int32())
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/digest"
+)
+
+// DigestRepository stores each author's email digest schedule
+type DigestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDigestRepository(db *mongo.Database) *DigestRepository {
+	return &DigestRepository{
+		collection: db.Collection("digest_subscriptions"),
+	}
+}
+
+// Get returns an author's digest subscription. Authors who never changed
+// it get the default frequency.
+func (r *DigestRepository) Get(ctx context.Context, authorID primitive.ObjectID) (*digest.Subscription, error) {
+	sub := digest.Subscription{AuthorID: authorID, Frequency: digest.DefaultFrequency}
+	err := r.collection.FindOne(ctx, bson.M{"_id": authorID}).Decode(&sub)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// SetFrequency changes how often an author receives digests
+func (r *DigestRepository) SetFrequency(ctx context.Context, authorID primitive.ObjectID, frequency digest.Frequency) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": authorID},
+		bson.M{"$set": bson.M{"frequency": frequency}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Claim records the end of the period a digest covers and the post totals
+// at that moment. It only succeeds while no earlier claim reached sentAt,
+// so of several servers checking the same author only one gets the period.
+func (r *DigestRepository) Claim(ctx context.Context, authorID primitive.ObjectID, sentAt time.Time, baseline map[string]digest.Counts) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id": authorID,
+			"$or": bson.A{
+				bson.M{"last_sent_at": bson.M{"$lt": sentAt}},
+				bson.M{"last_sent_at": bson.M{"$exists": false}},
+			},
+		},
+		bson.M{
+			"$set":         bson.M{"last_sent_at": sentAt, "baseline": baseline},
+			"$setOnInsert": bson.M{"frequency": digest.DefaultFrequency},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// The subscription exists and its period was already claimed
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0 || res.UpsertedCount > 0, nil
+}
+
+// Release puts back the schedule a claim replaced, as long as nothing has
+// claimed a later period since
+func (r *DigestRepository) Release(ctx context.Context, sub *digest.Subscription, sentAt time.Time) error {
+	update := bson.M{"$set": bson.M{"last_sent_at": sub.LastSentAt, "baseline": sub.Baseline}}
+	if sub.LastSentAt == nil {
+		update = bson.M{"$unset": bson.M{"last_sent_at": "", "baseline": ""}}
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": sub.AuthorID, "last_sent_at": sentAt}, update)
+	return err
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDigestClaim(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	authorID := primitive.NewObjectID()
+	end := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	mt.Run("Claims a period no earlier run reached", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		claimed, err := NewDigestRepository(mt.DB).Claim(context.Background(), authorID, end, nil)
+
+		assert.NoError(mt, err)
+		assert.True(mt, claimed)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(mt, authorID, update.Lookup("q", "_id").ObjectID())
+		before := update.Lookup("q", "$or").Array().Index(0).Value().Document()
+		assert.Equal(mt, end, before.Lookup("last_sent_at", "$lt").Time().UTC())
+		assert.True(mt, update.Lookup("upsert").Boolean())
+	})
+
+	mt.Run("Loses to a server that claimed the period first", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index: 0, Code: 11000, Message: "E11000 duplicate key error",
+		}))
+
+		claimed, err := NewDigestRepository(mt.DB).Claim(context.Background(), authorID, end, nil)
+
+		assert.NoError(mt, err)
+		assert.False(mt, claimed)
+	})
+}
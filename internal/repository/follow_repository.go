@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/follow"
+)
+
+// FollowRepository stores who follows whom and keeps the follower and
+// following counts on author profiles in step
+type FollowRepository struct {
+	collection *mongo.Collection
+	authors    *mongo.Collection
+}
+
+func NewFollowRepository(db *mongo.Database) *FollowRepository {
+	return &FollowRepository{
+		collection: db.Collection("follows"),
+		authors:    db.Collection("authors"),
+	}
+}
+
+// EnsureIndexes allows each follow only once and supports listing both
+// sides of it
+func (r *FollowRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "follower_id", Value: 1}, {Key: "followee_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "followee_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Follow makes followerID follow followeeID. It reports false if they
+// already did.
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, follow.Follow{
+		ID:         primitive.NewObjectID(),
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, r.count(ctx, followerID, followeeID, 1)
+}
+
+// Unfollow removes a follow. It reports false if there was none.
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"follower_id": followerID, "followee_id": followeeID})
+	if err != nil || res.DeletedCount == 0 {
+		return false, err
+	}
+	return true, r.count(ctx, followerID, followeeID, -1)
+}
+
+// count moves the following count of the follower and the follower count
+// of the followee by delta
+func (r *FollowRepository) count(ctx context.Context, followerID, followeeID primitive.ObjectID, delta int) error {
+	if _, err := r.authors.UpdateOne(ctx, bson.M{"_id": followerID}, bson.M{"$inc": bson.M{"following_count": delta}}); err != nil {
+		return err
+	}
+	_, err := r.authors.UpdateOne(ctx, bson.M{"_id": followeeID}, bson.M{"$inc": bson.M{"follower_count": delta}})
+	return err
+}
+
+// IsFollowing reports whether followerID follows followeeID
+func (r *FollowRepository) IsFollowing(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"follower_id": followerID, "followee_id": followeeID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// Following returns everyone an author follows
+func (r *FollowRepository) Following(ctx context.Context, followerID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"follower_id": followerID},
+		options.Find().SetProjection(bson.M{"followee_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var follows []follow.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(follows))
+	for _, f := range follows {
+		ids = append(ids, f.FolloweeID)
+	}
+	return ids, nil
+}
@@ -2,10 +2,21 @@ package repository
 
 import (
 	"context"
+	"time"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"razorblog-backend/internal/models/analytics"
+	"razorblog-backend/internal/models/audit"
 	"razorblog-backend/internal/models/author"
 	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/bookmark"
+	"razorblog-backend/internal/models/digest"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/models/reaction"
+	"razorblog-backend/internal/models/related"
+	"razorblog-backend/internal/models/report"
+	"razorblog-backend/internal/models/series"
+	share "razorblog-backend/internal/models/share"
 )
 
 type IBlogRepository interface {
@@ -13,17 +24,117 @@ type IBlogRepository interface {
 	GetByID(ctx context.Context, id primitive.ObjectID) (*blog.Blog, error)
 	Update(ctx context.Context, id primitive.ObjectID, update bson.M) (*blog.Blog, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	Restore(ctx context.Context, id, authorID primitive.ObjectID) error
+	ListTrash(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error)
 	List(ctx context.Context, limit int64, skip int64) ([]*blog.Blog, error)
 	ListByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error)
-	IncrementReaders(ctx context.Context, id primitive.ObjectID) error
-	LikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) error
+	ListAllByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*blog.Blog, error)
+	ListTrending(ctx context.Context, docType blog.DocumentType, limit int64) ([]*blog.Blog, error)
+	ListFeed(ctx context.Context, authorIDs []primitive.ObjectID, after *blog.Cursor, limit int64) ([]*blog.Blog, error)
+	GetListedByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*blog.Blog, error)
+	ListTDDs(ctx context.Context, status blog.DecisionStatus, limit, skip int64) ([]*blog.Blog, error)
+	CountView(ctx context.Context, id primitive.ObjectID, unique bool) error
+	LikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) (bool, error)
 	UnlikeBlog(ctx context.Context, blogID, userID primitive.ObjectID) error
+	InviteCoAuthor(ctx context.Context, blogID, authorID primitive.ObjectID, role blog.ContributionRole) (*blog.Blog, error)
+	RespondToInvite(ctx context.Context, blogID, authorID primitive.ObjectID, status blog.InviteStatus) (*blog.Blog, error)
+	RemoveCoAuthor(ctx context.Context, blogID, authorID primitive.ObjectID) (*blog.Blog, error)
+	ReorderCoAuthors(ctx context.Context, blogID primitive.ObjectID, current, ordered []blog.CoAuthor) (*blog.Blog, error)
 }
 
 type IAuthorRepository interface {
 	CreateAuthor(a *author.Author) (*author.Author, error)
 	GetAuthorByID(id primitive.ObjectID) (*author.Author, error)
 	GetAuthorByEmail(email string) (*author.Author, error)
+	GetAuthorByName(name string) (*author.Author, error)
+	GetAuthorsByNamesOrIDs(names []string, ids []primitive.ObjectID) ([]*author.Author, error)
 	UpdateAuthor(id primitive.ObjectID, update bson.M) error
 	DeleteAuthor(id primitive.ObjectID) error
 }
+
+type ISeriesRepository interface {
+	Create(ctx context.Context, s *series.Series) (*series.Series, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*series.Series, error)
+	GetByPost(ctx context.Context, postID primitive.ObjectID) (*series.Series, error)
+	ListByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*series.Series, error)
+	Update(ctx context.Context, id primitive.ObjectID, update bson.M) (*series.Series, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type IAuditRepository interface {
+	Record(ctx context.Context, e *audit.Event) error
+	ListByActor(ctx context.Context, actorID primitive.ObjectID) ([]*audit.Event, error)
+}
+
+type IReactionRepository interface {
+	Add(ctx context.Context, r *reaction.Reaction) (bool, error)
+	Remove(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID, kind reaction.Kind, reactor string) (bool, error)
+	Counts(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID) (map[reaction.Kind]int64, error)
+	ListReactors(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID, kind reaction.Kind, limit, skip int64) ([]*reaction.Reaction, error)
+}
+
+type IReportRepository interface {
+	Create(ctx context.Context, r *report.Report) (*report.Report, bool, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*report.Report, error)
+	CountOpen(ctx context.Context, targetType report.TargetType, targetID primitive.ObjectID) (int64, error)
+	List(ctx context.Context, status report.Status, targetType report.TargetType, limit, skip int64) ([]*report.Report, error)
+	CloseTarget(ctx context.Context, targetType report.TargetType, targetID primitive.ObjectID, status report.Status, moderatorID primitive.ObjectID, note string) (int64, error)
+}
+
+type INotificationRepository interface {
+	CreateMany(ctx context.Context, notes []*notification.Notification) error
+	List(ctx context.Context, recipientID primitive.ObjectID, unreadOnly bool, limit, skip int64) ([]*notification.Notification, error)
+	CountUnread(ctx context.Context, recipientID primitive.ObjectID) (int64, error)
+	MarkRead(ctx context.Context, id, recipientID primitive.ObjectID) error
+	MarkAllRead(ctx context.Context, recipientID primitive.ObjectID) (int64, error)
+	GetPreferences(ctx context.Context, authorID primitive.ObjectID) (*notification.Preferences, error)
+	SetPreferences(ctx context.Context, prefs *notification.Preferences) error
+	MutedBy(ctx context.Context, t notification.Type, authorIDs []primitive.ObjectID) ([]primitive.ObjectID, error)
+}
+
+type IDigestRepository interface {
+	Get(ctx context.Context, authorID primitive.ObjectID) (*digest.Subscription, error)
+	SetFrequency(ctx context.Context, authorID primitive.ObjectID, frequency digest.Frequency) error
+}
+
+type IFollowRepository interface {
+	Follow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error)
+	Unfollow(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error)
+	IsFollowing(ctx context.Context, followerID, followeeID primitive.ObjectID) (bool, error)
+	Following(ctx context.Context, followerID primitive.ObjectID) ([]primitive.ObjectID, error)
+}
+
+type IBookmarkRepository interface {
+	Add(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error)
+	Remove(ctx context.Context, authorID, blogID primitive.ObjectID) error
+	IsBookmarked(ctx context.Context, authorID, blogID primitive.ObjectID) (bool, error)
+	List(ctx context.Context, authorID primitive.ObjectID, limit, skip int64) ([]*bookmark.Bookmark, error)
+	CreateList(ctx context.Context, l *bookmark.ReadingList) (*bookmark.ReadingList, error)
+	GetList(ctx context.Context, id primitive.ObjectID) (*bookmark.ReadingList, error)
+	ListLists(ctx context.Context, ownerID primitive.ObjectID, publicOnly bool) ([]*bookmark.ReadingList, error)
+	UpdateList(ctx context.Context, id primitive.ObjectID, update bson.M) (*bookmark.ReadingList, error)
+	AddEntry(ctx context.Context, listID primitive.ObjectID, e bookmark.Entry) (*bookmark.ReadingList, error)
+	SetEntryNote(ctx context.Context, listID, blogID primitive.ObjectID, note string) (*bookmark.ReadingList, error)
+	RemoveEntry(ctx context.Context, listID, blogID primitive.ObjectID) (*bookmark.ReadingList, error)
+	ReorderEntries(ctx context.Context, listID primitive.ObjectID, current, ordered []bookmark.Entry) (*bookmark.ReadingList, error)
+	DeleteList(ctx context.Context, id primitive.ObjectID) error
+}
+
+type IAnalyticsRepository interface {
+	Record(ctx context.Context, e analytics.Event) error
+	Series(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time) ([]analytics.Point, error)
+	Breakdown(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) (map[string]int, error)
+	BreakdownSeries(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, field string) ([]analytics.LabelPoint, error)
+	TopPosts(ctx context.Context, blogIDs []primitive.ObjectID, g analytics.Granularity, from, to time.Time, by analytics.Metric, limit int64) ([]analytics.PostTotals, error)
+	Popular(ctx context.Context, g analytics.Granularity, from, to time.Time, weights map[analytics.Metric]float64, docType blog.DocumentType, limit int64) ([]analytics.PostTotals, error)
+}
+
+type IRelatedRepository interface {
+	Get(ctx context.Context, blogID primitive.ObjectID) ([]related.Match, error)
+}
+
+type IShareRepository interface {
+	Create(ctx context.Context, s *share.Share) (*share.Share, error)
+	List(ctx context.Context, blogID primitive.ObjectID, limit, skip int64) ([]*share.Share, error)
+	Claim(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/notification"
+)
+
+// NotificationRepository stores notifications and each author's
+// notification preferences
+type NotificationRepository struct {
+	collection  *mongo.Collection
+	preferences *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		collection:  db.Collection("notifications"),
+		preferences: db.Collection("notification_preferences"),
+	}
+}
+
+// EnsureIndexes supports the unread-first inbox and keeps keyed events
+// from notifying the same author twice
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "recipient_id", Value: 1}, {Key: "read", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "recipient_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key": bson.M{"$exists": true}}),
+		},
+	})
+	return err
+}
+
+// CreateMany stores a batch of notifications. Notifications whose key the
+// recipient has already been sent are skipped.
+func (r *NotificationRepository) CreateMany(ctx context.Context, notes []*notification.Notification) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(notes))
+	now := time.Now()
+	for _, n := range notes {
+		n.ID = primitive.NewObjectID()
+		n.Read = false
+		n.ReadAt = nil
+		n.CreatedAt = now
+		docs = append(docs, n)
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// List returns an author's notifications, unread first and newest first
+// within each group
+func (r *NotificationRepository) List(ctx context.Context, recipientID primitive.ObjectID, unreadOnly bool, limit, skip int64) ([]*notification.Notification, error) {
+	filter := bson.M{"recipient_id": recipientID}
+	if unreadOnly {
+		filter["read"] = false
+	}
+
+	opts := options.Find().
+		SetLimit(limit).
+		SetSkip(skip).
+		SetSort(bson.D{{Key: "read", Value: 1}, {Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*notification.Notification
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// CountUnread returns how many unread notifications an author has
+func (r *NotificationRepository) CountUnread(ctx context.Context, recipientID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"recipient_id": recipientID, "read": false})
+}
+
+// MarkRead marks one of an author's notifications as read
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, recipientID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "recipient_id": recipientID},
+		bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of an author as read and
+// returns how many changed
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, recipientID primitive.ObjectID) (int64, error) {
+	res, err := r.collection.UpdateMany(ctx,
+		bson.M{"recipient_id": recipientID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// GetPreferences returns an author's notification preferences. Authors who
+// never changed them get every type turned on.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, authorID primitive.ObjectID) (*notification.Preferences, error) {
+	prefs := notification.Preferences{AuthorID: authorID, Muted: []notification.Type{}}
+	err := r.preferences.FindOne(ctx, bson.M{"_id": authorID}).Decode(&prefs)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SetPreferences replaces an author's notification preferences
+func (r *NotificationRepository) SetPreferences(ctx context.Context, prefs *notification.Preferences) error {
+	_, err := r.preferences.UpdateOne(ctx,
+		bson.M{"_id": prefs.AuthorID},
+		bson.M{"$set": bson.M{"muted": prefs.Muted}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// MutedBy returns which of the given authors have muted notifications of
+// type t
+func (r *NotificationRepository) MutedBy(ctx context.Context, t notification.Type, authorIDs []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.preferences.Find(ctx,
+		bson.M{"_id": bson.M{"$in": authorIDs}, "muted": t},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefs []notification.Preferences
+	if err := cursor.All(ctx, &prefs); err != nil {
+		return nil, err
+	}
+	muted := make([]primitive.ObjectID, 0, len(prefs))
+	for _, p := range prefs {
+		muted = append(muted, p.AuthorID)
+	}
+	return muted, nil
+}
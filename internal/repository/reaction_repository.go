@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/reaction"
+)
+
+// ReactionRepository stores emoji reactions and keeps the per-kind counts
+// embedded in blogs and comments up to date
+type ReactionRepository struct {
+	collection *mongo.Collection
+	targets    map[reaction.TargetType]*mongo.Collection
+}
+
+func NewReactionRepository(db *mongo.Database) *ReactionRepository {
+	return &ReactionRepository{
+		collection: db.Collection("reactions"),
+		targets: map[reaction.TargetType]*mongo.Collection{
+			reaction.TargetBlog:    db.Collection("blogs"),
+			reaction.TargetComment: db.Collection("comments"),
+		},
+	}
+}
+
+// EnsureIndexes makes each reader's reaction of a kind unique per target
+func (r *ReactionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "reactor", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Add records a reaction. It is idempotent: reacting twice with the same
+// kind changes nothing and reports false.
+func (r *ReactionRepository) Add(ctx context.Context, re *reaction.Reaction) (bool, error) {
+	filter := bson.M{
+		"target_type": re.TargetType,
+		"target_id":   re.TargetID,
+		"kind":        re.Kind,
+		"reactor":     re.Reactor,
+	}
+	re.ID = primitive.NewObjectID()
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": re}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request inserted the same reaction first
+		return false, nil
+	}
+	if err != nil || res.UpsertedCount == 0 {
+		return false, err
+	}
+
+	return true, r.adjustCount(ctx, re.TargetType, re.TargetID, re.Kind, 1)
+}
+
+// Remove deletes a reaction, reporting false when there was none
+func (r *ReactionRepository) Remove(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID, kind reaction.Kind, reactor string) (bool, error) {
+	res, err := r.collection.DeleteOne(ctx, bson.M{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"kind":        kind,
+		"reactor":     reactor,
+	})
+	if err != nil || res.DeletedCount == 0 {
+		return false, err
+	}
+
+	return true, r.adjustCount(ctx, targetType, targetID, kind, -1)
+}
+
+// Counts returns the number of reactions of each kind on a target
+func (r *ReactionRepository) Counts(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID) (map[reaction.Kind]int64, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"target_type": targetType, "target_id": targetID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$kind", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Kind  reaction.Kind `bson:"_id"`
+		Count int64         `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[reaction.Kind]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Kind] = row.Count
+	}
+	return counts, nil
+}
+
+// ListReactors returns who reacted to a target, newest first. An empty kind
+// lists every kind.
+func (r *ReactionRepository) ListReactors(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID, kind reaction.Kind, limit, skip int64) ([]*reaction.Reaction, error) {
+	filter := bson.M{"target_type": targetType, "target_id": targetID}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reactions []*reaction.Reaction
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+// adjustCount keeps the reaction counts embedded in the target in step
+func (r *ReactionRepository) adjustCount(ctx context.Context, targetType reaction.TargetType, targetID primitive.ObjectID, kind reaction.Kind, delta int) error {
+	_, err := r.targets[targetType].UpdateOne(ctx,
+		bson.M{"_id": targetID},
+		bson.M{"$inc": bson.M{"reactions." + string(kind): delta}},
+	)
+	return err
+}
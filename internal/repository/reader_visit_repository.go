@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReaderVisitRepository remembers which visitors have been counted as
+// readers of a post in each window. Anonymous visitors are stored as
+// hashes that change every window, and visits expire after the retention
+// period; until then they show which posts were read together.
+type ReaderVisitRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReaderVisitRepository(db *mongo.Database) *ReaderVisitRepository {
+	return &ReaderVisitRepository{collection: db.Collection("reader_visits")}
+}
+
+// EnsureIndexes allows one visit per visitor, post and window and drops
+// visits once they expire
+func (r *ReaderVisitRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "visitor", Value: 1}, {Key: "window", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+// RecordVisit stores the visit, reporting false if the visitor was already
+// counted for the post in this window
+func (r *ReaderVisitRepository) RecordVisit(ctx context.Context, blogID primitive.ObjectID, visitor string, window, expires time.Time) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, bson.M{
+		"blog_id":    blogID,
+		"visitor":    visitor,
+		"window":     window,
+		"expires_at": expires,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ForEachSession calls fn with the posts each visitor read within one
+// window, for windows starting at or after since
+func (r *ReaderVisitRepository) ForEachSession(ctx context.Context, since time.Time, fn func([]primitive.ObjectID) error) error {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"window": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"visitor": "$visitor", "window": "$window"},
+			"blogs": bson.M{"$addToSet": "$blog_id"},
+		}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var session struct {
+			Blogs []primitive.ObjectID `bson:"blogs"`
+		}
+		if err := cursor.Decode(&session); err != nil {
+			return err
+		}
+		if err := fn(session.Blogs); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
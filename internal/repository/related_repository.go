@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/related"
+)
+
+// RelatedRepository stores the precomputed related posts of each post
+type RelatedRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRelatedRepository(db *mongo.Database) *RelatedRepository {
+	return &RelatedRepository{collection: db.Collection("related_posts")}
+}
+
+// Get returns the related posts of a post, best first. Posts that have not
+// been through a run yet have none.
+func (r *RelatedRepository) Get(ctx context.Context, blogID primitive.ObjectID) ([]related.Match, error) {
+	var rel related.Related
+	err := r.collection.FindOne(ctx, bson.M{"_id": blogID}).Decode(&rel)
+	if err == mongo.ErrNoDocuments {
+		return []related.Match{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rel.Posts, nil
+}
+
+// ReplaceAll stores the results of a run and drops the related posts of
+// posts the run did not cover, such as deleted ones
+func (r *RelatedRepository) ReplaceAll(ctx context.Context, results map[primitive.ObjectID][]related.Match, computedAt time.Time) error {
+	if len(results) > 0 {
+		models := make([]mongo.WriteModel, 0, len(results))
+		for id, posts := range results {
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": id}).
+				SetReplacement(related.Related{BlogID: id, Posts: posts, ComputedAt: computedAt}).
+				SetUpsert(true))
+		}
+		if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"computed_at": bson.M{"$lt": computedAt}})
+	return err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/report"
+)
+
+// ReportRepository stores abuse reports
+type ReportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReportRepository(db *mongo.Database) *ReportRepository {
+	return &ReportRepository{
+		collection: db.Collection("reports"),
+	}
+}
+
+// EnsureIndexes allows each reporter only one open report per target
+func (r *ReportRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "reporter_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": report.StatusOpen}),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+// Create files a report unless the reporter already has an open one on the
+// same target, in which case that report is returned and created is false
+func (r *ReportRepository) Create(ctx context.Context, rep *report.Report) (*report.Report, bool, error) {
+	filter := bson.M{
+		"target_type": rep.TargetType,
+		"target_id":   rep.TargetID,
+		"reporter_id": rep.ReporterID,
+		"status":      report.StatusOpen,
+	}
+	rep.ID = primitive.NewObjectID()
+	rep.Status = report.StatusOpen
+	rep.CreatedAt = time.Now()
+
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": rep}, options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return nil, false, err
+	}
+	if err == nil && res.UpsertedCount == 1 {
+		return rep, true, nil
+	}
+
+	var existing report.Report
+	if err := r.collection.FindOne(ctx, filter).Decode(&existing); err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// GetByID finds a report
+func (r *ReportRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*report.Report, error) {
+	var rep report.Report
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+// CountOpen returns how many distinct readers have an open report on a target
+func (r *ReportRepository) CountOpen(ctx context.Context, targetType report.TargetType, targetID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"status":      report.StatusOpen,
+	})
+}
+
+// List returns reports in a triage state, oldest first. An empty target
+// type lists every kind.
+func (r *ReportRepository) List(ctx context.Context, status report.Status, targetType report.TargetType, limit, skip int64) ([]*report.Report, error) {
+	filter := bson.M{"status": status}
+	if targetType != "" {
+		filter["target_type"] = targetType
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"created_at": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*report.Report
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// CloseTarget closes every open report on a target and returns how many
+// were closed
+func (r *ReportRepository) CloseTarget(ctx context.Context, targetType report.TargetType, targetID primitive.ObjectID, status report.Status, moderatorID primitive.ObjectID, note string) (int64, error) {
+	res, err := r.collection.UpdateMany(ctx,
+		bson.M{"target_type": targetType, "target_id": targetID, "status": report.StatusOpen},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"resolved_by": moderatorID,
+			"note":        note,
+			"resolved_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
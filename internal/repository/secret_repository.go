@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SecretRepository keeps server-generated secrets that every replica has
+// to agree on, such as the salt of anonymous visitor hashes
+type SecretRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSecretRepository(db *mongo.Database) *SecretRepository {
+	return &SecretRepository{
+		collection: db.Collection("secrets"),
+	}
+}
+
+// Get returns the secret stored under name. The first caller generates a
+// random one and stores it; everyone after, on any replica, gets that one.
+func (r *SecretRepository) Get(ctx context.Context, name string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var doc struct {
+		Value string `bson:"value"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": name},
+		bson.M{"$setOnInsert": bson.M{"value": hex.EncodeToString(buf)}},
+		opts,
+	).Decode(&doc)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another replica stored the secret first
+		err = r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&doc)
+	}
+	if err != nil {
+		return "", err
+	}
+	return doc.Value, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSecretGet(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Returns the stored secret rather than a new one", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "reader_salt"}, {Key: "value", Value: "stored"}}},
+		))
+
+		salt, err := NewSecretRepository(mt.DB).Get(context.Background(), "reader_salt")
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, "stored", salt)
+		cmd := mt.GetStartedEvent().Command
+		assert.True(mt, cmd.Lookup("upsert").Boolean())
+		generated := cmd.Lookup("update", "$setOnInsert", "value").StringValue()
+		assert.Len(mt, generated, 64)
+	})
+
+	mt.Run("Reads back the secret a concurrent replica stored", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Name: "DuplicateKey", Message: "E11000 duplicate key error"}),
+			mtest.CreateCursorResponse(0, "test.secrets", mtest.FirstBatch, bson.D{{Key: "_id", Value: "reader_salt"}, {Key: "value", Value: "theirs"}}),
+		)
+
+		salt, err := NewSecretRepository(mt.DB).Get(context.Background(), "reader_salt")
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, "theirs", salt)
+	})
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/series"
+)
+
+// SeriesRepository handles database operations for post series
+type SeriesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSeriesRepository(db *mongo.Database) *SeriesRepository {
+	return &SeriesRepository{
+		collection: db.Collection("series"),
+	}
+}
+
+// EnsureIndexes lets a post belong to at most one series. Series without
+// posts are left out, as every empty list would otherwise share one key.
+func (r *SeriesRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "post_ids", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"post_ids": bson.M{"$type": "objectId"}}),
+	})
+	return err
+}
+
+// Create inserts a new series into the database
+func (r *SeriesRepository) Create(ctx context.Context, s *series.Series) (*series.Series, error) {
+	now := time.Now()
+	s.ID = primitive.NewObjectID()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+	if s.PostIDs == nil {
+		s.PostIDs = []primitive.ObjectID{}
+	}
+
+	_, err := r.collection.InsertOne(ctx, s)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, series.ErrPostInSeries
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetByID finds a series by ID
+func (r *SeriesRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*series.Series, error) {
+	var s series.Series
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetByPost returns the series a post belongs to, or nil if it is standalone
+func (r *SeriesRepository) GetByPost(ctx context.Context, postID primitive.ObjectID) (*series.Series, error) {
+	var s series.Series
+	err := r.collection.FindOne(ctx, bson.M{"post_ids": postID}).Decode(&s)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByAuthor returns all series owned by an author, newest first
+func (r *SeriesRepository) ListByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]*series.Series, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"author_id": authorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []*series.Series
+	for cursor.Next(ctx) {
+		var s series.Series
+		if err := cursor.Decode(&s); err != nil {
+			return nil, err
+		}
+		list = append(list, &s)
+	}
+	return list, nil
+}
+
+// Update applies a partial update and returns the updated series
+func (r *SeriesRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) (*series.Series, error) {
+	update["updated_at"] = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated series.Series
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": update}, opts).Decode(&updated)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, series.ErrPostInSeries
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete removes a series by ID. The posts themselves are left untouched.
+func (r *SeriesRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"razorblog-backend/internal/models/series"
+)
+
+func TestSeriesPostUniqueness(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	duplicate := mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}
+
+	mt.Run("Indexes each post once, skipping empty series", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := NewSeriesRepository(mt.DB).EnsureIndexes(context.Background())
+
+		assert.NoError(mt, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(mt, int32(1), index.Lookup("key", "post_ids").Int32())
+		assert.True(mt, index.Lookup("unique").Boolean())
+		assert.Equal(mt, "objectId", index.Lookup("partialFilterExpression", "post_ids", "$type").StringValue())
+	})
+
+	mt.Run("Create reports a post claimed by another series", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(duplicate))
+
+		_, err := NewSeriesRepository(mt.DB).Create(context.Background(), &series.Series{
+			PostIDs: []primitive.ObjectID{primitive.NewObjectID()},
+		})
+
+		assert.ErrorIs(mt, err, series.ErrPostInSeries)
+	})
+
+	mt.Run("Update reports a post claimed by another series", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 11000, Message: "E11000 duplicate key error",
+		}))
+
+		_, err := NewSeriesRepository(mt.DB).Update(context.Background(), primitive.NewObjectID(), bson.M{
+			"post_ids": []primitive.ObjectID{primitive.NewObjectID()},
+		})
+
+		assert.ErrorIs(mt, err, series.ErrPostInSeries)
+	})
+}
@@ -4,24 +4,56 @@ import (
 	"context"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	models "razorblog-backend/internal/models/share"
 )
 
+// shareCountsMigration names the marker recording when blogs started
+// keeping their own share counts
+const shareCountsMigration = "share_counts"
+
+// shareBackfillLease is how long a server may spend backfilling share
+// counts before another may take over
+const shareBackfillLease = 15 * time.Minute
+
 // ShareRepository handles database operations for blog shares
 type ShareRepository struct {
 	collection *mongo.Collection
+	blogs      *mongo.Collection
+	claims     *mongo.Collection // Recent shares, used to drop rapid repeats
+	migrations *mongo.Collection
 }
 
 func NewShareRepository(db *mongo.Database) *ShareRepository {
 	return &ShareRepository{
 		collection: db.Collection("shares"),
+		blogs:      db.Collection("blogs"),
+		claims:     db.Collection("share_claims"),
+		migrations: db.Collection("migrations"),
+	}
+}
+
+// EnsureIndexes speeds up listing a blog's shares and drops share claims
+// once their window has passed
+func (r *ShareRepository) EnsureIndexes(ctx context.Context) error {
+	if _, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "blog_id", Value: 1}, {Key: "created_at", Value: 1}},
+	}); err != nil {
+		return err
 	}
+	_, err := r.claims.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
 }
 
-// Create inserts a new share into the database
+// Create inserts a new share into the database and adds it to the blog's
+// per-platform share counts
 func (r *ShareRepository) Create(ctx context.Context, s *models.Share) (*models.Share, error) {
 	s.ID = primitive.NewObjectID()
 	s.CreatedAt = time.Now()
@@ -31,12 +63,159 @@ func (r *ShareRepository) Create(ctx context.Context, s *models.Share) (*models.
 		return nil, err
 	}
 
+	if _, err := r.blogs.UpdateOne(ctx,
+		bson.M{"_id": s.BlogID},
+		bson.M{"$inc": bson.M{"shares." + s.Platform: 1}},
+	); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
-// List returns all shares for a given blog
-func (r *ShareRepository) List(ctx context.Context, blogID primitive.ObjectID) ([]*models.Share, error) {
-	cursor, err := r.collection.Find(ctx, map[string]interface{}{"blog_id": blogID})
+// Claim reports whether a share identified by key should be counted. The
+// first claim of a key counts and holds it for window; claims of the same
+// key before then do not.
+func (r *ShareRepository) Claim(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error) {
+	// An expired claim the TTL monitor has not removed yet is taken over,
+	// while a live one makes the upsert collide on _id
+	_, err := r.claims.UpdateOne(ctx,
+		bson.M{"_id": key, "expires_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"expires_at": now.Add(window)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StartCounting records when blogs started keeping their own share counts
+// and returns that moment. The first server to start with counting sets
+// it; later ones get the same time. Call it before serving shares.
+func (r *ShareRepository) StartCounting(ctx context.Context) (time.Time, error) {
+	var marker struct {
+		StartedAt time.Time `bson:"started_at"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.migrations.FindOneAndUpdate(ctx,
+		bson.M{"_id": shareCountsMigration},
+		bson.M{"$setOnInsert": bson.M{"started_at": time.Now()}},
+		opts,
+	).Decode(&marker)
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.migrations.FindOne(ctx, bson.M{"_id": shareCountsMigration}).Decode(&marker)
+	}
+	return marker.StartedAt, err
+}
+
+// BackfillCounts adds the shares made before counting started to the
+// blogs' per-platform counts, skipping platforms outside platforms. Live
+// shares are counted by Create, so the two never overlap and the backfill
+// adds to rather than replaces the counts. One server at a time runs it
+// under a lease and reports true; the rest report false. It is only marked
+// done once every count is written, so a failed run is retried by the next
+// server to start.
+func (r *ShareRepository) BackfillCounts(ctx context.Context, before time.Time, platforms []string) (bool, error) {
+	now := time.Now()
+	res, err := r.migrations.UpdateOne(ctx,
+		bson.M{
+			"_id":           shareCountsMigration,
+			"backfilled_at": bson.M{"$exists": false},
+			"$or": bson.A{
+				bson.M{"backfill_lease": bson.M{"$exists": false}},
+				bson.M{"backfill_lease": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"$set": bson.M{"backfill_lease": now.Add(shareBackfillLease)}},
+	)
+	if err != nil || res.ModifiedCount == 0 {
+		return false, err
+	}
+
+	// The work must not outlive the lease, or another server could take
+	// over while it is still running
+	runCtx, cancel := context.WithDeadline(ctx, now.Add(shareBackfillLease))
+	defer cancel()
+	if err := r.backfillCounts(runCtx, before, platforms); err != nil {
+		releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelRelease()
+		_, _ = r.migrations.UpdateOne(releaseCtx,
+			bson.M{"_id": shareCountsMigration},
+			bson.M{"$unset": bson.M{"backfill_lease": ""}},
+		)
+		return true, err
+	}
+
+	_, err = r.migrations.UpdateOne(ctx,
+		bson.M{"_id": shareCountsMigration},
+		bson.M{
+			"$set":   bson.M{"backfilled_at": time.Now()},
+			"$unset": bson.M{"backfill_lease": ""},
+		},
+	)
+	return true, err
+}
+
+// backfillCounts adds each blog's earlier shares to its counts. Blogs are
+// flagged in the same update, so a retry after a partly applied run skips
+// the blogs already done.
+func (r *ShareRepository) backfillCounts(ctx context.Context, before time.Time, platforms []string) error {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$lt": before}}}},
+		{{Key: "$project", Value: bson.M{"blog_id": 1, "platform": bson.M{"$toLower": "$platform"}}}},
+		{{Key: "$match", Value: bson.M{"platform": bson.M{"$in": platforms}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"blog_id": "$blog_id", "platform": "$platform"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	counts := map[primitive.ObjectID]bson.M{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				BlogID   primitive.ObjectID `bson:"blog_id"`
+				Platform string             `bson:"platform"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return err
+		}
+		if counts[row.ID.BlogID] == nil {
+			counts[row.ID.BlogID] = bson.M{}
+		}
+		counts[row.ID.BlogID]["shares."+row.ID.Platform] = row.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	updates := make([]mongo.WriteModel, 0, len(counts))
+	for id, byPlatform := range counts {
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "shares_backfilled": bson.M{"$exists": false}}).
+			SetUpdate(bson.M{"$inc": byPlatform, "$set": bson.M{"shares_backfilled": true}}))
+	}
+	_, err = r.blogs.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// List returns a page of a blog's shares, newest first
+func (r *ShareRepository) List(ctx context.Context, blogID primitive.ObjectID, limit, skip int64) ([]*models.Share, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"blog_id": blogID}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -54,3 +233,11 @@ func (r *ShareRepository) List(ctx context.Context, blogID primitive.ObjectID) (
 	return shares, nil
 }
 
+// CountByBlog returns how many times each of the given blogs was shared in
+// [from, to)
+func (r *ShareRepository) CountByBlog(ctx context.Context, blogIDs []primitive.ObjectID, from, to time.Time) (map[primitive.ObjectID]int, error) {
+	return countByBlog(ctx, r.collection, bson.M{
+		"blog_id":    bson.M{"$in": blogIDs},
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	})
+}
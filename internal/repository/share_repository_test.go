@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestShareBackfillCounts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := func(n int) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+
+	mt.Run("Adds earlier shares to the live counts once", func(mt *mtest.T) {
+		blogID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			updated(1), // claim the marker
+			mtest.CreateCursorResponse(0, "test.shares", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: bson.D{{Key: "blog_id", Value: blogID}, {Key: "platform", Value: "x"}}},
+				{Key: "count", Value: 3},
+			}),
+			updated(1), // blogs
+			updated(1), // mark done
+		)
+
+		ran, err := NewShareRepository(mt.DB).BackfillCounts(context.Background(), since, []string{"x"})
+
+		assert.NoError(mt, err)
+		assert.True(mt, ran)
+		mt.GetStartedEvent() // claim
+		pipeline := mt.GetStartedEvent().Command.Lookup("pipeline").Array()
+		assert.Equal(mt, since, pipeline.Index(0).Value().Document().Lookup("$match", "created_at", "$lt").Time().UTC())
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(mt, int32(3), update.Lookup("u", "$inc", "shares.x").Int32(), "merged with live counts, not overwriting them")
+		assert.True(mt, update.Lookup("u", "$set", "shares_backfilled").Boolean(), "retries skip blogs already done")
+		done := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		_, marked := done.Lookup("u", "$set", "backfilled_at").TimeOK()
+		assert.True(mt, marked)
+	})
+
+	mt.Run("Leaves a failed backfill to be retried", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			updated(1), // claim the marker
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "aggregation failed"}),
+			updated(1), // release the lease
+		)
+
+		ran, err := NewShareRepository(mt.DB).BackfillCounts(context.Background(), since, []string{"x"})
+
+		assert.Error(mt, err)
+		assert.True(mt, ran)
+		assert.Equal(mt, []string{"update migrations", "aggregate shares", "update migrations"}, commands(mt))
+	})
+
+	mt.Run("Leaves a claimed backfill to the server that claimed it", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(0))
+
+		ran, err := NewShareRepository(mt.DB).BackfillCounts(context.Background(), since, []string{"x"})
+
+		assert.NoError(mt, err)
+		assert.False(mt, ran)
+		assert.Equal(mt, []string{"update migrations"}, commands(mt))
+	})
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/spam"
+)
+
+// spamStatsID is the _id of the document holding the classifier's totals
+const spamStatsID = "classifier"
+
+// spamToken is the stored training count of one token
+type spamToken struct {
+	Token string `bson:"_id"`
+	Spam  int    `bson:"spam"`
+	Ham   int    `bson:"ham"`
+}
+
+// SpamRepository persists the spam classifier's training
+type SpamRepository struct {
+	tokens *mongo.Collection
+	stats  *mongo.Collection
+}
+
+func NewSpamRepository(db *mongo.Database) *SpamRepository {
+	return &SpamRepository{
+		tokens: db.Collection("spam_tokens"),
+		stats:  db.Collection("spam_stats"),
+	}
+}
+
+// Load reads the full training set
+func (r *SpamRepository) Load(ctx context.Context) (*spam.Model, error) {
+	m := &spam.Model{Spam: map[string]int{}, Ham: map[string]int{}}
+
+	var stats struct {
+		SpamDocs int `bson:"spam_docs"`
+		HamDocs  int `bson:"ham_docs"`
+	}
+	err := r.stats.FindOne(ctx, bson.M{"_id": spamStatsID}).Decode(&stats)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	m.SpamDocs, m.HamDocs = stats.SpamDocs, stats.HamDocs
+
+	cursor, err := r.tokens.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var t spamToken
+		if err := cursor.Decode(&t); err != nil {
+			return nil, err
+		}
+		if t.Spam > 0 {
+			m.Spam[t.Token] = t.Spam
+		}
+		if t.Ham > 0 {
+			m.Ham[t.Token] = t.Ham
+		}
+	}
+	return m, cursor.Err()
+}
+
+// Add adjusts the counts of one labelled document by delta
+func (r *SpamRepository) Add(ctx context.Context, tokens []string, isSpam bool, delta int) error {
+	field, docs := "ham", "ham_docs"
+	if isSpam {
+		field, docs = "spam", "spam_docs"
+	}
+
+	_, err := r.stats.UpdateOne(ctx,
+		bson.M{"_id": spamStatsID},
+		bson.M{"$inc": bson.M{docs: delta}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil || len(tokens) == 0 {
+		return err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(tokens))
+	for _, t := range tokens {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": t}).
+			SetUpdate(bson.M{"$inc": bson.M{field: delta}}).
+			SetUpsert(true))
+	}
+	_, err = r.tokens.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/reaction"
+)
+
+// DeletionMode decides what happens to a deleted author's posts
+type DeletionMode string
+
+const (
+	// DeletionTransfer hands the posts and series over to another author
+	DeletionTransfer DeletionMode = "transfer"
+	// DeletionAnonymize keeps the posts but detaches them from any author
+	DeletionAnonymize DeletionMode = "anonymize"
+	// DeletionDelete moves the posts to the trash and deletes the series
+	DeletionDelete DeletionMode = "delete"
+)
+
+// Valid reports whether m is a known deletion mode
+func (m DeletionMode) Valid() bool {
+	return m == DeletionTransfer || m == DeletionAnonymize || m == DeletionDelete
+}
+
+var (
+	ErrAuthorNotFound        = errors.New("author not found")
+	ErrInvalidDeletionMode   = errors.New("mode must be transfer, anonymize or delete")
+	ErrInvalidTransferTarget = errors.New("transfer_to must reference another existing author")
+)
+
+// DeletionOptions configures an author deletion
+type DeletionOptions struct {
+	Mode       DeletionMode
+	TransferTo primitive.ObjectID // Required when Mode is DeletionTransfer
+}
+
+// DeletionReport summarises what an author deletion changed
+type DeletionReport struct {
+	Mode                   DeletionMode `json:"mode"`
+	PostsTransferred       int64        `json:"posts_transferred"`
+	PostsAnonymized        int64        `json:"posts_anonymized"`
+	PostsDeleted           int64        `json:"posts_deleted"`
+	SeriesAffected         int64        `json:"series_affected"`
+	LikesRemoved           int64        `json:"likes_removed"`
+	FollowsRemoved         int64        `json:"follows_removed"`
+	BookmarksRemoved       int64        `json:"bookmarks_removed"`
+	ReadingListsRemoved    int64        `json:"reading_lists_removed"`
+	CoAuthorCreditsRemoved int64        `json:"co_author_credits_removed"`
+	ReactionsRemoved       int64        `json:"reactions_removed"`
+	CommentsDetached       int64        `json:"comments_detached"`
+	NotificationsRemoved   int64        `json:"notifications_removed"`
+	ReportsRemoved         int64        `json:"reports_removed"`
+	DigestRemoved          int64        `json:"digest_subscriptions_removed"`
+	PreferencesRemoved     int64        `json:"notification_preferences_removed"`
+	Transactional          bool         `json:"transactional"` // False when Mongo does not support transactions
+}
+
+// IAuthorDeletionService deletes an author and cleans up everything that references them
+type IAuthorDeletionService interface {
+	DeleteAuthor(ctx context.Context, authorID primitive.ObjectID, opts DeletionOptions) (*DeletionReport, error)
+}
+
+// AuthorDeletionService removes an author account and cascades the change
+// to their posts, series, likes, follows, bookmarks, reading lists,
+// co-author credits, reactions, comments, notifications, reports and settings
+type AuthorDeletionService struct {
+	client        *mongo.Client
+	authors       *mongo.Collection
+	blogs         *mongo.Collection
+	comments      *mongo.Collection
+	series        *mongo.Collection
+	follows       *mongo.Collection
+	bookmarks     *mongo.Collection
+	readingLists  *mongo.Collection
+	reactions     *mongo.Collection
+	notifications *mongo.Collection
+	preferences   *mongo.Collection
+	digests       *mongo.Collection
+	reports       *mongo.Collection
+}
+
+func NewAuthorDeletionService(client *mongo.Client, db *mongo.Database) *AuthorDeletionService {
+	return &AuthorDeletionService{
+		client:        client,
+		authors:       db.Collection("authors"),
+		blogs:         db.Collection("blogs"),
+		comments:      db.Collection("comments"),
+		series:        db.Collection("series"),
+		follows:       db.Collection("follows"),
+		bookmarks:     db.Collection("bookmarks"),
+		readingLists:  db.Collection("reading_lists"),
+		reactions:     db.Collection("reactions"),
+		notifications: db.Collection("notifications"),
+		preferences:   db.Collection("notification_preferences"),
+		digests:       db.Collection("digest_subscriptions"),
+		reports:       db.Collection("reports"),
+	}
+}
+
+// DeleteAuthor runs the cascade inside a transaction. Standalone Mongo
+// servers do not support transactions, in which case the same steps run
+// one after another and the report is marked as non-transactional.
+func (s *AuthorDeletionService) DeleteAuthor(ctx context.Context, authorID primitive.ObjectID, opts DeletionOptions) (*DeletionReport, error) {
+	if !opts.Mode.Valid() {
+		return nil, ErrInvalidDeletionMode
+	}
+	if opts.Mode == DeletionTransfer && (opts.TransferTo.IsZero() || opts.TransferTo == authorID) {
+		return nil, ErrInvalidTransferTarget
+	}
+
+	session, err := s.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return s.cascade(sc, authorID, opts)
+	})
+	if err == nil {
+		report := result.(*DeletionReport)
+		report.Transactional = true
+		return report, nil
+	}
+	if !transactionsUnsupported(err) {
+		return nil, err
+	}
+
+	return s.cascade(ctx, authorID, opts)
+}
+
+func (s *AuthorDeletionService) cascade(ctx context.Context, authorID primitive.ObjectID, opts DeletionOptions) (*DeletionReport, error) {
+	if err := s.authors.FindOne(ctx, bson.M{"_id": authorID}).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAuthorNotFound
+		}
+		return nil, err
+	}
+
+	report := &DeletionReport{Mode: opts.Mode}
+	owned := bson.M{"author_id": authorID}
+
+	switch opts.Mode {
+	case DeletionTransfer:
+		if err := s.authors.FindOne(ctx, bson.M{"_id": opts.TransferTo}).Err(); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, ErrInvalidTransferTarget
+			}
+			return nil, err
+		}
+
+		res, err := s.blogs.UpdateMany(ctx, owned, bson.M{"$set": bson.M{"author_id": opts.TransferTo}})
+		if err != nil {
+			return nil, err
+		}
+		report.PostsTransferred = res.ModifiedCount
+
+		// The new owner no longer needs a co-author credit on posts they now own
+		if _, err := s.blogs.UpdateMany(ctx,
+			bson.M{"author_id": opts.TransferTo, "co_authors.author_id": opts.TransferTo},
+			bson.M{"$pull": bson.M{"co_authors": bson.M{"author_id": opts.TransferTo}}},
+		); err != nil {
+			return nil, err
+		}
+
+		seriesRes, err := s.series.UpdateMany(ctx, owned, bson.M{"$set": bson.M{"author_id": opts.TransferTo}})
+		if err != nil {
+			return nil, err
+		}
+		report.SeriesAffected = seriesRes.ModifiedCount
+
+	case DeletionAnonymize:
+		res, err := s.blogs.UpdateMany(ctx, owned, bson.M{"$set": bson.M{"author_id": primitive.NilObjectID}})
+		if err != nil {
+			return nil, err
+		}
+		report.PostsAnonymized = res.ModifiedCount
+
+		seriesRes, err := s.series.UpdateMany(ctx, owned, bson.M{"$set": bson.M{"author_id": primitive.NilObjectID}})
+		if err != nil {
+			return nil, err
+		}
+		report.SeriesAffected = seriesRes.ModifiedCount
+
+	case DeletionDelete:
+		// Posts go to the trash so the purge job cascades to comments and shares
+		res, err := s.blogs.UpdateMany(ctx,
+			bson.M{"author_id": authorID, "deleted_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		report.PostsDeleted = res.ModifiedCount
+
+		seriesRes, err := s.series.DeleteMany(ctx, owned)
+		if err != nil {
+			return nil, err
+		}
+		report.SeriesAffected = seriesRes.DeletedCount
+	}
+
+	likes, err := s.blogs.UpdateMany(ctx, bson.M{"likes": authorID}, bson.M{"$pull": bson.M{"likes": authorID}})
+	if err != nil {
+		return nil, err
+	}
+	report.LikesRemoved = likes.ModifiedCount
+
+	credits, err := s.blogs.UpdateMany(ctx,
+		bson.M{"co_authors.author_id": authorID},
+		bson.M{"$pull": bson.M{"co_authors": bson.M{"author_id": authorID}}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	report.CoAuthorCreditsRemoved = credits.ModifiedCount
+
+	if report.FollowsRemoved, err = s.removeFollows(ctx, authorID); err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := s.bookmarks.DeleteMany(ctx, bson.M{"author_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	report.BookmarksRemoved = bookmarks.DeletedCount
+
+	lists, err := s.readingLists.DeleteMany(ctx, bson.M{"owner_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	report.ReadingListsRemoved = lists.DeletedCount
+
+	if report.ReactionsRemoved, err = s.removeReactions(ctx, authorID); err != nil {
+		return nil, err
+	}
+
+	// Comments stay in their threads but no longer point at the deleted account
+	detached, err := s.comments.UpdateMany(ctx, bson.M{"author_id": authorID}, bson.M{
+		"$unset": bson.M{"author_id": "", "avatar_url": ""},
+		"$set":   bson.M{"verified": false},
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.CommentsDetached = detached.ModifiedCount
+
+	notifications, err := s.notifications.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"recipient_id": authorID},
+		bson.M{"actor_id": authorID},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	report.NotificationsRemoved = notifications.DeletedCount
+
+	reports, err := s.reports.DeleteMany(ctx, bson.M{"reporter_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	report.ReportsRemoved = reports.DeletedCount
+
+	digests, err := s.digests.DeleteOne(ctx, bson.M{"_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	report.DigestRemoved = digests.DeletedCount
+
+	prefs, err := s.preferences.DeleteOne(ctx, bson.M{"_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	report.PreferencesRemoved = prefs.DeletedCount
+
+	if _, err := s.authors.DeleteOne(ctx, bson.M{"_id": authorID}); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// removeFollows deletes every follow to or from the author and takes them
+// out of the other authors' follower and following counts
+func (s *AuthorDeletionService) removeFollows(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	followees, err := s.follows.Distinct(ctx, "followee_id", bson.M{"follower_id": authorID})
+	if err != nil {
+		return 0, err
+	}
+	if len(followees) > 0 {
+		if _, err := s.authors.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": followees}},
+			bson.M{"$inc": bson.M{"follower_count": -1}},
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	followers, err := s.follows.Distinct(ctx, "follower_id", bson.M{"followee_id": authorID})
+	if err != nil {
+		return 0, err
+	}
+	if len(followers) > 0 {
+		if _, err := s.authors.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": followers}},
+			bson.M{"$inc": bson.M{"following_count": -1}},
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := s.follows.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"follower_id": authorID},
+		bson.M{"followee_id": authorID},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// removeReactions deletes the author's reactions and takes them out of the
+// counts embedded in the posts and comments they reacted to
+func (s *AuthorDeletionService) removeReactions(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	cursor, err := s.reactions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"author_id": authorID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"target_type": "$target_type", "target_id": "$target_id", "kind": "$kind"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var rows []struct {
+		Key struct {
+			TargetType reaction.TargetType `bson:"target_type"`
+			TargetID   primitive.ObjectID  `bson:"target_id"`
+			Kind       reaction.Kind       `bson:"kind"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, err
+	}
+
+	updates := map[reaction.TargetType][]mongo.WriteModel{}
+	for _, row := range rows {
+		updates[row.Key.TargetType] = append(updates[row.Key.TargetType], mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": row.Key.TargetID}).
+			SetUpdate(bson.M{"$inc": bson.M{"reactions." + string(row.Key.Kind): -row.Count}}))
+	}
+	targets := map[reaction.TargetType]*mongo.Collection{
+		reaction.TargetBlog:    s.blogs,
+		reaction.TargetComment: s.comments,
+	}
+	for targetType, models := range updates {
+		col, ok := targets[targetType]
+		if !ok {
+			continue
+		}
+		if _, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := s.reactions.DeleteMany(ctx, bson.M{"author_id": authorID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// transactionsUnsupported reports whether err means the server is a
+// standalone instance that cannot run multi-document transactions
+func transactionsUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 20 { // IllegalOperation
+		return true
+	}
+	return strings.Contains(err.Error(), "Transaction numbers are only allowed")
+}
@@ -0,0 +1,527 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"razorblog-backend/internal/models/audit"
+	"razorblog-backend/internal/models/author"
+	"razorblog-backend/internal/models/blog"
+	"razorblog-backend/internal/models/bookmark"
+	models "razorblog-backend/internal/models/comment"
+	"razorblog-backend/internal/models/export"
+	"razorblog-backend/internal/models/follow"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/models/reaction"
+)
+
+const (
+	// exportJobTimeout bounds how long building one archive may take
+	exportJobTimeout = 30 * time.Minute
+	// exportStaleAfter is when a pending job is taken to have died with
+	// its server; it allows for time spent waiting for a free slot
+	exportStaleAfter = 2 * time.Hour
+	// maxRunningExports caps how many archives are built at once
+	maxRunningExports = 2
+)
+
+var (
+	ErrExportJobNotFound = errors.New("export job not found")
+	ErrExportNotReady    = errors.New("export is not ready yet")
+)
+
+// ExportBundle is everything stored about one author
+type ExportBundle struct {
+	GeneratedAt     time.Time                    `json:"generated_at"`
+	Profile         *author.Author               `json:"profile"`
+	Posts           []*blog.Blog                 `json:"posts"`
+	CoAuthoredPosts []*blog.Blog                 `json:"co_authored_posts"`
+	Comments        []*models.Comment            `json:"comments"`
+	LikedPosts      []LikedItem                  `json:"liked_posts"`
+	LikedComments   []LikedItem                  `json:"liked_comments"`
+	Reactions       []*reaction.Reaction         `json:"reactions"`
+	Bookmarks       []*bookmark.Bookmark         `json:"bookmarks"`
+	ReadingLists    []*bookmark.ReadingList      `json:"reading_lists"`
+	Following       []*follow.Follow             `json:"following"`
+	Followers       []*follow.Follow             `json:"followers"`
+	Notifications   []*notification.Notification `json:"notifications"`
+	AuditEvents     []*audit.Event               `json:"audit_events"`
+}
+
+// LikedItem identifies something the author liked
+type LikedItem struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id"`
+	BlogID primitive.ObjectID `json:"blog_id,omitempty" bson:"blog_id,omitempty"`
+	Title  string             `json:"title,omitempty" bson:"title,omitempty"`
+}
+
+// IDataExportService assembles personal data exports
+type IDataExportService interface {
+	Collect(ctx context.Context, authorID primitive.ObjectID) (*ExportBundle, error)
+	IsLarge(ctx context.Context, authorID primitive.ObjectID) (bool, error)
+	StartJob(ctx context.Context, authorID primitive.ObjectID) (*export.Job, error)
+	GetJob(ctx context.Context, jobID, authorID primitive.ObjectID) (*export.Job, error)
+	DownloadJob(ctx context.Context, job *export.Job, w io.Writer) error
+}
+
+// DataExportService collects an author's personal data and packages it as
+// a ZIP of JSON and Markdown files. Accounts above the async threshold are
+// exported by a background job whose archive is kept in GridFS until the
+// retention period runs out.
+type DataExportService struct {
+	authors        *mongo.Collection
+	blogs          *mongo.Collection
+	comments       *mongo.Collection
+	reactions      *mongo.Collection
+	bookmarks      *mongo.Collection
+	readingLists   *mongo.Collection
+	follows        *mongo.Collection
+	notifications  *mongo.Collection
+	auditEvents    *mongo.Collection
+	jobs           *mongo.Collection
+	bucket         *gridfs.Bucket
+	asyncThreshold int64
+	retention      time.Duration
+	slots          chan struct{} // One token per archive being built
+}
+
+func NewDataExportService(db *mongo.Database, asyncThreshold int64, retention time.Duration) (*DataExportService, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("exports"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataExportService{
+		authors:        db.Collection("authors"),
+		blogs:          db.Collection("blogs"),
+		comments:       db.Collection("comments"),
+		reactions:      db.Collection("reactions"),
+		bookmarks:      db.Collection("bookmarks"),
+		readingLists:   db.Collection("reading_lists"),
+		follows:        db.Collection("follows"),
+		notifications:  db.Collection("notifications"),
+		auditEvents:    db.Collection("audit_events"),
+		jobs:           db.Collection("export_jobs"),
+		bucket:         bucket,
+		asyncThreshold: asyncThreshold,
+		retention:      retention,
+		slots:          make(chan struct{}, maxRunningExports),
+	}, nil
+}
+
+// EnsureIndexes allows one pending job per author and speeds up the
+// lookups of active and expired jobs
+func (s *DataExportService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.jobs.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "author_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": export.JobPending}),
+		},
+		{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+// Collect gathers the author's profile, posts (including trashed ones) and
+// posts they co-wrote, their comments, likes, reactions, bookmarks, reading
+// lists, follows, notifications and audit events
+func (s *DataExportService) Collect(ctx context.Context, authorID primitive.ObjectID) (*ExportBundle, error) {
+	var a author.Author
+	if err := s.authors.FindOne(ctx, bson.M{"_id": authorID}).Decode(&a); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAuthorNotFound
+		}
+		return nil, err
+	}
+	a.Password = ""
+
+	bundle := &ExportBundle{GeneratedAt: time.Now(), Profile: &a}
+	byDate := options.Find().SetSort(bson.M{"created_at": 1})
+
+	if err := findAll(ctx, s.blogs, bson.M{"author_id": authorID}, byDate, &bundle.Posts); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.blogs, bson.M{"co_authors.author_id": authorID}, byDate, &bundle.CoAuthoredPosts); err != nil {
+		return nil, err
+	}
+	// Comments written before registered comments carried an author ID
+	// are only linked to the author by name. Anonymous and detached
+	// comments are stored unverified, so a reader who borrowed the name
+	// does not end up in the export
+	comments := bson.M{"$or": bson.A{
+		bson.M{"author_id": authorID},
+		bson.M{
+			"username":  a.Name,
+			"author_id": bson.M{"$exists": false},
+			"verified":  bson.M{"$ne": false},
+		},
+	}}
+	if err := findAll(ctx, s.comments, comments, byDate, &bundle.Comments); err != nil {
+		return nil, err
+	}
+
+	titles := options.Find().SetProjection(bson.M{"_id": 1, "title": 1})
+	if err := findAll(ctx, s.blogs, bson.M{"likes": authorID}, titles, &bundle.LikedPosts); err != nil {
+		return nil, err
+	}
+	blogIDs := options.Find().SetProjection(bson.M{"_id": 1, "blog_id": 1})
+	if err := findAll(ctx, s.comments, bson.M{"liked_by": a.Name}, blogIDs, &bundle.LikedComments); err != nil {
+		return nil, err
+	}
+
+	if err := findAll(ctx, s.reactions, bson.M{"author_id": authorID}, byDate, &bundle.Reactions); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.bookmarks, bson.M{"author_id": authorID}, byDate, &bundle.Bookmarks); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.readingLists, bson.M{"owner_id": authorID}, byDate, &bundle.ReadingLists); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.follows, bson.M{"follower_id": authorID}, byDate, &bundle.Following); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.follows, bson.M{"followee_id": authorID}, byDate, &bundle.Followers); err != nil {
+		return nil, err
+	}
+	if err := findAll(ctx, s.notifications, bson.M{"recipient_id": authorID}, byDate, &bundle.Notifications); err != nil {
+		return nil, err
+	}
+
+	if err := findAll(ctx, s.auditEvents, bson.M{"actor_id": authorID}, byDate, &bundle.AuditEvents); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// IsLarge reports whether an export should run as a background job
+func (s *DataExportService) IsLarge(ctx context.Context, authorID primitive.ObjectID) (bool, error) {
+	posts, err := s.blogs.CountDocuments(ctx, bson.M{"author_id": authorID})
+	if err != nil {
+		return false, err
+	}
+	events, err := s.auditEvents.CountDocuments(ctx, bson.M{"actor_id": authorID})
+	if err != nil {
+		return false, err
+	}
+	return posts+events > s.asyncThreshold, nil
+}
+
+// StartJob returns the author's pending or still downloadable export, or
+// records a new pending one and builds it in the background
+func (s *DataExportService) StartJob(ctx context.Context, authorID primitive.ObjectID) (*export.Job, error) {
+	now := time.Now()
+	if err := s.failStale(ctx, bson.M{"author_id": authorID}, now); err != nil {
+		return nil, err
+	}
+
+	active := bson.M{"author_id": authorID, "$or": bson.A{
+		bson.M{"status": export.JobPending},
+		bson.M{"status": export.JobCompleted, "expires_at": bson.M{"$gt": now}},
+	}}
+	fresh := &export.Job{
+		ID:        primitive.NewObjectID(),
+		AuthorID:  authorID,
+		Status:    export.JobPending,
+		CreatedAt: now,
+	}
+
+	var job export.Job
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := s.jobs.FindOneAndUpdate(ctx, active, bson.M{"$setOnInsert": fresh}, opts).Decode(&job)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request created the pending job first
+		err = s.jobs.FindOne(ctx, active).Decode(&job)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if job.ID == fresh.ID {
+		go s.runJob(&job)
+	}
+	return &job, nil
+}
+
+func (s *DataExportService) runJob(job *export.Job) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportJobTimeout)
+	defer cancel()
+
+	fail := func(err error) {
+		log.Printf("⚠️ Data export %s failed: %v", job.ID.Hex(), err)
+		_, _ = s.jobs.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+			"status":     export.JobFailed,
+			"error":      err.Error(),
+			"expires_at": time.Now().Add(s.retention),
+		}})
+	}
+
+	bundle, err := s.Collect(ctx, job.AuthorID)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	// The archive is written straight into GridFS rather than built in memory
+	fileID := primitive.NewObjectID()
+	upload, err := s.bucket.OpenUploadStreamWithID(fileID, ExportFilename(job.AuthorID))
+	if err != nil {
+		fail(err)
+		return
+	}
+	_ = upload.SetWriteDeadline(time.Now().Add(exportJobTimeout))
+
+	out := &countingWriter{w: upload}
+	if err := WriteZip(out, bundle); err != nil {
+		_ = upload.Abort()
+		fail(err)
+		return
+	}
+	if err := upload.Close(); err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	_, err = s.jobs.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+		"status":       export.JobCompleted,
+		"file_id":      fileID,
+		"size":         out.n,
+		"completed_at": now,
+		"expires_at":   now.Add(s.retention),
+	}})
+	if err != nil {
+		_ = s.bucket.DeleteContext(ctx, fileID)
+		fail(err)
+	}
+}
+
+// PurgeExpired deletes jobs past their expiry together with their
+// archives, and reports how many jobs it removed
+func (s *DataExportService) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	if err := s.failStale(ctx, bson.M{}, now); err != nil {
+		return 0, err
+	}
+
+	var expired []*export.Job
+	if err := findAll(ctx, s.jobs, bson.M{"expires_at": bson.M{"$lte": now}}, nil, &expired); err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(expired))
+	for _, job := range expired {
+		if job.FileID != nil {
+			err := s.bucket.DeleteContext(ctx, *job.FileID)
+			if err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+				return 0, err
+			}
+		}
+		ids = append(ids, job.ID)
+	}
+
+	res, err := s.jobs.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// failStale marks pending jobs matching filter that outlived
+// exportStaleAfter as failed, so they neither block new exports nor linger
+func (s *DataExportService) failStale(ctx context.Context, filter bson.M, now time.Time) error {
+	stale := bson.M{"status": export.JobPending, "created_at": bson.M{"$lt": now.Add(-exportStaleAfter)}}
+	for k, v := range filter {
+		stale[k] = v
+	}
+	_, err := s.jobs.UpdateMany(ctx, stale, bson.M{"$set": bson.M{
+		"status":     export.JobFailed,
+		"error":      "export timed out",
+		"expires_at": now.Add(s.retention),
+	}})
+	return err
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// GetJob returns an export job belonging to the author
+func (s *DataExportService) GetJob(ctx context.Context, jobID, authorID primitive.ObjectID) (*export.Job, error) {
+	var job export.Job
+	err := s.jobs.FindOne(ctx, bson.M{"_id": jobID, "author_id": authorID}).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrExportJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DownloadJob streams the archive of a completed job
+func (s *DataExportService) DownloadJob(ctx context.Context, job *export.Job, w io.Writer) error {
+	if job.Status != export.JobCompleted || job.FileID == nil {
+		return ErrExportNotReady
+	}
+	_, err := s.bucket.DownloadToStream(*job.FileID, w)
+	return err
+}
+
+// ExportFilename is the download name of an author's archive
+func ExportFilename(authorID primitive.ObjectID) string {
+	return "razorblog-export-" + authorID.Hex() + ".zip"
+}
+
+// WriteZip packages a bundle as JSON files plus one Markdown file per post
+func WriteZip(w io.Writer, b *ExportBundle) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", b.Profile},
+		{"posts.json", nonNil(b.Posts)},
+		{"co_authored_posts.json", nonNil(b.CoAuthoredPosts)},
+		{"comments.json", nonNil(b.Comments)},
+		{"likes.json", map[string]interface{}{
+			"posts":    nonNil(b.LikedPosts),
+			"comments": nonNil(b.LikedComments),
+		}},
+		{"reactions.json", nonNil(b.Reactions)},
+		{"bookmarks.json", nonNil(b.Bookmarks)},
+		{"reading_lists.json", nonNil(b.ReadingLists)},
+		{"follows.json", map[string]interface{}{
+			"following": nonNil(b.Following),
+			"followers": nonNil(b.Followers),
+		}},
+		{"notifications.json", nonNil(b.Notifications)},
+		{"audit_events.json", nonNil(b.AuditEvents)},
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range b.Posts {
+		fw, err := zw.Create("posts/" + p.ID.Hex() + ".md")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, postMarkdown(p)); err != nil {
+			return err
+		}
+	}
+
+	fw, err := zw.Create("README.md")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(fw, exportReadme(b)); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func postMarkdown(p *blog.Blog) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", p.Title)
+	fmt.Fprintf(&sb, "- Type: %s\n- Category: %s\n- Created: %s\n- Updated: %s\n",
+		p.Type, p.Category, p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+	if p.Status != "" {
+		fmt.Fprintf(&sb, "- Decision status: %s\n", p.Status)
+	}
+	if p.DeletedAt != nil {
+		fmt.Fprintf(&sb, "- In trash since: %s\n", p.DeletedAt.Format(time.RFC3339))
+	}
+	sb.WriteString("\n")
+
+	if tpl, ok := blog.TemplateFor(p.Type); ok && len(p.Sections) > 0 {
+		for _, section := range tpl.Sections {
+			fmt.Fprintf(&sb, "## %s\n\n%s\n\n", section.Title, p.Sections[section.Key])
+		}
+	}
+	if p.Content != "" {
+		sb.WriteString(p.Content)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func exportReadme(b *ExportBundle) string {
+	return fmt.Sprintf(`# RazorBlog data export
+
+Generated %s for %s.
+
+- profile.json: your account details (password hash excluded)
+- posts.json and posts/*.md: %d posts, including any in the trash
+- co_authored_posts.json: %d posts you are credited on as a co-author
+- comments.json: %d comments you wrote
+- likes.json: posts and comments you liked
+- reactions.json: your reactions to posts and comments
+- bookmarks.json and reading_lists.json: posts you saved and your reading lists
+- follows.json: authors you follow and authors following you
+- notifications.json: notifications sent to you
+- audit_events.json: %d login and account events
+
+Posts are exported as they currently are; earlier revisions are not stored.
+`, b.GeneratedAt.Format(time.RFC3339), b.Profile.Name, len(b.Posts), len(b.CoAuthoredPosts), len(b.Comments), len(b.AuditEvents))
+}
+
+// findAll decodes every document matching filter into out
+func findAll(ctx context.Context, col *mongo.Collection, filter bson.M, opts *options.FindOptions, out interface{}) error {
+	cursor, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, out)
+}
+
+// nonNil keeps empty collections as [] rather than null in the JSON files
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
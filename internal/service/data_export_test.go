@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"razorblog-backend/internal/models/export"
+)
+
+// commands lists the command name and collection of every command the
+// mock deployment received
+func commands(mt *mtest.T) []string {
+	var sent []string
+	for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+		if e.CommandName == "endSessions" {
+			continue
+		}
+		sent = append(sent, e.CommandName+" "+e.Command.Index(0).Value().StringValue())
+	}
+	return sent
+}
+
+func newExporter(mt *mtest.T) *DataExportService {
+	s, err := NewDataExportService(mt.DB, 200, 24*time.Hour)
+	if err != nil {
+		mt.Fatal(err)
+	}
+	return s
+}
+
+func TestCollect(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Gathers everything stored about the author", func(mt *mtest.T) {
+		authorID := primitive.NewObjectID()
+		empty := func(ns string) bson.D { return mtest.CreateCursorResponse(0, "test."+ns, mtest.FirstBatch) }
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.authors", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: authorID}, {Key: "name", Value: "ada"}, {Key: "password", Value: "hash"},
+			}),
+			empty("blogs"), empty("blogs"), empty("comments"), empty("blogs"), empty("comments"),
+			empty("reactions"), empty("bookmarks"), empty("reading_lists"), empty("follows"), empty("follows"),
+			empty("notifications"), empty("audit_events"),
+		)
+
+		var finds []bson.Raw
+		bundle, err := newExporter(mt).Collect(context.Background(), authorID)
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			if e.CommandName == "find" {
+				finds = append(finds, e.Command)
+			}
+		}
+
+		assert.NoError(mt, err)
+		assert.Empty(mt, bundle.Profile.Password)
+
+		var collections []string
+		for _, cmd := range finds {
+			collections = append(collections, cmd.Lookup("find").StringValue())
+		}
+		assert.Equal(mt, []string{
+			"authors", "blogs", "blogs", "comments", "blogs", "comments",
+			"reactions", "bookmarks", "reading_lists", "follows", "follows",
+			"notifications", "audit_events",
+		}, collections)
+
+		// Comments are matched by author ID, and by name only when they
+		// predate author IDs
+		or := finds[3].Lookup("filter", "$or").Array()
+		assert.Equal(mt, authorID, or.Index(0).Value().Document().Lookup("author_id").ObjectID())
+		byName := or.Index(1).Value().Document()
+		assert.Equal(mt, "ada", byName.Lookup("username").StringValue())
+		assert.False(mt, byName.Lookup("author_id", "$exists").Boolean())
+		assert.False(mt, byName.Lookup("verified", "$ne").Boolean())
+	})
+}
+
+func TestStartJob(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	authorID := primitive.NewObjectID()
+	existing := bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "author_id", Value: authorID},
+		{Key: "status", Value: export.JobPending},
+	}
+	staleFailed := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+
+	mt.Run("Reuses the author's active job", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			staleFailed,
+			mtest.CreateSuccessResponse(
+				bson.E{Key: "value", Value: existing},
+				bson.E{Key: "lastErrorObject", Value: bson.D{{Key: "n", Value: 1}, {Key: "updatedExisting", Value: true}}},
+			),
+		)
+
+		job, err := newExporter(mt).StartJob(context.Background(), authorID)
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, existing[0].Value, job.ID)
+		assert.Equal(mt, []string{"update export_jobs", "findAndModify export_jobs"}, commands(mt))
+	})
+
+	mt.Run("Falls back to the job a concurrent request created", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			staleFailed,
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Name: "DuplicateKey", Message: "E11000 duplicate key error"}),
+			mtest.CreateCursorResponse(0, "test.export_jobs", mtest.FirstBatch, existing),
+		)
+
+		job, err := newExporter(mt).StartJob(context.Background(), authorID)
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, existing[0].Value, job.ID)
+		assert.Equal(mt, []string{"update export_jobs", "findAndModify export_jobs", "find export_jobs"}, commands(mt))
+	})
+}
+
+func TestPurgeExpired(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Deletes expired jobs and their archives", func(mt *mtest.T) {
+		jobID, fileID := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, "test.export_jobs", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: jobID}, {Key: "status", Value: export.JobCompleted}, {Key: "file_id", Value: fileID},
+			}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), // exports.files
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}), // exports.chunks
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), // export_jobs
+		)
+
+		purged, err := newExporter(mt).PurgeExpired(context.Background(), time.Now())
+
+		assert.NoError(mt, err)
+		assert.Equal(mt, int64(1), purged)
+		assert.Equal(mt, []string{
+			"update export_jobs",
+			"find export_jobs",
+			"delete exports.files",
+			"delete exports.chunks",
+			"delete export_jobs",
+		}, commands(mt))
+	})
+
+	mt.Run("Does nothing when no job has expired", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, "test.export_jobs", mtest.FirstBatch),
+		)
+
+		purged, err := newExporter(mt).PurgeExpired(context.Background(), time.Now())
+
+		assert.NoError(mt, err)
+		assert.Zero(mt, purged)
+		assert.Equal(mt, []string{"update export_jobs", "find export_jobs"}, commands(mt))
+	})
+}
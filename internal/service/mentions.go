@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"razorblog-backend/internal/mention"
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/repository"
+)
+
+// MentionEvent describes where authors were mentioned and by whom
+type MentionEvent struct {
+	ActorID   *primitive.ObjectID // Nil for anonymous commenters
+	ActorName string
+	BlogID    *primitive.ObjectID
+	CommentID *primitive.ObjectID
+}
+
+// IMentionService resolves @mentions and keeps them in step with authors
+type IMentionService interface {
+	Resolve(text string) (string, []primitive.ObjectID)
+	Notify(ctx context.Context, mentioned []primitive.ObjectID, event MentionEvent) error
+	Rename(ctx context.Context, authorID primitive.ObjectID, name string) error
+	Forget(ctx context.Context, authorID primitive.ObjectID) error
+}
+
+// MentionService links @mentions to author profiles, notifies the
+// mentioned authors and rewrites stored mentions when an author is renamed
+// or deleted
+type MentionService struct {
+	authors       repository.IAuthorRepository
+	notifications INotificationService
+	blogs         *mongo.Collection
+	comments      *mongo.Collection
+}
+
+func NewMentionService(db *mongo.Database, authors repository.IAuthorRepository, notifications INotificationService) *MentionService {
+	return &MentionService{
+		authors:       authors,
+		notifications: notifications,
+		blogs:         db.Collection("blogs"),
+		comments:      db.Collection("comments"),
+	}
+}
+
+// Resolve rewrites the mentions in text as profile links
+func (s *MentionService) Resolve(text string) (string, []primitive.ObjectID) {
+	return mention.Resolve(text, authorDirectory{s.authors})
+}
+
+// Notify sends a mention notification to every mentioned author; the
+// notification service drops the writer's mention of themselves
+func (s *MentionService) Notify(ctx context.Context, mentioned []primitive.ObjectID, event MentionEvent) error {
+	notes := make([]*notification.Notification, 0, len(mentioned))
+	for _, id := range mentioned {
+		notes = append(notes, &notification.Notification{
+			RecipientID: id,
+			Type:        notification.TypeMention,
+			ActorID:     event.ActorID,
+			ActorName:   event.ActorName,
+			BlogID:      event.BlogID,
+			CommentID:   event.CommentID,
+		})
+	}
+	return s.notifications.Notify(ctx, notes...)
+}
+
+// Rename refreshes the visible name in every stored mention of an author
+func (s *MentionService) Rename(ctx context.Context, authorID primitive.ObjectID, name string) error {
+	return s.rewrite(ctx, authorID, func(text string) string {
+		return mention.Relabel(text, authorID, name)
+	}, bson.M{})
+}
+
+// Forget turns every stored mention of a deleted author back into plain text
+func (s *MentionService) Forget(ctx context.Context, authorID primitive.ObjectID) error {
+	return s.rewrite(ctx, authorID, func(text string) string {
+		return mention.Unlink(text, authorID)
+	}, bson.M{"$pull": bson.M{"mentions": authorID}})
+}
+
+// rewrite applies fn to the content (and, for posts, the sections) of every
+// post and comment mentioning the author
+func (s *MentionService) rewrite(ctx context.Context, authorID primitive.ObjectID, fn func(string) string, extra bson.M) error {
+	for _, col := range []*mongo.Collection{s.blogs, s.comments} {
+		cursor, err := col.Find(ctx, bson.M{"mentions": authorID})
+		if err != nil {
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID       primitive.ObjectID `bson:"_id"`
+				Content  string             `bson:"content"`
+				Sections map[string]string  `bson:"sections,omitempty"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+
+			set := bson.M{"content": fn(doc.Content)}
+			for key, text := range doc.Sections {
+				set["sections."+key] = fn(text)
+			}
+			update := bson.M{"$set": set}
+			for op, v := range extra {
+				update[op] = v
+			}
+
+			if _, err := col.UpdateOne(ctx, bson.M{"_id": doc.ID}, update); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			cursor.Close(ctx)
+			return err
+		}
+		cursor.Close(ctx)
+	}
+	return nil
+}
+
+// authorDirectory looks mentioned authors up in the author repository
+type authorDirectory struct {
+	repo repository.IAuthorRepository
+}
+
+func (d authorDirectory) Lookup(names []string, ids []primitive.ObjectID) []mention.Author {
+	authors, err := d.repo.GetAuthorsByNamesOrIDs(names, ids)
+	if err != nil {
+		// Mentions stay as plain text rather than failing the write
+		log.Printf("⚠️ Failed to look up mentioned authors: %v", err)
+		return nil
+	}
+
+	found := make([]mention.Author, 0, len(authors))
+	for _, a := range authors {
+		found = append(found, mention.Author{ID: a.ID, Name: a.Name})
+	}
+	return found
+}
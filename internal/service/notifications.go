@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/notification"
+	"razorblog-backend/internal/repository"
+)
+
+// INotificationService delivers notifications to authors
+type INotificationService interface {
+	Notify(ctx context.Context, notes ...*notification.Notification) error
+}
+
+// NotificationService stores notifications, leaving out the ones nobody
+// should get: authors are not told about their own activity, nor about
+// types they have muted
+type NotificationService struct {
+	repo repository.INotificationRepository
+}
+
+func NewNotificationService(repo repository.INotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// Notify delivers the notifications their recipients want
+func (s *NotificationService) Notify(ctx context.Context, notes ...*notification.Notification) error {
+	recipients := map[notification.Type][]primitive.ObjectID{}
+	for _, n := range notes {
+		recipients[n.Type] = append(recipients[n.Type], n.RecipientID)
+	}
+
+	muted := map[notification.Type]map[primitive.ObjectID]bool{}
+	for t, ids := range recipients {
+		mutedBy, err := s.repo.MutedBy(ctx, t, ids)
+		if err != nil {
+			return err
+		}
+		muted[t] = make(map[primitive.ObjectID]bool, len(mutedBy))
+		for _, id := range mutedBy {
+			muted[t][id] = true
+		}
+	}
+
+	wanted := make([]*notification.Notification, 0, len(notes))
+	for _, n := range notes {
+		if n.ActorID != nil && *n.ActorID == n.RecipientID {
+			continue
+		}
+		if muted[n.Type][n.RecipientID] {
+			continue
+		}
+		wanted = append(wanted, n)
+	}
+	return s.repo.CreateMany(ctx, wanted)
+}
@@ -0,0 +1,116 @@
+package spam
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// minDocs is how many examples of each class the classifier needs before
+// its opinion is taken into account
+const minDocs = 5
+
+// Model holds the training counts of the classifier
+type Model struct {
+	SpamDocs int
+	HamDocs  int
+	Spam     map[string]int // Number of spam documents containing each token
+	Ham      map[string]int // Number of ham documents containing each token
+}
+
+// Classifier is a naive Bayes classifier over the tokens of a comment.
+// It is safe for concurrent use.
+type Classifier struct {
+	mu    sync.RWMutex
+	model Model
+}
+
+func NewClassifier() *Classifier {
+	return &Classifier{model: Model{Spam: map[string]int{}, Ham: map[string]int{}}}
+}
+
+// Replace swaps in a model loaded from storage
+func (c *Classifier) Replace(m *Model) {
+	if m.Spam == nil {
+		m.Spam = map[string]int{}
+	}
+	if m.Ham == nil {
+		m.Ham = map[string]int{}
+	}
+
+	c.mu.Lock()
+	c.model = *m
+	c.mu.Unlock()
+}
+
+// Learn adds (delta 1) or removes (delta -1) one labelled document
+func (c *Classifier) Learn(tokens []string, isSpam bool, delta int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts, docs := c.model.Ham, &c.model.HamDocs
+	if isSpam {
+		counts, docs = c.model.Spam, &c.model.SpamDocs
+	}
+
+	*docs = max(0, *docs+delta)
+	for _, t := range tokens {
+		if n := counts[t] + delta; n > 0 {
+			counts[t] = n
+		} else {
+			delete(counts, t)
+		}
+	}
+}
+
+// Trained reports whether there are enough examples to classify
+func (c *Classifier) Trained() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.model.SpamDocs >= minDocs && c.model.HamDocs >= minDocs
+}
+
+// Probability returns how likely the tokens are to be spam, between 0 and 1.
+// An untrained classifier answers 0.5.
+func (c *Classifier) Probability(tokens []string) float64 {
+	if !c.Trained() {
+		return 0.5
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := c.model
+	total := float64(m.SpamDocs + m.HamDocs)
+	logSpam := math.Log(float64(m.SpamDocs) / total)
+	logHam := math.Log(float64(m.HamDocs) / total)
+
+	// Laplace smoothing keeps unseen tokens from zeroing out a class
+	for _, t := range tokens {
+		logSpam += math.Log(float64(m.Spam[t]+1) / float64(m.SpamDocs+2))
+		logHam += math.Log(float64(m.Ham[t]+1) / float64(m.HamDocs+2))
+	}
+
+	return 1 / (1 + math.Exp(logHam-logSpam))
+}
+
+// Tokenize splits text into the distinct lower-case words the classifier
+// looks at. Very short and very long words carry little signal and are
+// dropped.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) < 3 || len(w) > 24 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
@@ -0,0 +1,200 @@
+package spam
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultBlocklist is used when no blocklist is configured
+var DefaultBlocklist = []string{
+	"viagra", "cialis", "casino", "payday loan", "crypto giveaway",
+	"buy followers", "work from home", "replica watches", "essay writing service",
+}
+
+// Weights of each signal in the combined score
+const (
+	weightBlocklist  = 0.9
+	weightLinks      = 0.8
+	weightDuplicate  = 0.7
+	weightVelocity   = 0.8
+	weightClassifier = 0.9
+)
+
+// Comments shorter than this (once normalised) are too generic to treat
+// repeats as suspicious
+const minFingerprintLength = 20
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// Settings tune the scorer
+type Settings struct {
+	Threshold       float64       // Comments scoring at or above this go to moderation
+	Blocklist       []string      // Words and phrases that mark a comment as spam
+	MaxLinks        int           // Links a comment may contain before it looks spammy
+	VelocityLimit   int           // Comments allowed per IP or username within VelocityWindow; 0 disables the check
+	VelocityWindow  time.Duration // Period over which posting velocity is measured
+	DuplicateWindow time.Duration // How far back identical comments are looked for
+}
+
+// History answers questions about recent comments
+type History interface {
+	CountByFingerprint(ctx context.Context, fingerprint string, since time.Time) (int64, error)
+	CountRecent(ctx context.Context, ip, username string, since time.Time) (int64, error)
+}
+
+// Store persists the classifier's training
+type Store interface {
+	Load(ctx context.Context) (*Model, error)
+	Add(ctx context.Context, tokens []string, isSpam bool, delta int) error
+}
+
+// Input is the comment being scored
+type Input struct {
+	Content  string
+	Username string
+	IP       string
+}
+
+// Result is the verdict on a comment
+type Result struct {
+	Score       float64  // Combined spam score between 0 and 1
+	Reasons     []string // Signals that contributed to the score
+	Fingerprint string   // Fingerprint of the normalised content
+}
+
+// Scorer rates comments for spam using only local signals
+type Scorer struct {
+	settings   Settings
+	history    History
+	store      Store
+	classifier *Classifier
+}
+
+func NewScorer(settings Settings, history History, store Store) *Scorer {
+	if settings.Blocklist == nil {
+		settings.Blocklist = DefaultBlocklist
+	}
+	return &Scorer{
+		settings:   settings,
+		history:    history,
+		store:      store,
+		classifier: NewClassifier(),
+	}
+}
+
+// Load restores the classifier's training from the store
+func (s *Scorer) Load(ctx context.Context) error {
+	m, err := s.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	s.classifier.Replace(m)
+	return nil
+}
+
+// Score rates a comment. Signals are combined as a noisy-OR, so any one
+// strong signal is enough to flag a comment while weak ones add up.
+func (s *Scorer) Score(ctx context.Context, in Input) (*Result, error) {
+	res := &Result{Fingerprint: Fingerprint(in.Content)}
+	notSpam := 1.0
+	add := func(weight, strength float64, reason string) {
+		if strength <= 0 {
+			return
+		}
+		notSpam *= 1 - weight*math.Min(strength, 1)
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	if term := s.blocked(in.Content + " " + in.Username); term != "" {
+		add(weightBlocklist, 1, fmt.Sprintf("contains blocked term %q", term))
+	}
+
+	if links := len(linkPattern.FindAllString(in.Content, -1)); links > 0 {
+		words := len(strings.Fields(in.Content))
+		density := float64(links) / float64(max(words, 1))
+		strength := math.Max(float64(links)/float64(max(s.settings.MaxLinks, 1))-0.5, density*3)
+		add(weightLinks, strength, fmt.Sprintf("%d links in %d words", links, words))
+	}
+
+	if len(normalize(in.Content)) >= minFingerprintLength {
+		n, err := s.history.CountByFingerprint(ctx, res.Fingerprint, time.Now().Add(-s.settings.DuplicateWindow))
+		if err != nil {
+			return nil, err
+		}
+		add(weightDuplicate, float64(n)/2, fmt.Sprintf("posted %d times recently", n+1))
+	}
+
+	if s.settings.VelocityLimit > 0 {
+		n, err := s.history.CountRecent(ctx, in.IP, in.Username, time.Now().Add(-s.settings.VelocityWindow))
+		if err != nil {
+			return nil, err
+		}
+		if n >= int64(s.settings.VelocityLimit) {
+			add(weightVelocity, 1, fmt.Sprintf("%d comments in %s", n+1, s.settings.VelocityWindow))
+		}
+	}
+
+	if s.classifier.Trained() {
+		p := s.classifier.Probability(Tokenize(in.Content + " " + in.Username))
+		add(weightClassifier, 2*p-1, fmt.Sprintf("classifier spam probability %.2f", p))
+	}
+
+	res.Score = 1 - notSpam
+	return res, nil
+}
+
+// Flagged reports whether a result should be held for moderation
+func (s *Scorer) Flagged(res *Result) bool {
+	return res.Score >= s.settings.Threshold
+}
+
+// Train teaches the classifier that a comment is spam or ham. Passing
+// forget undoes an earlier Train with the same arguments, so a moderator
+// can correct a mislabelled comment.
+func (s *Scorer) Train(ctx context.Context, content, username string, isSpam, forget bool) error {
+	delta := 1
+	if forget {
+		delta = -1
+	}
+
+	tokens := Tokenize(content + " " + username)
+	if err := s.store.Add(ctx, tokens, isSpam, delta); err != nil {
+		return err
+	}
+	s.classifier.Learn(tokens, isSpam, delta)
+	return nil
+}
+
+// blocked returns the first blocklisted term found in text
+func (s *Scorer) blocked(text string) string {
+	padded := " " + normalize(text) + " "
+	for _, term := range s.settings.Blocklist {
+		term = normalize(term)
+		if term != "" && strings.Contains(padded, " "+term+" ") {
+			return term
+		}
+	}
+	return ""
+}
+
+// Fingerprint identifies comments with the same wording regardless of
+// case, punctuation and spacing
+func Fingerprint(content string) string {
+	sum := sha1.Sum([]byte(normalize(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize lower-cases text and reduces it to words separated by single spaces
+func normalize(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
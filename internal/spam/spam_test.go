@@ -0,0 +1,93 @@
+package spam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHistory reports fixed counts for duplicate and velocity checks
+type fakeHistory struct {
+	duplicates int64
+	recent     int64
+}
+
+func (f fakeHistory) CountByFingerprint(context.Context, string, time.Time) (int64, error) {
+	return f.duplicates, nil
+}
+
+func (f fakeHistory) CountRecent(context.Context, string, string, time.Time) (int64, error) {
+	return f.recent, nil
+}
+
+// memoryStore keeps training in memory
+type memoryStore struct{}
+
+func (memoryStore) Load(context.Context) (*Model, error)           { return &Model{}, nil }
+func (memoryStore) Add(context.Context, []string, bool, int) error { return nil }
+
+func newTestScorer(h History) *Scorer {
+	return NewScorer(Settings{
+		Threshold:       0.7,
+		MaxLinks:        2,
+		VelocityLimit:   5,
+		VelocityWindow:  10 * time.Minute,
+		DuplicateWindow: 24 * time.Hour,
+	}, h, memoryStore{})
+}
+
+func TestScore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ALLOW: Ordinary comment", func(t *testing.T) {
+		s := newTestScorer(fakeHistory{})
+		res, err := s.Score(ctx, Input{Content: "Thanks, the section on indexes cleared things up for me.", Username: "ana"})
+
+		assert.NoError(t, err)
+		assert.False(t, s.Flagged(res))
+		assert.Empty(t, res.Reasons)
+	})
+
+	t.Run("FLAG: Blocklisted phrase", func(t *testing.T) {
+		s := newTestScorer(fakeHistory{})
+		res, _ := s.Score(ctx, Input{Content: "Best online CASINO, payday-loan offers inside", Username: "bob"})
+
+		assert.True(t, s.Flagged(res))
+	})
+
+	t.Run("FLAG: Link stuffing", func(t *testing.T) {
+		s := newTestScorer(fakeHistory{})
+		res, _ := s.Score(ctx, Input{Content: "see http://a.example http://b.example www.c.example", Username: "bob"})
+
+		assert.True(t, s.Flagged(res))
+	})
+
+	t.Run("FLAG: Repeated content posted quickly", func(t *testing.T) {
+		s := newTestScorer(fakeHistory{duplicates: 2, recent: 6})
+		res, _ := s.Score(ctx, Input{Content: "Great article, check out my profile for more", Username: "bob"})
+
+		assert.True(t, s.Flagged(res))
+		assert.Len(t, res.Reasons, 2)
+	})
+
+	t.Run("FLAG: Classifier learns from moderators", func(t *testing.T) {
+		s := newTestScorer(fakeHistory{})
+		for i := 0; i < minDocs; i++ {
+			assert.NoError(t, s.Train(ctx, "cheap pills discount pharmacy order today", "", true, false))
+			assert.NoError(t, s.Train(ctx, "interesting point about database migrations", "", false, false))
+		}
+
+		spammy, _ := s.Score(ctx, Input{Content: "discount pharmacy pills", Username: "x"})
+		legit, _ := s.Score(ctx, Input{Content: "migrations point", Username: "y"})
+
+		assert.True(t, s.Flagged(spammy))
+		assert.False(t, s.Flagged(legit))
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, Fingerprint("Nice post!!"), Fingerprint("  nice   POST "))
+	assert.NotEqual(t, Fingerprint("Nice post"), Fingerprint("Nice posts"))
+}
@@ -0,0 +1,105 @@
+// Package trending ranks posts by recent engagement
+package trending
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/analytics"
+)
+
+// Weights say how much each kind of engagement is worth. Unique readers
+// stand in for views so refreshes cannot push a post up the rankings.
+type Weights map[analytics.Metric]float64
+
+// DefaultWeights favour the engagement that takes the most effort
+var DefaultWeights = Weights{
+	analytics.MetricReaders:  1,
+	analytics.MetricLikes:    3,
+	analytics.MetricComments: 5,
+	analytics.MetricShares:   8,
+}
+
+// Score is the weighted engagement in c
+func (w Weights) Score(c analytics.Counts) float64 {
+	score := 0.0
+	for m, weight := range w {
+		score += weight * float64(c.Get(m))
+	}
+	return score
+}
+
+// Defaults for Settings left at zero
+const (
+	DefaultHalfLife = 24 * time.Hour
+	DefaultLookback = 7 * 24 * time.Hour
+)
+
+// Settings tune the trending score
+type Settings struct {
+	HalfLife time.Duration // Engagement this old counts half as much as engagement now
+	Lookback time.Duration // Engagement older than this is ignored
+	Weights  Weights
+}
+
+// Source reads the hourly analytics buckets that started at or after from
+type Source interface {
+	ForEachBucket(ctx context.Context, g analytics.Granularity, from time.Time, fn func(*analytics.Bucket) error) error
+}
+
+// Store saves the scores on the posts, clearing the score of every other post
+type Store interface {
+	SetTrendingScores(ctx context.Context, scores map[primitive.ObjectID]float64) error
+}
+
+// Ranker recomputes trending scores
+type Ranker struct {
+	settings Settings
+	source   Source
+	store    Store
+}
+
+func NewRanker(settings Settings, source Source, store Store) *Ranker {
+	if settings.HalfLife <= 0 {
+		settings.HalfLife = DefaultHalfLife
+	}
+	if settings.Lookback <= 0 {
+		settings.Lookback = DefaultLookback
+	}
+	if settings.Weights == nil {
+		settings.Weights = DefaultWeights
+	}
+	return &Ranker{settings: settings, source: source, store: store}
+}
+
+// Recompute scores every post with engagement in the lookback period and
+// stores the scores, returning how many posts are trending
+func (r *Ranker) Recompute(ctx context.Context, now time.Time) (int, error) {
+	scores := map[primitive.ObjectID]float64{}
+	err := r.source.ForEachBucket(ctx, analytics.Hourly, now.Add(-r.settings.Lookback), func(b *analytics.Bucket) error {
+		if s := r.Score(b.Counts, now.Sub(b.Start)); s > 0 {
+			scores[b.BlogID] += s
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.store.SetTrendingScores(ctx, scores); err != nil {
+		return 0, err
+	}
+	return len(scores), nil
+}
+
+// Score is the engagement in one bucket, decayed by its age
+func (r *Ranker) Score(c analytics.Counts, age time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	decay := math.Exp2(-age.Hours() / r.settings.HalfLife.Hours())
+	return r.settings.Weights.Score(c) * decay
+}
@@ -0,0 +1,68 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"razorblog-backend/internal/models/analytics"
+)
+
+type bucketList []*analytics.Bucket
+
+func (l bucketList) ForEachBucket(ctx context.Context, g analytics.Granularity, from time.Time, fn func(*analytics.Bucket) error) error {
+	for _, b := range l {
+		if b.Granularity == g && !b.Start.Before(from) {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type scoreStore map[primitive.ObjectID]float64
+
+func (s scoreStore) SetTrendingScores(ctx context.Context, scores map[primitive.ObjectID]float64) error {
+	for id := range s {
+		delete(s, id)
+	}
+	for id, score := range scores {
+		s[id] = score
+	}
+	return nil
+}
+
+func TestScoreDecays(t *testing.T) {
+	r := NewRanker(Settings{HalfLife: 24 * time.Hour}, nil, nil)
+	c := analytics.Counts{Readers: 10, Likes: 2}
+
+	assert.InDelta(t, 16.0, r.Score(c, 0), 1e-9)
+	assert.InDelta(t, 8.0, r.Score(c, 24*time.Hour), 1e-9)
+	assert.InDelta(t, 4.0, r.Score(c, 48*time.Hour), 1e-9)
+}
+
+func TestRecompute(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	fresh, stale, old := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	hourly := func(id primitive.ObjectID, age time.Duration, c analytics.Counts) *analytics.Bucket {
+		return &analytics.Bucket{BlogID: id, Granularity: analytics.Hourly, Start: now.Add(-age), Counts: c}
+	}
+
+	source := bucketList{
+		hourly(fresh, time.Hour, analytics.Counts{Readers: 20, Shares: 1}),
+		hourly(stale, 72*time.Hour, analytics.Counts{Readers: 100, Comments: 4}),
+		hourly(old, 30*24*time.Hour, analytics.Counts{Readers: 1000}),
+		{BlogID: old, Granularity: analytics.Daily, Start: now, Counts: analytics.Counts{Readers: 1000}},
+	}
+	store := scoreStore{old: 99}
+
+	n, err := NewRanker(Settings{}, source, store).Recompute(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Greater(t, store[fresh], store[stale])
+	assert.NotContains(t, store, old)
+}